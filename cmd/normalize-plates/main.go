@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"be-parkir/internal/config"
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// normalize-plates rewrites parking_sessions.plat_nomor in TNKB form ("BG 1234 AB") so plate
+// lookups match sessions recorded before plates were normalized at the API.
+// It only reports by default; pass -apply to update the rows.
+func main() {
+	apply := flag.Bool("apply", false, "rewrite plates instead of only reporting them")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	logger.SetLevel(logrus.InfoLevel)
+
+	db, err := repository.NewPostgresDB(cfg.Database)
+	if err != nil {
+		logger.Fatal("Failed to connect to database:", err)
+	}
+
+	sessionRepo := repository.NewParkingSessionRepository(db)
+
+	plates, err := sessionRepo.GetDistinctPlatNomors()
+	if err != nil {
+		logger.Fatal("Failed to list session plates:", err)
+	}
+
+	changed, invalid := 0, 0
+	var updated int64
+	for _, plate := range plates {
+		normalized, err := entities.NormalizePlatNomor(plate)
+		if err != nil {
+			// Left as typed; a jukir or admin has to correct these by hand
+			logger.WithField("plat_nomor", plate).Warn(err.Error())
+			invalid++
+			continue
+		}
+		if normalized == plate {
+			continue
+		}
+		changed++
+
+		entry := logger.WithFields(logrus.Fields{"plat_nomor": plate, "normalized": normalized})
+		if !*apply {
+			entry.Info("Plate is not in TNKB form")
+			continue
+		}
+		rows, err := sessionRepo.RenamePlatNomor(plate, normalized)
+		if err != nil {
+			entry.Error("Failed to normalize plate:", err)
+			continue
+		}
+		entry.WithField("sessions", rows).Info("Normalized plate")
+		updated += rows
+	}
+
+	logger.WithFields(logrus.Fields{
+		"plates":           len(plates),
+		"not_normalized":   changed,
+		"invalid":          invalid,
+		"sessions_updated": updated,
+		"applied":          *apply,
+	}).Info("Plate normalization finished")
+}
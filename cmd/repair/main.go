@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"be-parkir/internal/config"
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+
+	"github.com/sirupsen/logrus"
+)
+
+// repair finds rows left inconsistent by session/payment writes that were not atomic.
+// It only reports by default; pass -apply to fix what can be fixed safely.
+func main() {
+	apply := flag.Bool("apply", false, "fix orphaned rows instead of only reporting them")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Failed to load config:", err)
+	}
+
+	logger := logrus.New()
+	logger.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	logger.SetLevel(logrus.InfoLevel)
+
+	db, err := repository.NewPostgresDB(cfg.Database)
+	if err != nil {
+		logger.Fatal("Failed to connect to database:", err)
+	}
+
+	paymentRepo := repository.NewPaymentRepository(db)
+
+	// Paid sessions without a payment row: recreate the payment from the session
+	sessions, err := paymentRepo.GetPaidSessionsWithoutPayment()
+	if err != nil {
+		logger.Fatal("Failed to find paid sessions without payment:", err)
+	}
+	fixed := 0
+	for _, session := range sessions {
+		amount := 0.0
+		if session.TotalCost != nil {
+			amount = *session.TotalCost
+		}
+		confirmedAt := session.CheckinTime
+		if session.CheckoutTime != nil {
+			confirmedAt = *session.CheckoutTime
+		}
+
+		entry := logger.WithFields(logrus.Fields{"session_id": session.ID, "amount": amount})
+		if !*apply {
+			entry.Info("Paid session has no payment")
+			continue
+		}
+
+		payment := &entities.Payment{
+			SessionID:     session.ID,
+			Amount:        amount,
+			PaymentMethod: entities.PaymentMethodCash,
+			ConfirmedBy:   session.JukirID,
+			ConfirmedAt:   &confirmedAt,
+			Status:        entities.PaymentStatusPaid,
+		}
+		if err := paymentRepo.Create(payment); err != nil {
+			entry.Error("Failed to create payment:", err)
+			continue
+		}
+		entry.WithField("payment_id", payment.ID).Info("Created missing payment")
+		fixed++
+	}
+
+	// Payments whose session is gone: soft delete them so they drop out of revenue reports
+	orphans, err := paymentRepo.GetPaymentsWithoutSession()
+	if err != nil {
+		logger.Fatal("Failed to find payments without session:", err)
+	}
+	for _, payment := range orphans {
+		entry := logger.WithFields(logrus.Fields{"payment_id": payment.ID, "session_id": payment.SessionID, "amount": payment.Amount})
+		if !*apply {
+			entry.Info("Payment has no session")
+			continue
+		}
+		if err := paymentRepo.Delete(payment.ID); err != nil {
+			entry.Error("Failed to delete payment:", err)
+			continue
+		}
+		entry.Info("Deleted orphaned payment")
+		fixed++
+	}
+
+	// Amount mismatches are reported only: manual revenue adjustments create them on purpose
+	mismatches, err := paymentRepo.GetCompletedSessionAmountMismatches()
+	if err != nil {
+		logger.Fatal("Failed to find payment amount mismatches:", err)
+	}
+	for _, payment := range mismatches {
+		totalCost := 0.0
+		if payment.Session.TotalCost != nil {
+			totalCost = *payment.Session.TotalCost
+		}
+		logger.WithFields(logrus.Fields{
+			"payment_id": payment.ID,
+			"session_id": payment.SessionID,
+			"amount":     payment.Amount,
+			"total_cost": totalCost,
+		}).Warn("Payment amount differs from session total cost")
+	}
+
+	logger.WithFields(logrus.Fields{
+		"paid_without_payment":    len(sessions),
+		"payment_without_session": len(orphans),
+		"amount_mismatches":       len(mismatches),
+		"fixed":                   fixed,
+		"applied":                 *apply,
+	}).Info("Repair finished")
+}
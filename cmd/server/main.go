@@ -8,7 +8,9 @@ import (
 	"be-parkir/internal/delivery/http"
 	"be-parkir/internal/delivery/http/handler"
 	"be-parkir/internal/delivery/http/middleware"
+	"be-parkir/internal/paygate"
 	"be-parkir/internal/repository"
+	"be-parkir/internal/scheduler"
 	"be-parkir/internal/storage"
 	"be-parkir/internal/usecase"
 
@@ -66,11 +68,61 @@ func main() {
 	areaRepo := repository.NewParkingAreaRepository(db)
 	sessionRepo := repository.NewParkingSessionRepository(db)
 	paymentRepo := repository.NewPaymentRepository(db)
+	tariffRepo := repository.NewTariffPlanRepository(db)
+	holidayRepo := repository.NewHolidayRepository(db)
+	vehicleTypeRepo := repository.NewVehicleTypeRepository(db)
+	occupancyRepo := repository.NewOccupancyRepository(db, redisClient)
+	syncRepo := repository.NewSyncRecordRepository(db)
+	voidRepo := repository.NewSessionVoidRepository(db)
+	overstayRepo := repository.NewOverstayRepository(db)
+	passRepo := repository.NewParkingPassRepository(db)
+	attachmentRepo := repository.NewSessionAttachmentRepository(db)
+	reservationRepo := repository.NewReservationRepository(db)
+	vehicleRepo := repository.NewCustomerVehicleRepository(db)
+	receiptRepo := repository.NewSessionReceiptRepository(db)
+	hoursRepo := repository.NewOperatingHoursRepository(db)
+	depositRepo := repository.NewCashDepositRepository(db)
+	uow := repository.NewUnitOfWork(db)
+
+	// Sync occupancy counters with active sessions and open reservations
+	if err := occupancyRepo.ReconcileAll(); err != nil {
+		logger.Warn("Failed to reconcile occupancy counters:", err)
+	}
+	if backfilled, err := areaRepo.BackfillGeohashes(); err != nil {
+		logger.Warn("Failed to backfill parking area geohashes:", err)
+	} else if backfilled > 0 {
+		logger.Infof("Backfilled geohash for %d parking areas", backfilled)
+	}
 
 	// Initialize Event Manager for SSE
 	eventManager := usecase.NewEventManager()
 	logger.Info("Event Manager initialized for SSE")
 
+	// Initialize MinIO storage client
+	minioClient, err := storage.NewMinIOClient(cfg.MinIO)
+	if err != nil {
+		logger.Fatal("Failed to initialize MinIO:", err)
+	}
+
+	// Initialize the online payment gateway
+	var paymentGateway paygate.Gateway
+	mockPayments := false
+	switch provider := viper.GetString("PAYMENT_GATEWAY"); provider {
+	case "":
+		logger.Info("Online payments disabled")
+	case "mock":
+		// Callbacks are signed with their own key, never the JWT key
+		mockGateway, err := paygate.NewMockGateway(viper.GetString("PAYMENT_MOCK_FILE"), viper.GetString("PAYMENT_WEBHOOK_SECRET"))
+		if err != nil {
+			logger.Fatal("Failed to initialize mock payment gateway:", err)
+		}
+		paymentGateway = mockGateway
+		mockPayments = true
+		logger.Warn("Using the mock payment gateway; payments are not real")
+	default:
+		logger.Fatalf("Unknown payment gateway %q", provider)
+	}
+
 	// Initialize use cases
 	authUC := usecase.NewAuthUsecase(userRepo, redisClient, usecase.JWTConfig{
 		SecretKey:     cfg.JWT.SecretKey,
@@ -78,18 +130,83 @@ func main() {
 		RefreshExpiry: cfg.JWT.RefreshExpiry,
 	})
 	userUC := usecase.NewUserUsecase(userRepo)
-	jukirUC := usecase.NewJukirUsecase(jukirRepo, areaRepo, sessionRepo, paymentRepo, eventManager)
-	parkingUC := usecase.NewParkingUsecase(sessionRepo, areaRepo, userRepo, jukirRepo, paymentRepo, eventManager)
-	adminUC := usecase.NewAdminUsecase(userRepo, jukirRepo, areaRepo, sessionRepo, paymentRepo)
+	jukirUC := usecase.NewJukirUsecase(jukirRepo, areaRepo, sessionRepo, paymentRepo, tariffRepo, holidayRepo, vehicleTypeRepo, voidRepo, eventManager)
+	parkingUC := usecase.NewParkingUsecase(sessionRepo, areaRepo, userRepo, jukirRepo, paymentRepo, tariffRepo, holidayRepo, vehicleTypeRepo, occupancyRepo, syncRepo, passRepo, reservationRepo, hoursRepo, vehicleRepo, uow, eventManager, usecase.TicketConfig{
+		SecretKey:   cfg.Ticket.SecretKey,
+		Expiry:      cfg.Ticket.Expiry,
+		LegacyUntil: cfg.Ticket.LegacyUntil,
+	}, usecase.GeofenceConfig{
+		DefaultRadius:  viper.GetFloat64("GEOFENCE_DEFAULT_RADIUS"),
+		AccuracyMargin: viper.GetFloat64("GEOFENCE_ACCURACY_MARGIN"),
+	}, usecase.PaymentConfig{
+		Gateway:      paymentGateway,
+		ChargeExpiry: viper.GetDuration("PAYMENT_CHARGE_EXPIRY"),
+	})
+	adminUC := usecase.NewAdminUsecase(userRepo, jukirRepo, areaRepo, sessionRepo, paymentRepo, tariffRepo, holidayRepo, vehicleTypeRepo, occupancyRepo, voidRepo, passRepo, attachmentRepo, hoursRepo, uow)
+	overstayUC := usecase.NewOverstayUsecase(areaRepo, overstayRepo, tariffRepo, holidayRepo, occupancyRepo, hoursRepo, uow, eventManager)
+	passUC := usecase.NewPassUsecase(passRepo, areaRepo, vehicleTypeRepo, uow)
+	reservationUC := usecase.NewReservationUsecase(reservationRepo, areaRepo, vehicleTypeRepo, occupancyRepo, usecase.ReservationConfig{
+		GracePeriod: viper.GetDuration("RESERVATION_GRACE_PERIOD"),
+		MaxAdvance:  viper.GetDuration("RESERVATION_MAX_ADVANCE"),
+	})
+	operatingHoursUC := usecase.NewOperatingHoursUsecase(areaRepo, hoursRepo)
+	cashDepositUC := usecase.NewCashDepositUsecase(depositRepo, jukirRepo, paymentRepo)
+	receiptUC := usecase.NewReceiptUsecase(receiptRepo, sessionRepo, paymentRepo, tariffRepo, holidayRepo, minioClient, usecase.ReceiptConfig{
+		BaseURL: viper.GetString("RECEIPT_BASE_URL"),
+	})
 
-	// Initialize MinIO storage client
-	minioClient, err := storage.NewMinIOClient(cfg.MinIO)
-	if err != nil {
-		logger.Fatal("Failed to initialize MinIO:", err)
-	}
+	// Start background jobs
+	jobs := scheduler.New(logger)
+	jobs.Add(scheduler.Job{
+		Name:     "overstay",
+		Interval: viper.GetDuration("OVERSTAY_CHECK_INTERVAL"),
+		Run: func() error {
+			result, err := overstayUC.CheckOverstays()
+			if err != nil {
+				return err
+			}
+			if result.Alerted > 0 || result.AutoCompleted > 0 || result.Failed > 0 {
+				logger.WithFields(logrus.Fields{
+					"alerted":        result.Alerted,
+					"auto_completed": result.AutoCompleted,
+					"failed":         result.Failed,
+				}).Info("Overstay check finished")
+			}
+			return nil
+		},
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "reservations",
+		Interval: viper.GetDuration("RESERVATION_CHECK_INTERVAL"),
+		Run: func() error {
+			expired, err := reservationUC.ExpireReservations()
+			if err != nil {
+				return err
+			}
+			if expired > 0 {
+				logger.WithField("expired", expired).Info("Expired unused reservations")
+			}
+			return nil
+		},
+	})
+	jobs.Add(scheduler.Job{
+		Name:     "operating-hours",
+		Interval: viper.GetDuration("OPERATING_HOURS_CHECK_INTERVAL"),
+		Run: func() error {
+			changed, err := operatingHoursUC.SyncOperationalStatus()
+			if err != nil {
+				return err
+			}
+			if changed > 0 {
+				logger.WithField("changed", changed).Info("Updated operational status from operating hours")
+			}
+			return nil
+		},
+	})
+	jobs.Start()
 
 	// Initialize HTTP handlers
-	handlers := handler.NewHandlers(authUC, userUC, jukirUC, parkingUC, adminUC, eventManager, logger, minioClient)
+	handlers := handler.NewHandlers(authUC, userUC, jukirUC, parkingUC, adminUC, overstayUC, passUC, reservationUC, receiptUC, operatingHoursUC, cashDepositUC, eventManager, logger, minioClient)
 
 	// Setup middleware configurations
 	apiKeyConfig := &middleware.APIKeyConfig{
@@ -101,19 +218,24 @@ func main() {
 	corsConfig := &middleware.CORSConfig{
 		AllowOrigins:     []string{viper.GetString("CORS_ALLOW_ORIGINS")},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "X-API-Key"},
-		ExposeHeaders:    []string{"Content-Length", "Content-Type", "X-Total-Count"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "X-API-Key", middleware.IdempotencyKeyHeader},
+		ExposeHeaders:    []string{"Content-Length", "Content-Type", "X-Total-Count", middleware.IdempotentReplayedHeader},
 		AllowCredentials: viper.GetBool("CORS_ALLOW_CREDENTIALS"),
 		MaxAge:           viper.GetInt("CORS_MAX_AGE"),
 	}
 
+	idempotencyConfig := &middleware.IdempotencyConfig{
+		Store: repository.NewIdempotencyRepository(redisClient),
+		TTL:   viper.GetDuration("IDEMPOTENCY_TTL"),
+	}
+
 	// Setup routes
 	router := gin.Default()
 	http.SetupRoutes(router, handlers, usecase.JWTConfig{
 		SecretKey:     cfg.JWT.SecretKey,
 		AccessExpiry:  cfg.JWT.AccessExpiry,
 		RefreshExpiry: cfg.JWT.RefreshExpiry,
-	}, apiKeyConfig, corsConfig)
+	}, apiKeyConfig, corsConfig, idempotencyConfig, mockPayments)
 
 	// Start server
 	logger.Info("Starting server on port :8080")
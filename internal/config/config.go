@@ -14,6 +14,7 @@ type Config struct {
 	Redis    RedisConfig
 	JWT      JWTConfig
 	MinIO    MinIOConfig
+	Ticket   TicketConfig
 }
 
 type ServerConfig struct {
@@ -45,6 +46,12 @@ type JWTConfig struct {
 	RefreshExpiry time.Duration
 }
 
+type TicketConfig struct {
+	SecretKey   string
+	Expiry      time.Duration
+	LegacyUntil time.Time // bare session IDs are accepted until this time; zero = never
+}
+
 type MinIOConfig struct {
 	Endpoint  string
 	AccessKey string
@@ -78,10 +85,24 @@ func Load() (*Config, error) {
 	// JWT_SECRET must be provided via environment variable
 	viper.SetDefault("JWT_ACCESS_EXPIRY", "15m")
 	viper.SetDefault("JWT_REFRESH_EXPIRY", "7d")
+	// TICKET_SECRET falls back to JWT_SECRET when not set
+	viper.SetDefault("TICKET_EXPIRY", "720h")
 	viper.SetDefault("API_KEY_REQUIRED", "true")
 	viper.SetDefault("API_KEY_HEADER", "X-API-Key")
 	viper.SetDefault("CORS_ALLOW_CREDENTIALS", "true")
 	viper.SetDefault("CORS_MAX_AGE", "86400")
+	viper.SetDefault("IDEMPOTENCY_TTL", "24h")
+	viper.SetDefault("OVERSTAY_CHECK_INTERVAL", "5m")
+	viper.SetDefault("RESERVATION_CHECK_INTERVAL", "1m")
+	viper.SetDefault("OPERATING_HOURS_CHECK_INTERVAL", "1m")
+	viper.SetDefault("RESERVATION_GRACE_PERIOD", "15m")
+	viper.SetDefault("RESERVATION_MAX_ADVANCE", "2h")
+	viper.SetDefault("RECEIPT_BASE_URL", "http://localhost:8080")
+	viper.SetDefault("GEOFENCE_DEFAULT_RADIUS", 300)
+	viper.SetDefault("GEOFENCE_ACCURACY_MARGIN", 20)
+	viper.SetDefault("PAYMENT_GATEWAY", "")
+	viper.SetDefault("PAYMENT_MOCK_FILE", "data/mock_payments.json")
+	viper.SetDefault("PAYMENT_CHARGE_EXPIRY", "15m")
 	// MinIO defaults
 	viper.SetDefault("MINIO_ENDPOINT", "localhost:9000")
 	viper.SetDefault("MINIO_ACCESS_KEY", "miniokey")
@@ -140,6 +161,26 @@ func Load() (*Config, error) {
 			Bucket:    viper.GetString("MINIO_BUCKET"),
 			UseSSL:    viper.GetBool("MINIO_USE_SSL"),
 		},
+		Ticket: TicketConfig{
+			SecretKey: viper.GetString("TICKET_SECRET"),
+			Expiry:    viper.GetDuration("TICKET_EXPIRY"),
+		},
+	}
+
+	if config.Ticket.SecretKey == "" {
+		config.Ticket.SecretKey = config.JWT.SecretKey
+	}
+	// Transition window for clients that still send bare session IDs (YYYY-MM-DD, GMT+7)
+	if legacyUntil := viper.GetString("TICKET_LEGACY_UNTIL"); legacyUntil != "" {
+		loc, err := time.LoadLocation("Asia/Jakarta")
+		if err != nil {
+			loc = time.FixedZone("GMT+7", 7*60*60)
+		}
+		parsed, err := time.ParseInLocation("2006-01-02", legacyUntil, loc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TICKET_LEGACY_UNTIL: %w", err)
+		}
+		config.Ticket.LegacyUntil = parsed
 	}
 
 	// Validate required fields
@@ -3,10 +3,13 @@ package handler
 import (
 	"be-parkir/internal/domain/entities"
 	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"path"
 	"strconv"
 	"strings"
 	"time"
@@ -67,7 +70,7 @@ func parseDateFilter(c *gin.Context) (*time.Time, *time.Time, error) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param vehicle_type query string false "Filter by vehicle type (mobil/motor)"
+// @Param vehicle_type query string false "Filter by vehicle type code (mobil, motor, ...)"
 // @Param start_date query string false "Start date (YYYY-MM-DD)"
 // @Param end_date query string false "End date (YYYY-MM-DD)"
 // @Success 200 {object} map[string]interface{}
@@ -78,7 +81,7 @@ func (h *Handlers) GetAdminOverview(c *gin.Context) {
 	vehicleType := c.Query("vehicle_type")
 
 	var vehicleTypePtr *string
-	if vehicleType != "" && (vehicleType == "mobil" || vehicleType == "motor") {
+	if vehicleType != "" {
 		vehicleTypePtr = &vehicleType
 	}
 
@@ -151,7 +154,7 @@ func (h *Handlers) GetJukirs(c *gin.Context) {
 		dateRange := c.Query("date_range")
 
 		var vehicleTypePtr *string
-		if vehicleType != "" && (vehicleType == "mobil" || vehicleType == "motor") {
+		if vehicleType != "" {
 			vehicleTypePtr = &vehicleType
 		}
 
@@ -589,6 +592,324 @@ func (h *Handlers) GetAllSessions(c *gin.Context) {
 	})
 }
 
+// GetVoidRequests godoc
+// @Summary Get void requests
+// @Description List session void requests submitted by jukirs, newest first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Param status query string false "Request status (pending, approved, rejected)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/void-requests [get]
+func (h *Handlers) GetVoidRequests(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	var status *entities.VoidStatus
+	if statusStr := c.Query("status"); statusStr != "" {
+		s := entities.VoidStatus(statusStr)
+		status = &s
+	}
+
+	requests, count, err := h.AdminUC.GetVoidRequests(status, limit, offset)
+	if err != nil {
+		h.Logger.Error("Failed to get void requests:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Void requests retrieved successfully",
+		"data":    requests,
+		"meta": gin.H{
+			"pagination": gin.H{
+				"limit":  limit,
+				"offset": offset,
+				"total":  count,
+			},
+		},
+	})
+}
+
+// GetSessionDetail godoc
+// @Summary Get session detail
+// @Description Get a parking session with its payment and the vehicle photos taken at manual check-in and checkout. Photo urls point to the file download endpoint.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/sessions/{id} [get]
+func (h *Handlers) GetSessionDetail(c *gin.Context) {
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid session ID",
+		})
+		return
+	}
+
+	session, err := h.AdminUC.GetSessionDetail(uint(sessionID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Session retrieved successfully",
+		"data":    session,
+	})
+}
+
+// GetSessionVoidRequests godoc
+// @Summary Get void requests of a session
+// @Description Get every void request submitted for a session, including who reviewed it
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/sessions/{id}/void [get]
+func (h *Handlers) GetSessionVoidRequests(c *gin.Context) {
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid session ID",
+		})
+		return
+	}
+
+	requests, err := h.AdminUC.GetSessionVoidRequests(uint(sessionID))
+	if err != nil {
+		h.Logger.Error("Failed to get session void requests:", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Void requests retrieved successfully",
+		"data":    requests,
+	})
+}
+
+// ApproveSessionVoid godoc
+// @Summary Approve a session void
+// @Description Approve the pending void request of a session. The session is cancelled, its payment is refunded, and it no longer counts towards revenue or reports.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Param request body entities.ReviewVoidRequest false "Review note"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/admin/sessions/{id}/void/approve [post]
+func (h *Handlers) ApproveSessionVoid(c *gin.Context) {
+	sessionID, adminID, req, ok := h.bindVoidReview(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.AdminUC.ApproveSessionVoid(sessionID, adminID, req)
+	if err != nil {
+		h.Logger.Error("Failed to approve void request:", err)
+		c.JSON(conflictErrorStatus(err, http.StatusBadRequest), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Session voided successfully",
+		"data":    request,
+	})
+}
+
+// RejectSessionVoid godoc
+// @Summary Reject a session void
+// @Description Reject the pending void request of a session. The session is left unchanged.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Param request body entities.ReviewVoidRequest false "Review note"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/sessions/{id}/void/reject [post]
+func (h *Handlers) RejectSessionVoid(c *gin.Context) {
+	sessionID, adminID, req, ok := h.bindVoidReview(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.AdminUC.RejectSessionVoid(sessionID, adminID, req)
+	if err != nil {
+		h.Logger.Error("Failed to reject void request:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Void request rejected",
+		"data":    request,
+	})
+}
+
+// bindVoidReview reads the session ID, the reviewing admin and the optional review note.
+// It writes the error response itself and returns ok=false when the request is invalid.
+func (h *Handlers) bindVoidReview(c *gin.Context) (uint, uint, *entities.ReviewVoidRequest, bool) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User not authenticated",
+		})
+		return 0, 0, nil, false
+	}
+
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid session ID",
+		})
+		return 0, 0, nil, false
+	}
+
+	// The review note is optional, so an empty body is allowed
+	var req entities.ReviewVoidRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return 0, 0, nil, false
+	}
+
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return 0, 0, nil, false
+	}
+
+	return uint(sessionID), userID.(uint), &req, true
+}
+
+// GetOverstayEvents godoc
+// @Summary Get overstay events
+// @Description Audit log of sessions the overstay scheduler alerted on or closed automatically, newest first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Param area_id query int false "Area ID"
+// @Param action query string false "Action (alert, auto_completed)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/overstays [get]
+func (h *Handlers) GetOverstayEvents(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	var areaID *uint
+	if areaIDStr := c.Query("area_id"); areaIDStr != "" {
+		if id, err := strconv.ParseUint(areaIDStr, 10, 32); err == nil {
+			v := uint(id)
+			areaID = &v
+		}
+	}
+
+	var action *entities.OverstayAction
+	if actionStr := c.Query("action"); actionStr != "" {
+		a := entities.OverstayAction(actionStr)
+		action = &a
+	}
+
+	events, count, err := h.OverstayUC.GetOverstayEvents(areaID, action, limit, offset)
+	if err != nil {
+		h.Logger.Error("Failed to get overstay events:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Overstay events retrieved successfully",
+		"data":    events,
+		"meta": gin.H{
+			"pagination": gin.H{
+				"limit":  limit,
+				"offset": offset,
+				"total":  count,
+			},
+		},
+	})
+}
+
 // CreateParkingArea godoc
 // @Summary Create parking area
 // @Description Create a new parking area
@@ -607,6 +928,13 @@ func (h *Handlers) GetAllSessions(c *gin.Context) {
 // @Param max_motor formData integer false "Max Motor"
 // @Param status_operasional formData string true "Status Operasional (buka/tutup/maintenance)"
 // @Param jenis_area formData string true "Jenis Area (indoor/outdoor/mix)"
+// @Param capacity_policy formData string false "Check-in when full: reject (default) or overflow"
+// @Param max_duration formData integer false "Maximum parking duration in minutes before a session counts as overstay"
+// @Param closing_time formData string false "Closing time (HH:MM, WIB); sessions still active after it count as overstay"
+// @Param overstay_policy formData string false "Overstay handling: alert (default) or auto_complete"
+// @Param lost_ticket_penalty formData number false "Penalty charged on top of the parking fee when a customer loses their ticket"
+// @Param tariff_schedules formData string false "JSON array of night/weekend/holiday schedules"
+// @Param vehicle_rates formData string false "JSON array of rates and capacities for other vehicle types"
 // @Param image formData file false "Area image"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
@@ -727,6 +1055,45 @@ func (h *Handlers) CreateParkingArea(c *gin.Context) {
 			jenisArea = "outdoor"
 		}
 		req.JenisArea = entities.JenisArea(jenisArea)
+		req.CapacityPolicy = entities.CapacityPolicy(c.PostForm("capacity_policy"))
+		if md := c.PostForm("max_duration"); md != "" {
+			if v, err := strconv.Atoi(md); err == nil {
+				req.MaxDuration = &v
+			}
+		}
+		if ct := c.PostForm("closing_time"); ct != "" {
+			req.ClosingTime = &ct
+		}
+		req.OverstayPolicy = entities.OverstayPolicy(c.PostForm("overstay_policy"))
+		if ltp := c.PostForm("lost_ticket_penalty"); ltp != "" {
+			if v, err := strconv.ParseFloat(ltp, 64); err == nil {
+				req.LostTicketPenalty = v
+			}
+		}
+		if geofence := c.PostForm("geofence"); geofence != "" {
+			if err := json.Unmarshal([]byte(geofence), &req.Geofence); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid geofence"})
+				return
+			}
+		}
+		if gr := c.PostForm("geofence_radius"); gr != "" {
+			if v, err := strconv.ParseFloat(gr, 64); err == nil {
+				req.GeofenceRadius = &v
+			}
+		}
+
+		if schedules := c.PostForm("tariff_schedules"); schedules != "" {
+			if err := json.Unmarshal([]byte(schedules), &req.TariffSchedules); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid tariff_schedules"})
+				return
+			}
+		}
+		if rates := c.PostForm("vehicle_rates"); rates != "" {
+			if err := json.Unmarshal([]byte(rates), &req.VehicleRates); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid vehicle_rates"})
+				return
+			}
+		}
 	}
 
 	// Validate request
@@ -1749,14 +2116,20 @@ func (h *Handlers) DownloadFile(c *gin.Context) {
 	parts := strings.Split(filePath, "/")
 	filename := parts[len(parts)-1]
 
+	// Exports are spreadsheets unless the extension says otherwise (CSV, session photos)
+	contentType := mime.TypeByExtension(path.Ext(filename))
+	if contentType == "" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+
 	// Set headers for file download
 	c.Header("Content-Description", "File Transfer")
 	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
-	c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	c.Header("Content-Type", contentType)
 	c.Header("Content-Length", fmt.Sprintf("%d", size))
 
 	// Stream file to response
-	c.DataFromReader(http.StatusOK, size, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", reader, nil)
+	c.DataFromReader(http.StatusOK, size, contentType, reader, nil)
 }
 
 // UpdateParkingArea godoc
@@ -1849,6 +2222,53 @@ func (h *Handlers) UpdateParkingArea(c *gin.Context) {
 			jaVal := entities.JenisArea(ja)
 			req.JenisArea = &jaVal
 		}
+		if cp := c.PostForm("capacity_policy"); cp != "" {
+			cpVal := entities.CapacityPolicy(cp)
+			req.CapacityPolicy = &cpVal
+		}
+		if md := c.PostForm("max_duration"); md != "" {
+			if v, err := strconv.Atoi(md); err == nil {
+				req.MaxDuration = &v
+			}
+		}
+		if ct, ok := c.GetPostForm("closing_time"); ok {
+			req.ClosingTime = &ct
+		}
+		if op := c.PostForm("overstay_policy"); op != "" {
+			opVal := entities.OverstayPolicy(op)
+			req.OverstayPolicy = &opVal
+		}
+		if ltp := c.PostForm("lost_ticket_penalty"); ltp != "" {
+			if v, err := strconv.ParseFloat(ltp, 64); err == nil {
+				req.LostTicketPenalty = &v
+			}
+		}
+		if geofence := c.PostForm("geofence"); geofence != "" {
+			if err := json.Unmarshal([]byte(geofence), &req.Geofence); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid geofence"})
+				return
+			}
+		}
+		if cg := c.PostForm("clear_geofence"); cg != "" {
+			req.ClearGeofence, _ = strconv.ParseBool(cg)
+		}
+		if gr := c.PostForm("geofence_radius"); gr != "" {
+			if v, err := strconv.ParseFloat(gr, 64); err == nil {
+				req.GeofenceRadius = &v
+			}
+		}
+		if schedules := c.PostForm("tariff_schedules"); schedules != "" {
+			if err := json.Unmarshal([]byte(schedules), &req.TariffSchedules); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid tariff_schedules"})
+				return
+			}
+		}
+		if rates := c.PostForm("vehicle_rates"); rates != "" {
+			if err := json.Unmarshal([]byte(rates), &req.VehicleRates); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"success": false, "message": "invalid vehicle_rates"})
+				return
+			}
+		}
 	}
 
 	// Handle optional image upload
@@ -1991,6 +2411,7 @@ func (h *Handlers) GetAllJukirsRevenue(c *gin.Context) {
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/v1/admin/jukirs/manual-revenue [post]
 func (h *Handlers) AddManualRevenue(c *gin.Context) {
@@ -2020,7 +2441,7 @@ func (h *Handlers) AddManualRevenue(c *gin.Context) {
 	response, err := h.AdminUC.AddManualRevenue(&req)
 	if err != nil {
 		h.Logger.Error("Failed to add manual revenue:", err)
-		c.JSON(http.StatusBadRequest, gin.H{
+		c.JSON(conflictErrorStatus(err, http.StatusBadRequest), gin.H{
 			"success": false,
 			"message": err.Error(),
 		})
@@ -2041,7 +2462,7 @@ func (h *Handlers) AddManualRevenue(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param vehicle_type query string false "Filter by vehicle type (mobil/motor)"
+// @Param vehicle_type query string false "Filter by vehicle type code (mobil, motor, ...)"
 // @Param start_date query string false "Start date (DD-MM-YYYY)"
 // @Param end_date query string false "End date (DD-MM-YYYY)"
 // @Param regional query string false "Filter by regional"
@@ -2054,7 +2475,7 @@ func (h *Handlers) GetVehicleStatistics(c *gin.Context) {
 	regional := c.Query("regional")
 
 	var vehicleTypePtr *string
-	if vehicleType != "" && (vehicleType == "mobil" || vehicleType == "motor") {
+	if vehicleType != "" {
 		vehicleTypePtr = &vehicleType
 	}
 
@@ -2097,7 +2518,7 @@ func (h *Handlers) GetVehicleStatistics(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param vehicle_type query string false "Filter by vehicle type (mobil/motor)"
+// @Param vehicle_type query string false "Filter by vehicle type code (mobil, motor, ...)"
 // @Param start_date query string false "Start date (DD-MM-YYYY)"
 // @Param end_date query string false "End date (DD-MM-YYYY)"
 // @Param regional query string false "Filter by regional"
@@ -2110,7 +2531,7 @@ func (h *Handlers) GetTotalRevenue(c *gin.Context) {
 	regional := c.Query("regional")
 
 	var vehicleTypePtr *string
-	if vehicleType != "" && (vehicleType == "mobil" || vehicleType == "motor") {
+	if vehicleType != "" {
 		vehicleTypePtr = &vehicleType
 	}
 
@@ -2153,7 +2574,7 @@ func (h *Handlers) GetTotalRevenue(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param vehicle_type query string false "Filter by vehicle type (mobil/motor)"
+// @Param vehicle_type query string false "Filter by vehicle type code (mobil, motor, ...)"
 // @Param date_range query string false "Filter by date range (hari_ini, minggu_ini, bulan_ini)"
 // @Param export query string false "Export to Excel (true/false)"
 // @Success 200 {object} map[string]interface{}
@@ -2167,7 +2588,7 @@ func (h *Handlers) GetJukirsListWithRevenue(c *gin.Context) {
 	status := c.Query("status")
 
 	var vehicleTypePtr *string
-	if vehicleType != "" && (vehicleType == "mobil" || vehicleType == "motor") {
+	if vehicleType != "" {
 		vehicleTypePtr = &vehicleType
 	}
 
@@ -2211,7 +2632,7 @@ func (h *Handlers) GetJukirsListWithRevenue(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param vehicle_type query string false "Filter by vehicle type (mobil/motor)"
+// @Param vehicle_type query string false "Filter by vehicle type code (mobil, motor, ...)"
 // @Param start_date query string false "Start date (DD-MM-YYYY)"
 // @Param end_date query string false "End date (DD-MM-YYYY)"
 // @Param regional query string false "Filter by regional"
@@ -2224,7 +2645,7 @@ func (h *Handlers) GetChartDataDetailed(c *gin.Context) {
 	regional := c.Query("regional")
 
 	var vehicleTypePtr *string
-	if vehicleType != "" && (vehicleType == "mobil" || vehicleType == "motor") {
+	if vehicleType != "" {
 		vehicleTypePtr = &vehicleType
 	}
 
@@ -2645,3 +3066,1041 @@ func (h *Handlers) GetActivityLogs(c *gin.Context) {
 		"data":    response,
 	})
 }
+
+// GetAreaTariffs godoc
+// @Summary Get area tariff plans
+// @Description Get the progressive tariff plans configured for a parking area
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Area ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/areas/{id}/tariffs [get]
+func (h *Handlers) GetAreaTariffs(c *gin.Context) {
+	areaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid area ID",
+		})
+		return
+	}
+
+	response, err := h.AdminUC.GetAreaTariffs(uint(areaID))
+	if err != nil {
+		h.Logger.Error("Failed to get area tariffs:", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tariff plans retrieved successfully",
+		"data":    response,
+	})
+}
+
+// UpsertAreaTariff godoc
+// @Summary Create or update area tariff plan
+// @Description Set first-hour price, next-hour increment, daily cap and grace period for one vehicle type in a parking area
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Area ID"
+// @Param request body entities.UpsertTariffPlanRequest true "Tariff plan data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/areas/{id}/tariffs [put]
+func (h *Handlers) UpsertAreaTariff(c *gin.Context) {
+	areaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid area ID",
+		})
+		return
+	}
+
+	var req entities.UpsertTariffPlanRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.AdminUC.UpsertAreaTariff(uint(areaID), &req)
+	if err != nil {
+		h.Logger.Error("Failed to save area tariff:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tariff plan saved successfully",
+		"data":    response,
+	})
+}
+
+// DeleteAreaTariff godoc
+// @Summary Delete area tariff plan
+// @Description Remove the tariff plan for one vehicle type; the area falls back to its flat rate
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Area ID"
+// @Param vehicle_type path string true "Vehicle type code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/areas/{id}/tariffs/{vehicle_type} [delete]
+func (h *Handlers) DeleteAreaTariff(c *gin.Context) {
+	areaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid area ID",
+		})
+		return
+	}
+
+	vehicleType := entities.VehicleType(c.Param("vehicle_type"))
+	if err := h.AdminUC.DeleteAreaTariff(uint(areaID), vehicleType); err != nil {
+		h.Logger.Error("Failed to delete area tariff:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Tariff plan deleted successfully",
+	})
+}
+
+// GetVehicleTypes godoc
+// @Summary Get vehicle type registry
+// @Description Get every registered vehicle type, including inactive ones
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/vehicle-types [get]
+func (h *Handlers) GetVehicleTypes(c *gin.Context) {
+	response, err := h.AdminUC.GetVehicleTypes()
+	if err != nil {
+		h.Logger.Error("Failed to get vehicle types:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Vehicle types retrieved successfully",
+		"data":    response,
+	})
+}
+
+// CreateVehicleType godoc
+// @Summary Create vehicle type
+// @Description Register a new vehicle type (e.g. truk, bus, sepeda)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.CreateVehicleTypeRequest true "Vehicle type data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/vehicle-types [post]
+func (h *Handlers) CreateVehicleType(c *gin.Context) {
+	var req entities.CreateVehicleTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.AdminUC.CreateVehicleType(&req)
+	if err != nil {
+		h.Logger.Error("Failed to create vehicle type:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Vehicle type created successfully",
+		"data":    response,
+	})
+}
+
+// UpdateVehicleType godoc
+// @Summary Update vehicle type
+// @Description Rename, reorder, or deactivate a registered vehicle type
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Vehicle type code"
+// @Param request body entities.UpdateVehicleTypeRequest true "Vehicle type update data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/vehicle-types/{code} [put]
+func (h *Handlers) UpdateVehicleType(c *gin.Context) {
+	var req entities.UpdateVehicleTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	code := entities.VehicleType(c.Param("code"))
+	response, err := h.AdminUC.UpdateVehicleType(code, &req)
+	if err != nil {
+		h.Logger.Error("Failed to update vehicle type:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Vehicle type updated successfully",
+		"data":    response,
+	})
+}
+
+// GetHolidays godoc
+// @Summary Get holiday calendar
+// @Description Get national holidays used by holiday tariff schedules
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string false "Start date (DD-MM-YYYY)"
+// @Param end_date query string false "End date (DD-MM-YYYY)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/holidays [get]
+func (h *Handlers) GetHolidays(c *gin.Context) {
+	startTime, endTime, err := parseDateFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	response, err := h.AdminUC.GetHolidays(startTime, endTime)
+	if err != nil {
+		h.Logger.Error("Failed to get holidays:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Holidays retrieved successfully",
+		"data":    response,
+	})
+}
+
+// CreateHoliday godoc
+// @Summary Create holiday
+// @Description Add a national holiday (e.g. Lebaran) to the holiday calendar
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.CreateHolidayRequest true "Holiday data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/holidays [post]
+func (h *Handlers) CreateHoliday(c *gin.Context) {
+	var req entities.CreateHolidayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.AdminUC.CreateHoliday(&req)
+	if err != nil {
+		h.Logger.Error("Failed to create holiday:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Holiday created successfully",
+		"data":    response,
+	})
+}
+
+// DeleteHoliday godoc
+// @Summary Delete holiday
+// @Description Remove a date from the holiday calendar
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Holiday ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/holidays/{id} [delete]
+func (h *Handlers) DeleteHoliday(c *gin.Context) {
+	holidayID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid holiday ID",
+		})
+		return
+	}
+
+	if err := h.AdminUC.DeleteHoliday(uint(holidayID)); err != nil {
+		h.Logger.Error("Failed to delete holiday:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Holiday deleted successfully",
+	})
+}
+
+// GetPassProducts godoc
+// @Summary Get pass products
+// @Description List monthly pass (langganan) products, optionally filtered by area or region
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param area_id query int false "Filter by area ID"
+// @Param regional query string false "Filter by region"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/pass-products [get]
+func (h *Handlers) GetPassProducts(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	var areaID *uint
+	if areaIDStr := c.Query("area_id"); areaIDStr != "" {
+		if id, err := strconv.ParseUint(areaIDStr, 10, 32); err == nil {
+			v := uint(id)
+			areaID = &v
+		}
+	}
+
+	var regional *string
+	if r := c.Query("regional"); r != "" {
+		regional = &r
+	}
+
+	products, count, err := h.PassUC.GetPassProducts(areaID, regional, limit, offset)
+	if err != nil {
+		h.Logger.Error("Failed to get pass products:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Pass products retrieved successfully",
+		"data":    products,
+		"meta": gin.H{
+			"pagination": gin.H{
+				"limit":  limit,
+				"offset": offset,
+				"total":  count,
+			},
+		},
+	})
+}
+
+// CreatePassProduct godoc
+// @Summary Create pass product
+// @Description Create a monthly pass product for one area (area_id) or for every area in a region (regional)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.CreatePassProductRequest true "Pass product data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/pass-products [post]
+func (h *Handlers) CreatePassProduct(c *gin.Context) {
+	var req entities.CreatePassProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	product, err := h.PassUC.CreatePassProduct(&req)
+	if err != nil {
+		h.Logger.Error("Failed to create pass product:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Pass product created successfully",
+		"data":    product,
+	})
+}
+
+// UpdatePassProduct godoc
+// @Summary Update pass product
+// @Description Change a pass product's name, duration, price or availability. Passes already issued are not affected.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Pass product ID"
+// @Param request body entities.UpdatePassProductRequest true "Pass product update data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/pass-products/{id} [put]
+func (h *Handlers) UpdatePassProduct(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid pass product ID",
+		})
+		return
+	}
+
+	var req entities.UpdatePassProductRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	product, err := h.PassUC.UpdatePassProduct(uint(productID), &req)
+	if err != nil {
+		h.Logger.Error("Failed to update pass product:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Pass product updated successfully",
+		"data":    product,
+	})
+}
+
+// GetPasses godoc
+// @Summary Get passes
+// @Description List issued monthly passes, newest first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status (active, suspended)"
+// @Param plat_nomor query string false "Filter by partial plate number"
+// @Param product_id query int false "Filter by pass product ID"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/passes [get]
+func (h *Handlers) GetPasses(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	var status *entities.PassStatus
+	if statusStr := c.Query("status"); statusStr != "" {
+		s := entities.PassStatus(statusStr)
+		status = &s
+	}
+
+	var productID *uint
+	if productIDStr := c.Query("product_id"); productIDStr != "" {
+		if id, err := strconv.ParseUint(productIDStr, 10, 32); err == nil {
+			v := uint(id)
+			productID = &v
+		}
+	}
+
+	passes, count, err := h.PassUC.GetPasses(status, c.Query("plat_nomor"), productID, limit, offset)
+	if err != nil {
+		h.Logger.Error("Failed to get passes:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Passes retrieved successfully",
+		"data":    passes,
+		"meta": gin.H{
+			"pagination": gin.H{
+				"limit":  limit,
+				"offset": offset,
+				"total":  count,
+			},
+		},
+	})
+}
+
+// GetPassByID godoc
+// @Summary Get pass detail
+// @Description Get a monthly pass with its product and purchase history
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Pass ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/passes/{id} [get]
+func (h *Handlers) GetPassByID(c *gin.Context) {
+	passID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid pass ID",
+		})
+		return
+	}
+
+	pass, err := h.PassUC.GetPassByID(uint(passID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Pass retrieved successfully",
+		"data":    pass,
+	})
+}
+
+// IssuePass godoc
+// @Summary Issue pass
+// @Description Sell a monthly pass to a license plate. The sale is recorded as a pass purchase, separate from per-visit revenue.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.IssuePassRequest true "Pass data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/passes [post]
+func (h *Handlers) IssuePass(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User not authenticated",
+		})
+		return
+	}
+
+	var req entities.IssuePassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	pass, err := h.PassUC.IssuePass(userID.(uint), &req)
+	if err != nil {
+		h.Logger.Error("Failed to issue pass:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Pass issued successfully",
+		"data":    pass,
+	})
+}
+
+// RenewPass godoc
+// @Summary Renew pass
+// @Description Extend a monthly pass by one product period and record the renewal
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Pass ID"
+// @Param request body entities.RenewPassRequest true "Renewal payment"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/passes/{id}/renew [post]
+func (h *Handlers) RenewPass(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User not authenticated",
+		})
+		return
+	}
+
+	passID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid pass ID",
+		})
+		return
+	}
+
+	var req entities.RenewPassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	pass, err := h.PassUC.RenewPass(uint(passID), userID.(uint), &req)
+	if err != nil {
+		h.Logger.Error("Failed to renew pass:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Pass renewed successfully",
+		"data":    pass,
+	})
+}
+
+// SuspendPass godoc
+// @Summary Suspend pass
+// @Description Stop a monthly pass from being used at check-in. Sessions with the plate are charged the normal tariff.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Pass ID"
+// @Param request body entities.SuspendPassRequest true "Suspension reason"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/passes/{id}/suspend [post]
+func (h *Handlers) SuspendPass(c *gin.Context) {
+	passID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid pass ID",
+		})
+		return
+	}
+
+	var req entities.SuspendPassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	pass, err := h.PassUC.SuspendPass(uint(passID), &req)
+	if err != nil {
+		h.Logger.Error("Failed to suspend pass:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Pass suspended successfully",
+		"data":    pass,
+	})
+}
+
+// GetJukirQRImage godoc
+// @Summary Get jukir QR code image
+// @Description Render a jukir's check-in QR with the jukir code and area name printed underneath
+// @Tags admin
+// @Produce image/png
+// @Produce image/svg+xml
+// @Security BearerAuth
+// @Param id path int true "Jukir ID"
+// @Param format query string false "Image format" Enums(png, svg)
+// @Success 200 {file} file "QR card image"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/jukirs/{id}/qr-code [get]
+func (h *Handlers) GetJukirQRImage(c *gin.Context) {
+	jukirID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid jukir ID",
+		})
+		return
+	}
+
+	format, ok := qrImageFormat(c)
+	if !ok {
+		return
+	}
+
+	card, err := h.AdminUC.GetJukirQRImage(uint(jukirID), format)
+	if err != nil {
+		h.Logger.Error("Failed to render jukir QR code:", err)
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, format.ContentType(), card)
+}
+
+// RotateJukirQRToken godoc
+// @Summary Rotate jukir QR token
+// @Description Issue a new QR token for a jukir. The old token keeps working for grace_period_minutes (default 0, i.e. it stops working immediately).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Jukir ID"
+// @Param request body entities.RotateQRTokenRequest false "Grace period for the old token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/jukirs/{id}/qr-code/rotate [post]
+func (h *Handlers) RotateJukirQRToken(c *gin.Context) {
+	jukirID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid jukir ID",
+		})
+		return
+	}
+
+	// The body is optional; no body means no grace period
+	var req entities.RotateQRTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.AdminUC.RotateJukirQRToken(uint(jukirID), &req)
+	if err != nil {
+		h.Logger.Error("Failed to rotate jukir QR token:", err)
+		status := http.StatusInternalServerError
+		if err.Error() == "jukir not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "QR token rotated successfully",
+		"data":    response,
+	})
+}
+
+// GetJukirQRSheet godoc
+// @Summary Print jukir QR cards
+// @Description Printable A4 PDF with the QR cards of every active jukir in a region, twelve per page
+// @Tags admin
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param regional query string true "Region"
+// @Success 200 {file} file "QR card sheet"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/jukirs/qr-sheet [get]
+func (h *Handlers) GetJukirQRSheet(c *gin.Context) {
+	regional := strings.TrimSpace(c.Query("regional"))
+	if regional == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "regional is required",
+		})
+		return
+	}
+
+	sheet, err := h.AdminUC.GetJukirQRSheet(regional)
+	if err != nil {
+		h.Logger.Error("Failed to render jukir QR sheet:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	filename := fmt.Sprintf("qr-jukir-%s.pdf", strings.ReplaceAll(strings.ToLower(regional), " ", "-"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Data(http.StatusOK, "application/pdf", sheet)
+}
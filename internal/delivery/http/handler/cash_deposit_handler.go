@@ -0,0 +1,431 @@
+package handler
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/usecase"
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// RecordCashDeposit godoc
+// @Summary Record a cash deposit
+// @Description Record the cash a jukir handed over for one day (setoran). The expected amount is the paid cash payments the jukir confirmed that day; the variance is deposited minus expected. One deposit per jukir and date.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.RecordCashDepositRequest true "Deposit data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/admin/deposits [post]
+func (h *Handlers) RecordCashDeposit(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User not authenticated",
+		})
+		return
+	}
+
+	var req entities.RecordCashDepositRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	deposit, err := h.DepositUC.RecordDeposit(userID.(uint), &req)
+	if err != nil {
+		h.Logger.Error("Failed to record deposit:", err)
+		c.JSON(depositErrorStatus(err, http.StatusBadRequest), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Deposit recorded successfully",
+		"data":    deposit,
+	})
+}
+
+// UpdateCashDeposit godoc
+// @Summary Correct a cash deposit
+// @Description Correct the deposited amount or notes of a recorded deposit. The expected amount and variance are computed again from the day's payments.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Deposit ID"
+// @Param request body entities.UpdateCashDepositRequest true "Deposit data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/deposits/{id} [put]
+func (h *Handlers) UpdateCashDeposit(c *gin.Context) {
+	depositID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid deposit ID",
+		})
+		return
+	}
+
+	var req entities.UpdateCashDepositRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	deposit, err := h.DepositUC.UpdateDeposit(uint(depositID), &req)
+	if err != nil {
+		h.Logger.Error("Failed to update deposit:", err)
+		c.JSON(depositErrorStatus(err, http.StatusBadRequest), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Deposit updated successfully",
+		"data":    deposit,
+	})
+}
+
+// GetCashDeposits godoc
+// @Summary Get cash deposits
+// @Description List recorded cash deposits, newest date first
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Param jukir_id query int false "Jukir ID"
+// @Param regional query string false "Regional"
+// @Param start_date query string false "Start date (DD-MM-YYYY)"
+// @Param end_date query string false "End date (DD-MM-YYYY)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/deposits [get]
+func (h *Handlers) GetCashDeposits(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	regional, jukirID, ok := parseDepositFilter(c)
+	if !ok {
+		return
+	}
+
+	startTime, endTime, err := parseDateFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	filter := entities.CashDepositFilter{
+		JukirID:   jukirID,
+		Regional:  regional,
+		StartDate: startTime,
+		EndDate:   endTime,
+	}
+	deposits, count, err := h.DepositUC.GetDeposits(filter, limit, offset)
+	if err != nil {
+		h.Logger.Error("Failed to get deposits:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Deposits retrieved successfully",
+		"data":    deposits,
+		"meta": gin.H{
+			"pagination": gin.H{
+				"limit":  limit,
+				"offset": offset,
+				"total":  count,
+			},
+		},
+	})
+}
+
+// GetExpectedCash godoc
+// @Summary Get expected cash
+// @Description Get the cash a jukir should hand over for a day, from the paid cash payments they confirmed, with the deposit already recorded for it
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param jukir_id query int true "Jukir ID"
+// @Param date query string false "Date (DD-MM-YYYY), default today"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/deposits/expected [get]
+func (h *Handlers) GetExpectedCash(c *gin.Context) {
+	jukirID, err := strconv.ParseUint(c.Query("jukir_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid jukir_id",
+		})
+		return
+	}
+
+	date := time.Now().In(getGMT7Location())
+	if dateStr := c.Query("date"); dateStr != "" {
+		date, err = time.ParseInLocation("02-01-2006", dateStr, getGMT7Location())
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "invalid date format. Use DD-MM-YYYY",
+			})
+			return
+		}
+	}
+
+	response, err := h.DepositUC.GetExpectedCash(uint(jukirID), date)
+	if err != nil {
+		h.Logger.Error("Failed to get expected cash:", err)
+		c.JSON(depositErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Expected cash retrieved successfully",
+		"data":    response,
+	})
+}
+
+// GetOutstandingBalances godoc
+// @Summary Get outstanding cash balances
+// @Description Cash collected by each jukir over the period against the deposits recorded for it, largest shortfall first, with totals per region. Defaults to the last 30 days.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string false "Start date (DD-MM-YYYY)"
+// @Param end_date query string false "End date (DD-MM-YYYY)"
+// @Param regional query string false "Regional"
+// @Param jukir_id query int false "Jukir ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/deposits/outstanding [get]
+func (h *Handlers) GetOutstandingBalances(c *gin.Context) {
+	regional, jukirID, ok := parseDepositFilter(c)
+	if !ok {
+		return
+	}
+
+	startTime, endTime, err := parseDateFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	report, err := h.DepositUC.GetOutstandingBalances(startTime, endTime, regional, jukirID)
+	if err != nil {
+		h.Logger.Error("Failed to get outstanding balances:", err)
+		c.JSON(depositErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Outstanding balances retrieved successfully",
+		"data":    report,
+	})
+}
+
+// ExportOutstandingBalances godoc
+// @Summary Export outstanding cash balances
+// @Description Export the outstanding cash balances to XLSX, one sheet per jukir and one per region. Defaults to the last 30 days.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param start_date query string false "Start date (DD-MM-YYYY)"
+// @Param end_date query string false "End date (DD-MM-YYYY)"
+// @Param regional query string false "Regional"
+// @Param jukir_id query int false "Jukir ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/admin/deposits/outstanding/export [get]
+func (h *Handlers) ExportOutstandingBalances(c *gin.Context) {
+	regional, jukirID, ok := parseDepositFilter(c)
+	if !ok {
+		return
+	}
+
+	startTime, endTime, err := parseDateFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	xlsxBuffer, err := h.DepositUC.ExportOutstandingBalancesXLSX(startTime, endTime, regional, jukirID)
+	if err != nil {
+		h.Logger.Error("Failed to export outstanding balances:", err)
+		c.JSON(depositErrorStatus(err, http.StatusInternalServerError), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// Generate filename
+	filename := "outstanding-deposits.xlsx"
+	if startTime != nil && endTime != nil {
+		filename = fmt.Sprintf("outstanding-deposits-%s-to-%s.xlsx",
+			startTime.Format("2006-01-02"),
+			endTime.Format("2006-01-02"))
+	}
+
+	// Upload to MinIO
+	objectName := fmt.Sprintf("exports/deposits/%d_%s", time.Now().UnixNano(), filename)
+	reader := bytes.NewReader(xlsxBuffer.Bytes())
+	_, err = h.Storage.Upload(c.Request.Context(), objectName, reader, int64(xlsxBuffer.Len()), "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	if err != nil {
+		h.Logger.Error("Failed to upload XLSX to MinIO:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": "Failed to store export file",
+		})
+		return
+	}
+
+	downloadURL := fmt.Sprintf("/api/v1/admin/files/%s", objectName)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Outstanding balances exported successfully",
+		"data": gin.H{
+			"filename":    filename,
+			"url":         downloadURL,
+			"object_name": objectName,
+		},
+	})
+}
+
+// parseDepositFilter reads the optional regional and jukir_id filters. It writes the error
+// response itself and returns ok=false when jukir_id is invalid.
+func parseDepositFilter(c *gin.Context) (*string, *uint, bool) {
+	var regional *string
+	if value := c.Query("regional"); value != "" {
+		regional = &value
+	}
+
+	var jukirID *uint
+	if jukirIDStr := c.Query("jukir_id"); jukirIDStr != "" {
+		id, err := strconv.ParseUint(jukirIDStr, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid jukir_id",
+			})
+			return nil, nil, false
+		}
+		value := uint(id)
+		jukirID = &value
+	}
+
+	return regional, jukirID, true
+}
+
+// depositErrorStatus maps cash deposit errors to a status code: 409 for a day already recorded,
+// 404 for unknown jukirs and deposits, 400 for bad dates, anything else to the fallback status
+func depositErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, usecase.ErrDepositExists):
+		return http.StatusConflict
+	case err.Error() == "jukir not found", err.Error() == "deposit not found":
+		return http.StatusNotFound
+	case strings.Contains(err.Error(), "date"):
+		return http.StatusBadRequest
+	}
+	return fallback
+}
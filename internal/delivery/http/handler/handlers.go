@@ -8,25 +8,37 @@ import (
 )
 
 type Handlers struct {
-	AuthUC       usecase.AuthUsecase
-	UserUC       usecase.UserUsecase
-	JukirUC      usecase.JukirUsecase
-	ParkingUC    usecase.ParkingUsecase
-	AdminUC      usecase.AdminUsecase
-	EventManager *usecase.EventManager
-	Logger       *logrus.Logger
-	Storage      *storage.MinIOClient
+	AuthUC        usecase.AuthUsecase
+	UserUC        usecase.UserUsecase
+	JukirUC       usecase.JukirUsecase
+	ParkingUC     usecase.ParkingUsecase
+	AdminUC       usecase.AdminUsecase
+	OverstayUC    usecase.OverstayUsecase
+	PassUC        usecase.PassUsecase
+	ReservationUC usecase.ReservationUsecase
+	ReceiptUC     usecase.ReceiptUsecase
+	HoursUC       usecase.OperatingHoursUsecase
+	DepositUC     usecase.CashDepositUsecase
+	EventManager  *usecase.EventManager
+	Logger        *logrus.Logger
+	Storage       *storage.MinIOClient
 }
 
-func NewHandlers(authUC usecase.AuthUsecase, userUC usecase.UserUsecase, jukirUC usecase.JukirUsecase, parkingUC usecase.ParkingUsecase, adminUC usecase.AdminUsecase, eventManager *usecase.EventManager, logger *logrus.Logger, storage *storage.MinIOClient) *Handlers {
+func NewHandlers(authUC usecase.AuthUsecase, userUC usecase.UserUsecase, jukirUC usecase.JukirUsecase, parkingUC usecase.ParkingUsecase, adminUC usecase.AdminUsecase, overstayUC usecase.OverstayUsecase, passUC usecase.PassUsecase, reservationUC usecase.ReservationUsecase, receiptUC usecase.ReceiptUsecase, hoursUC usecase.OperatingHoursUsecase, depositUC usecase.CashDepositUsecase, eventManager *usecase.EventManager, logger *logrus.Logger, storage *storage.MinIOClient) *Handlers {
 	return &Handlers{
-		AuthUC:       authUC,
-		UserUC:       userUC,
-		JukirUC:      jukirUC,
-		ParkingUC:    parkingUC,
-		AdminUC:      adminUC,
-		EventManager: eventManager,
-		Logger:       logger,
-		Storage:      storage,
+		AuthUC:        authUC,
+		UserUC:        userUC,
+		JukirUC:       jukirUC,
+		ParkingUC:     parkingUC,
+		AdminUC:       adminUC,
+		OverstayUC:    overstayUC,
+		PassUC:        passUC,
+		ReservationUC: reservationUC,
+		ReceiptUC:     receiptUC,
+		HoursUC:       hoursUC,
+		DepositUC:     depositUC,
+		EventManager:  eventManager,
+		Logger:        logger,
+		Storage:       storage,
 	}
 }
@@ -2,7 +2,13 @@ package handler
 
 import (
 	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/qrcard"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -92,7 +98,7 @@ func (h *Handlers) GetPendingPayments(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param vehicle_type query string false "Filter by vehicle type: mobil or motor"
+// @Param vehicle_type query string false "Filter by vehicle type code (mobil, motor, ...)"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
@@ -113,14 +119,6 @@ func (h *Handlers) GetActiveSessions(c *gin.Context) {
 	vehicleTypeStr := c.Query("vehicle_type")
 	if vehicleTypeStr != "" {
 		vt := entities.VehicleType(vehicleTypeStr)
-		// Validate vehicle type
-		if vt != entities.VehicleTypeMobil && vt != entities.VehicleTypeMotor {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"success": false,
-				"message": "Invalid vehicle_type. Must be 'mobil' or 'motor'",
-			})
-			return
-		}
 		vehicleType = &vt
 	}
 
@@ -179,6 +177,60 @@ func (h *Handlers) GetQRCode(c *gin.Context) {
 	})
 }
 
+// GetQRImage godoc
+// @Summary Get QR code image
+// @Description Render the jukir's check-in QR with the jukir code and area name printed underneath
+// @Tags jukir
+// @Produce image/png
+// @Produce image/svg+xml
+// @Security BearerAuth
+// @Param format query string false "Image format" Enums(png, svg)
+// @Success 200 {file} file "QR card image"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/jukir/qr-code/image [get]
+func (h *Handlers) GetQRImage(c *gin.Context) {
+	jukirID, exists := c.Get("jukir_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Jukir not authenticated",
+		})
+		return
+	}
+
+	format, ok := qrImageFormat(c)
+	if !ok {
+		return
+	}
+
+	card, err := h.JukirUC.GetQRImage(jukirID.(uint), format)
+	if err != nil {
+		h.Logger.Error("Failed to render QR code:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.Data(http.StatusOK, format.ContentType(), card)
+}
+
+// qrImageFormat reads the format query parameter (png by default) and answers 400 for anything else
+func qrImageFormat(c *gin.Context) (qrcard.Format, bool) {
+	format := qrcard.Format(c.DefaultQuery("format", string(qrcard.FormatPNG)))
+	if format != qrcard.FormatPNG && format != qrcard.FormatSVG {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "format must be png or svg",
+		})
+		return "", false
+	}
+	return format, true
+}
+
 // GetDailyReport godoc
 // @Summary Get daily report
 // @Description Get daily transaction summary for jukir
@@ -269,15 +321,18 @@ func (h *Handlers) GetVehicleBreakdown(c *gin.Context) {
 
 // ManualCheckin godoc
 // @Summary Manual check-in
-// @Description Create manual parking record for check-in
+// @Description Create manual parking record for check-in. Send multipart/form-data with the same fields to attach an optional vehicle "photo" (JPEG, PNG or WebP, max 5 MB).
 // @Tags jukir
 // @Accept json
+// @Accept multipart/form-data
 // @Produce json
 // @Security BearerAuth
 // @Param request body entities.ManualCheckinRequest true "Manual check-in data"
+// @Param photo formData file false "Vehicle photo"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/v1/jukir/manual-checkin [post]
 func (h *Handlers) ManualCheckin(c *gin.Context) {
@@ -291,7 +346,7 @@ func (h *Handlers) ManualCheckin(c *gin.Context) {
 	}
 
 	var req entities.ManualCheckinRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindManualRecord(c, &req); err != nil {
 		h.Logger.Error("Failed to bind JSON:", err)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
@@ -313,16 +368,33 @@ func (h *Handlers) ManualCheckin(c *gin.Context) {
 		return
 	}
 
+	photo, err := h.uploadSessionPhoto(c, jukirID.(uint))
+	if err != nil {
+		h.Logger.Error("Failed to store vehicle photo:", err)
+		status := http.StatusBadRequest
+		if errors.Is(err, errPhotoUploadFailed) {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+	req.Photo = photo
+
 	response, err := h.ParkingUC.ManualCheckin(jukirID.(uint), &req)
 	if err != nil {
 		h.Logger.Error("Manual check-in failed:", err)
-		c.JSON(http.StatusBadRequest, gin.H{
+		c.JSON(checkinErrorStatus(err), gin.H{
 			"success": false,
 			"message": err.Error(),
 		})
 		return
 	}
 
+	response.ReceiptURL = h.issueReceipt(response.SessionID)
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Manual check-in successful",
@@ -332,15 +404,18 @@ func (h *Handlers) ManualCheckin(c *gin.Context) {
 
 // ManualCheckout godoc
 // @Summary Manual check-out
-// @Description Create manual parking record for check-out
+// @Description Create manual parking record for check-out. Send multipart/form-data with the same fields to attach an optional vehicle "photo" (JPEG, PNG or WebP, max 5 MB).
 // @Tags jukir
 // @Accept json
+// @Accept multipart/form-data
 // @Produce json
 // @Security BearerAuth
 // @Param request body entities.ManualCheckoutRequest true "Manual check-out data"
+// @Param photo formData file false "Vehicle photo"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/v1/jukir/manual-checkout [post]
 func (h *Handlers) ManualCheckout(c *gin.Context) {
@@ -354,7 +429,7 @@ func (h *Handlers) ManualCheckout(c *gin.Context) {
 	}
 
 	var req entities.ManualCheckoutRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := bindManualRecord(c, &req); err != nil {
 		h.Logger.Error("Failed to bind JSON:", err)
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
@@ -376,19 +451,359 @@ func (h *Handlers) ManualCheckout(c *gin.Context) {
 		return
 	}
 
+	photo, err := h.uploadSessionPhoto(c, jukirID.(uint))
+	if err != nil {
+		h.Logger.Error("Failed to store vehicle photo:", err)
+		status := http.StatusBadRequest
+		if errors.Is(err, errPhotoUploadFailed) {
+			status = http.StatusInternalServerError
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+	req.Photo = photo
+
 	response, err := h.ParkingUC.ManualCheckout(jukirID.(uint), &req)
 	if err != nil {
 		h.Logger.Error("Manual check-out failed:", err)
-		c.JSON(http.StatusBadRequest, gin.H{
+		c.JSON(conflictErrorStatus(err, http.StatusBadRequest), gin.H{
 			"success": false,
 			"message": err.Error(),
 		})
 		return
 	}
 
+	response.ReceiptURL = h.issueReceipt(response.SessionID)
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Manual check-out successful",
 		"data":    response,
 	})
 }
+
+// SyncManualRecords godoc
+// @Summary Sync offline manual records
+// @Description Upload manual check-ins and checkouts recorded offline, in order. Items are keyed by a client-generated UUID, so re-uploading a batch is safe. Each item gets its own result.
+// @Tags jukir
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.SyncRequest true "Offline manual records"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/jukir/sync [post]
+func (h *Handlers) SyncManualRecords(c *gin.Context) {
+	jukirID, exists := c.Get("jukir_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Jukir not authenticated",
+		})
+		return
+	}
+
+	var req entities.SyncRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.ParkingUC.SyncManualRecords(jukirID.(uint), &req)
+	if err != nil {
+		h.Logger.Error("Sync failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Sync completed",
+		"data":    response,
+	})
+}
+
+// RequestSessionVoid godoc
+// @Summary Request a session void
+// @Description Ask an admin to cancel a session recorded by mistake (wrong plate, wrong vehicle type or accidental check-in). The session is only cancelled once an admin approves.
+// @Tags jukir
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Session ID"
+// @Param request body entities.CreateVoidRequest true "Void reason"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/jukir/sessions/{id}/void [post]
+func (h *Handlers) RequestSessionVoid(c *gin.Context) {
+	jukirID, exists := c.Get("jukir_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Jukir not authenticated",
+		})
+		return
+	}
+
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid session ID",
+		})
+		return
+	}
+
+	var req entities.CreateVoidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	request, err := h.JukirUC.RequestSessionVoid(jukirID.(uint), uint(sessionID), &req)
+	if err != nil {
+		h.Logger.Error("Void request failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Void request submitted",
+		"data":    request,
+	})
+}
+
+// GetLostTicketCandidates godoc
+// @Summary Find sessions for a lost ticket
+// @Description Search active sessions in jukir's area by partial plate number and vehicle type, for a customer who lost their ticket. Each candidate includes the estimated fee and the area's lost-ticket penalty.
+// @Tags jukir
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param plat query string false "Partial plate number (spaces and case are ignored)"
+// @Param vehicle_type query string true "Vehicle type code (mobil, motor, ...)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/jukir/lost-ticket/candidates [get]
+func (h *Handlers) GetLostTicketCandidates(c *gin.Context) {
+	jukirID, exists := c.Get("jukir_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Jukir not authenticated",
+		})
+		return
+	}
+
+	vehicleTypeStr := c.Query("vehicle_type")
+	if vehicleTypeStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "vehicle_type is required",
+		})
+		return
+	}
+
+	candidates, err := h.ParkingUC.FindLostTicketCandidates(jukirID.(uint), c.Query("plat"), entities.VehicleType(vehicleTypeStr))
+	if err != nil {
+		h.Logger.Error("Failed to find lost ticket candidates:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Lost ticket candidates retrieved successfully",
+		"data":    candidates,
+	})
+}
+
+// LostTicketCheckout godoc
+// @Summary Lost ticket check-out
+// @Description Check out a session picked from the lost-ticket candidates. The parking fee and the area's lost-ticket penalty are recorded as separate payment lines.
+// @Tags jukir
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.LostTicketCheckoutRequest true "Lost ticket check-out data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/jukir/lost-ticket/checkout [post]
+func (h *Handlers) LostTicketCheckout(c *gin.Context) {
+	jukirID, exists := c.Get("jukir_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Jukir not authenticated",
+		})
+		return
+	}
+
+	var req entities.LostTicketCheckoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.ParkingUC.LostTicketCheckout(jukirID.(uint), &req)
+	if err != nil {
+		h.Logger.Error("Lost ticket check-out failed:", err)
+		c.JSON(conflictErrorStatus(err, http.StatusBadRequest), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	response.ReceiptURL = h.issueReceipt(response.SessionID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Lost ticket check-out successful",
+		"data":    response,
+	})
+}
+
+// maxSessionPhotoSize caps vehicle photos sent with manual records
+const maxSessionPhotoSize = 5 << 20
+
+// sessionPhotoExtensions are the accepted photo types, keyed by sniffed content type
+var sessionPhotoExtensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+var errPhotoUploadFailed = errors.New("failed to upload vehicle photo")
+
+// bindManualRecord binds a manual record sent as JSON or, when it carries a photo, as
+// multipart/form-data with the same field names
+func bindManualRecord(c *gin.Context, req interface{}) error {
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		return c.ShouldBind(req)
+	}
+	return c.ShouldBindJSON(req)
+}
+
+// uploadSessionPhoto stores the optional multipart "photo" file in MinIO so the usecase can link
+// it to the session. It returns nil when the request has no photo.
+func (h *Handlers) uploadSessionPhoto(c *gin.Context, jukirID uint) (*entities.UploadedPhoto, error) {
+	if !strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		return nil, nil
+	}
+	fileHeader, err := c.FormFile("photo")
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			return nil, nil
+		}
+		return nil, errors.New("invalid photo")
+	}
+	if fileHeader.Size > maxSessionPhotoSize {
+		return nil, fmt.Errorf("photo must be at most %d MB", maxSessionPhotoSize>>20)
+	}
+
+	f, err := fileHeader.Open()
+	if err != nil {
+		return nil, errors.New("failed to open photo")
+	}
+	defer f.Close()
+
+	// Trust the file content, not the client's Content-Type header
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, errors.New("failed to read photo")
+	}
+	contentType := http.DetectContentType(head[:n])
+	ext, ok := sessionPhotoExtensions[contentType]
+	if !ok {
+		return nil, errors.New("photo must be a JPEG, PNG or WebP image")
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.New("failed to read photo")
+	}
+
+	objectName := fmt.Sprintf("sessions/%d/%d%s", jukirID, time.Now().UnixNano(), ext)
+	if _, err := h.Storage.Upload(c.Request.Context(), objectName, f, fileHeader.Size, contentType); err != nil {
+		h.Logger.Error("MinIO upload failed:", err)
+		return nil, errPhotoUploadFailed
+	}
+
+	return &entities.UploadedPhoto{
+		ObjectName:  objectName,
+		ContentType: contentType,
+		Size:        fileHeader.Size,
+	}, nil
+}
@@ -0,0 +1,227 @@
+package handler
+
+import (
+	"be-parkir/internal/domain/entities"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// GetOperatingHours godoc
+// @Summary Get area operating hours
+// @Description Weekly operating hours, upcoming exceptions and the current open/closed status of a parking area
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Area ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/admin/areas/{id}/operating-hours [get]
+func (h *Handlers) GetOperatingHours(c *gin.Context) {
+	areaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid area ID",
+		})
+		return
+	}
+
+	response, err := h.HoursUC.GetOperatingHours(uint(areaID))
+	if err != nil {
+		h.Logger.Error("Failed to get operating hours:", err)
+		status := http.StatusInternalServerError
+		if err.Error() == "parking area not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Operating hours retrieved successfully",
+		"data":    response,
+	})
+}
+
+// SetOperatingHours godoc
+// @Summary Set area operating hours
+// @Description Replace the weekly operating hours of a parking area (days left out are closed, an empty list removes the schedule) and optionally set hours_override. status_operasional is updated right away.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Area ID"
+// @Param request body entities.SetOperatingHoursRequest true "Weekly operating hours"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/areas/{id}/operating-hours [put]
+func (h *Handlers) SetOperatingHours(c *gin.Context) {
+	areaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid area ID",
+		})
+		return
+	}
+
+	var req entities.SetOperatingHoursRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.HoursUC.SetOperatingHours(uint(areaID), &req)
+	if err != nil {
+		h.Logger.Error("Failed to set operating hours:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Operating hours saved successfully",
+		"data":    response,
+	})
+}
+
+// CreateScheduleException godoc
+// @Summary Create schedule exception
+// @Description Replace an area's operating hours on one date, either closed all day or with its own open and close time
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Area ID"
+// @Param request body entities.CreateScheduleExceptionRequest true "Exception data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/areas/{id}/operating-hours/exceptions [post]
+func (h *Handlers) CreateScheduleException(c *gin.Context) {
+	areaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid area ID",
+		})
+		return
+	}
+
+	var req entities.CreateScheduleExceptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.HoursUC.CreateScheduleException(uint(areaID), &req)
+	if err != nil {
+		h.Logger.Error("Failed to create schedule exception:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Schedule exception created successfully",
+		"data":    response,
+	})
+}
+
+// DeleteScheduleException godoc
+// @Summary Delete schedule exception
+// @Description Remove a dated exception; the weekly operating hours apply again on that date
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Area ID"
+// @Param exception_id path int true "Exception ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/admin/areas/{id}/operating-hours/exceptions/{exception_id} [delete]
+func (h *Handlers) DeleteScheduleException(c *gin.Context) {
+	areaID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid area ID",
+		})
+		return
+	}
+
+	exceptionID, err := strconv.ParseUint(c.Param("exception_id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid exception ID",
+		})
+		return
+	}
+
+	if err := h.HoursUC.DeleteScheduleException(uint(areaID), uint(exceptionID)); err != nil {
+		h.Logger.Error("Failed to delete schedule exception:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Schedule exception deleted successfully",
+	})
+}
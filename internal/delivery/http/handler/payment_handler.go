@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"be-parkir/internal/paygate"
+	"be-parkir/internal/usecase"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PaymentCallback godoc
+// @Summary Payment gateway callback
+// @Description Signed status callback from the payment gateway. A paid charge marks the payment paid, completes a session waiting on it and notifies the jukir. Repeated callbacks are accepted.
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /webhooks/payments [post]
+func (h *Handlers) PaymentCallback(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+		})
+		return
+	}
+
+	if err := h.ParkingUC.HandlePaymentCallback(body, c.Request.Header); err != nil {
+		h.Logger.Error("Failed to handle payment callback:", err)
+		c.JSON(paymentErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Callback processed",
+	})
+}
+
+// GetPaymentStatus godoc
+// @Summary Get payment status
+// @Description Get the QRIS charge of a parking session with its current status (anonymous). A pending charge is checked with the gateway first.
+// @Tags parking
+// @Accept json
+// @Produce json
+// @Param ticket query string true "Parking ticket from check-in"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/parking/payment [get]
+func (h *Handlers) GetPaymentStatus(c *gin.Context) {
+	response, err := h.ParkingUC.GetPaymentStatus(c.Query("ticket"))
+	if err != nil {
+		h.Logger.Error("Failed to get payment status:", err)
+		c.JSON(ticketErrorStatus(err, paymentErrorStatus(err)), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Payment status retrieved successfully",
+		"data":    response,
+	})
+}
+
+// SimulatePayment godoc
+// @Summary Pay a mock charge
+// @Description Development only: pay a charge on the mock gateway. Its signed callback goes through the same path as the webhook.
+// @Tags payment
+// @Accept json
+// @Produce json
+// @Param charge_id path string true "Charge ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/payments/mock/{charge_id}/pay [post]
+func (h *Handlers) SimulatePayment(c *gin.Context) {
+	if err := h.ParkingUC.SimulatePayment(c.Param("charge_id")); err != nil {
+		h.Logger.Error("Failed to simulate payment:", err)
+		status := paymentErrorStatus(err)
+		if status == http.StatusInternalServerError {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Charge paid",
+	})
+}
+
+// paymentErrorStatus maps online payment errors to a status code. Anything unexpected is a 500
+// so the gateway retries the callback.
+func paymentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, paygate.ErrInvalidSignature):
+		return http.StatusUnauthorized
+	case errors.Is(err, usecase.ErrUnknownCharge), errors.Is(err, paygate.ErrChargeNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrPaymentsUnavailable):
+		return http.StatusServiceUnavailable
+	case err.Error() == "payment not found":
+		return http.StatusNotFound
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"be-parkir/internal/usecase"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// issueReceipt (re)issues the e-karcis of a session and returns its public link. A receipt that
+// cannot be rendered or stored must not fail the check-in or checkout, so errors are only logged.
+func (h *Handlers) issueReceipt(sessionID uint) string {
+	issued, err := h.ReceiptUC.IssueReceipt(sessionID)
+	if err != nil {
+		h.Logger.WithField("session_id", sessionID).Error("Failed to issue receipt:", err)
+		return ""
+	}
+	return h.ReceiptUC.ReceiptURL(issued)
+}
+
+// GetReceipt godoc
+// @Summary Download e-karcis
+// @Description Download the receipt of a parking session by its short link code. Returns the PDF unless format=png is given. Public, no API key required.
+// @Tags receipts
+// @Produce application/pdf
+// @Produce image/png
+// @Param code path string true "Receipt code"
+// @Param format query string false "Document format" Enums(pdf, png)
+// @Success 200 {file} file "Receipt document"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /r/{code} [get]
+func (h *Handlers) GetReceipt(c *gin.Context) {
+	format := usecase.ReceiptFormat(c.DefaultQuery("format", string(usecase.ReceiptFormatPDF)))
+	if format != usecase.ReceiptFormatPDF && format != usecase.ReceiptFormatPNG {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "format must be pdf or png",
+		})
+		return
+	}
+
+	reader, size, contentType, err := h.ReceiptUC.GetReceiptFile(c.Param("code"), format)
+	if err != nil {
+		if !errors.Is(err, usecase.ErrReceiptNotFound) {
+			h.Logger.Error("Failed to get receipt:", err)
+		}
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": "Receipt not found",
+		})
+		return
+	}
+	defer func() {
+		if closer, ok := reader.(io.Closer); ok {
+			closer.Close()
+		}
+	}()
+
+	filename := fmt.Sprintf("karcis-%s.%s", c.Param("code"), format)
+	c.DataFromReader(http.StatusOK, size, contentType, reader, map[string]string{
+		"Content-Disposition": fmt.Sprintf("inline; filename=%s", filename),
+	})
+}
+
+// VerifyReceipt godoc
+// @Summary Verify e-karcis
+// @Description Confirm that a receipt was issued by the system and compare the amount charged with the area's official tariff. This is the link in the receipt's QR code. Public, no API key required.
+// @Tags receipts
+// @Produce json
+// @Param code path string true "Receipt code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /r/{code}/verify [get]
+func (h *Handlers) VerifyReceipt(c *gin.Context) {
+	verification, err := h.ReceiptUC.VerifyReceipt(c.Param("code"))
+	if err != nil {
+		if errors.Is(err, usecase.ErrReceiptNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"success": false,
+				"message": "Receipt not found; this karcis was not issued by the system",
+				"data":    gin.H{"code": c.Param("code"), "genuine": false},
+			})
+			return
+		}
+		h.Logger.Error("Failed to verify receipt:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Receipt is genuine",
+		"data":    verification,
+	})
+}
@@ -2,6 +2,8 @@ package handler
 
 import (
 	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/usecase"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -112,13 +114,16 @@ func (h *Handlers) UpdateProfile(c *gin.Context) {
 
 // GetNearbyAreas godoc
 // @Summary Get nearby parking areas
-// @Description Get parking areas within specified radius of user's location. If latitude and longitude are not provided, returns all active parking areas.
+// @Description Get parking areas within specified radius of user's location, nearest first with distance_m. If latitude and longitude are not provided, returns all active parking areas. Each area carries is_open and, per vehicle type, availability and the rate in effect now.
 // @Tags parking
 // @Accept json
 // @Produce json
 // @Param latitude query number false "Latitude"
 // @Param longitude query number false "Longitude"
 // @Param radius query number false "Radius in kilometers" default(1.0)
+// @Param jenis_area query string false "Only areas of this type" Enums(indoor, outdoor, mix)
+// @Param vehicle_type query string false "Only areas that accept this vehicle type"
+// @Param open_now query bool false "Only areas that are open now"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
@@ -175,6 +180,38 @@ func (h *Handlers) GetNearbyAreas(c *gin.Context) {
 		}
 	}
 
+	// Optional filters
+	if jenisArea := c.Query("jenis_area"); jenisArea != "" {
+		ja := entities.JenisArea(jenisArea)
+		req.JenisArea = &ja
+	}
+	if vehicleType := c.Query("vehicle_type"); vehicleType != "" {
+		vt := entities.VehicleType(vehicleType)
+		req.VehicleType = &vt
+	}
+	if openNow := c.Query("open_now"); openNow != "" {
+		parsed, err := strconv.ParseBool(openNow)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid open_now format",
+			})
+			return
+		}
+		req.OpenNow = parsed
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.StructPartial(req, "JenisArea", "VehicleType"); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
 	response, err := h.ParkingUC.GetNearbyAreas(req)
 	if err != nil {
 		h.Logger.Error("Failed to get nearby areas:", err)
@@ -197,15 +234,44 @@ func (h *Handlers) GetNearbyAreas(c *gin.Context) {
 	})
 }
 
+// GetActiveVehicleTypes godoc
+// @Summary Get vehicle types
+// @Description Get the vehicle types currently accepted for check-in
+// @Tags parking
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/parking/vehicle-types [get]
+func (h *Handlers) GetActiveVehicleTypes(c *gin.Context) {
+	response, err := h.ParkingUC.GetVehicleTypes()
+	if err != nil {
+		h.Logger.Error("Failed to get vehicle types:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Vehicle types retrieved successfully",
+		"data":    response,
+	})
+}
+
 // Checkin godoc
 // @Summary Check in to parking
-// @Description Start a parking session by scanning QR code (anonymous)
+// @Description Start a parking session by scanning QR code (anonymous). A customer bearer token, when sent, links the session to the account and allows vehicle_id to fill the plate and vehicle type from a saved vehicle. With payment_method qris the response carries a pending QRIS charge.
 // @Tags parking
 // @Accept json
 // @Produce json
+// @Param Authorization header string false "Optional customer bearer token"
 // @Param request body entities.CheckinRequest true "Check-in data"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/v1/parking/checkin [post]
 func (h *Handlers) Checkin(c *gin.Context) {
@@ -234,16 +300,20 @@ func (h *Handlers) Checkin(c *gin.Context) {
 		return
 	}
 
+	req.UserID = customerID(c)
+
 	response, err := h.ParkingUC.Checkin(&req)
 	if err != nil {
 		h.Logger.Error("Check-in failed:", err)
-		c.JSON(http.StatusBadRequest, gin.H{
+		c.JSON(checkinErrorStatus(err), gin.H{
 			"success": false,
 			"message": err.Error(),
 		})
 		return
 	}
 
+	response.ReceiptURL = h.issueReceipt(response.SessionID)
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Check-in successful",
@@ -251,15 +321,36 @@ func (h *Handlers) Checkin(c *gin.Context) {
 	})
 }
 
+// checkinErrorStatus maps check-in errors to a status code: 409 when the area is full or closed
+func checkinErrorStatus(err error) int {
+	if errors.Is(err, usecase.ErrAreaFull) || errors.Is(err, usecase.ErrAreaClosed) {
+		return http.StatusConflict
+	}
+	return http.StatusBadRequest
+}
+
+// customerID is the logged-in customer set by the optional auth middleware, or nil
+func customerID(c *gin.Context) *uint {
+	role, exists := c.Get("user_role")
+	if !exists || role != entities.RoleCustomer {
+		return nil
+	}
+	userID := c.GetUint("user_id")
+	return &userID
+}
+
 // Checkout godoc
 // @Summary Check out from parking
-// @Description End a parking session by scanning QR code (anonymous)
+// @Description End a parking session by scanning QR code (anonymous). Requires the ticket issued at check-in. A customer bearer token, when sent, links an anonymous session to the account. Sessions paid by QRIS get a QRIS charge for the rest of the cost and stay pending_payment until it is paid.
 // @Tags parking
 // @Accept json
 // @Produce json
+// @Param Authorization header string false "Optional customer bearer token"
 // @Param request body entities.CheckoutRequest true "Check-out data"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/v1/parking/checkout [post]
 func (h *Handlers) Checkout(c *gin.Context) {
@@ -288,16 +379,20 @@ func (h *Handlers) Checkout(c *gin.Context) {
 		return
 	}
 
+	req.UserID = customerID(c)
+
 	response, err := h.ParkingUC.Checkout(&req)
 	if err != nil {
 		h.Logger.Error("Check-out failed:", err)
-		c.JSON(http.StatusBadRequest, gin.H{
+		c.JSON(ticketErrorStatus(err, conflictErrorStatus(err, http.StatusBadRequest)), gin.H{
 			"success": false,
 			"message": err.Error(),
 		})
 		return
 	}
 
+	response.ReceiptURL = h.issueReceipt(response.SessionID)
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"message": "Check-out successful",
@@ -307,13 +402,15 @@ func (h *Handlers) Checkout(c *gin.Context) {
 
 // GetActiveSession godoc
 // @Summary Get active parking session
-// @Description Get active parking session by session ID (anonymous)
+// @Description Get active parking session by session ID (anonymous). Requires the ticket issued at check-in.
 // @Tags parking
 // @Accept json
 // @Produce json
 // @Param id path int true "Session ID"
+// @Param ticket query string false "Parking ticket from check-in"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
 // @Failure 404 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/v1/parking/active/{id} [get]
@@ -336,10 +433,10 @@ func (h *Handlers) GetActiveSession(c *gin.Context) {
 		return
 	}
 
-	response, err := h.ParkingUC.GetActiveSessionByID(uint(sessionID64))
+	response, err := h.ParkingUC.GetActiveSessionByID(uint(sessionID64), c.Query("ticket"))
 	if err != nil {
 		h.Logger.Error("Failed to get active session:", err)
-		c.JSON(http.StatusNotFound, gin.H{
+		c.JSON(ticketErrorStatus(err, http.StatusNotFound), gin.H{
 			"success": false,
 			"message": err.Error(),
 		})
@@ -355,80 +452,162 @@ func (h *Handlers) GetActiveSession(c *gin.Context) {
 
 // GetParkingHistory godoc
 // @Summary Get parking history
-// @Description Get parking session history by license plate or session ID (anonymous)
+// @Description Get a parking session by session ID and ticket (anonymous). Looking up a plate's history is no longer open; customers use their saved vehicles under /me instead.
 // @Tags parking
 // @Accept json
 // @Produce json
-// @Param plat_nomor query string false "License plate number"
-// @Param session_id query int false "Session ID"
-// @Param limit query int false "Limit" default(10)
-// @Param offset query int false "Offset" default(0)
+// @Param session_id query int true "Session ID"
+// @Param ticket query string false "Parking ticket, required with session_id"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
-// @Failure 500 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 410 {object} map[string]interface{}
 // @Router /api/v1/parking/history [get]
 func (h *Handlers) GetParkingHistory(c *gin.Context) {
-	platNomor := c.Query("plat_nomor")
 	sessionIDStr := c.Query("session_id")
 
-	// Require at least one: plat_nomor OR session_id
-	if platNomor == "" && sessionIDStr == "" {
+	if sessionIDStr == "" {
+		// Anyone could read any plate's history here; customers now see their own sessions under /me/sessions
+		if c.Query("plat_nomor") != "" {
+			c.JSON(http.StatusGone, gin.H{
+				"success": false,
+				"message": "History by license plate is no longer available. Log in and use /api/v1/me/sessions?vehicle_id= with a saved vehicle",
+			})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Session ID is required",
+		})
+		return
+	}
+
+	sessionID, err := strconv.ParseUint(sessionIDStr, 10, 64)
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"message": "Either license plate number or session ID is required",
+			"message": "Invalid session ID",
+		})
+		return
+	}
+
+	session, err := h.ParkingUC.GetHistoryBySession(uint(sessionID), c.Query("ticket"))
+	if err != nil {
+		c.JSON(ticketErrorStatus(err, http.StatusNotFound), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Parking history retrieved successfully",
+		"data": gin.H{
+			"sessions": []entities.ParkingSession{*session},
+			"count":    1,
+		},
+	})
+}
+
+// GetMySessions godoc
+// @Summary Get my parking sessions
+// @Description List the current customer's parking sessions, latest check-in first
+// @Tags parking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by session status (active, pending_payment, completed, cancelled)"
+// @Param area_id query int false "Filter by area ID"
+// @Param vehicle_type query string false "Filter by vehicle type"
+// @Param plat_nomor query string false "Filter by license plate number"
+// @Param vehicle_id query int false "Filter by saved vehicle"
+// @Param start_date query string false "Check-in from date (DD-MM-YYYY)"
+// @Param end_date query string false "Check-in until date (DD-MM-YYYY)"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/me/sessions [get]
+func (h *Handlers) GetMySessions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User not authenticated",
 		})
 		return
 	}
 
-	// If session_id is provided, return single session
-	if sessionIDStr != "" {
-		sessionID, err := strconv.ParseUint(sessionIDStr, 10, 64)
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	var filter entities.CustomerSessionFilter
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := entities.SessionStatus(statusStr)
+		filter.SessionStatus = &status
+	}
+	if areaIDStr := c.Query("area_id"); areaIDStr != "" {
+		areaID, err := strconv.ParseUint(areaIDStr, 10, 32)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{
 				"success": false,
-				"message": "Invalid session ID",
+				"message": "Invalid area ID",
 			})
 			return
 		}
-
-		session, err := h.ParkingUC.GetHistoryBySession(uint(sessionID))
+		id := uint(areaID)
+		filter.AreaID = &id
+	}
+	if vehicleTypeStr := c.Query("vehicle_type"); vehicleTypeStr != "" {
+		vehicleType := entities.VehicleType(vehicleTypeStr)
+		filter.VehicleType = &vehicleType
+	}
+	if platNomor := c.Query("plat_nomor"); platNomor != "" {
+		filter.PlatNomor = &platNomor
+	}
+	if vehicleIDStr := c.Query("vehicle_id"); vehicleIDStr != "" {
+		vehicleID, err := strconv.ParseUint(vehicleIDStr, 10, 32)
 		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{
+			c.JSON(http.StatusBadRequest, gin.H{
 				"success": false,
-				"message": err.Error(),
+				"message": "Invalid vehicle ID",
 			})
 			return
 		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"success": true,
-			"message": "Parking history retrieved successfully",
-			"data": gin.H{
-				"sessions": []entities.ParkingSession{*session},
-				"count":    1,
-			},
-		})
-		return
+		id := uint(vehicleID)
+		filter.VehicleID = &id
 	}
 
-	// Query by plat_nomor
-	limitStr := c.DefaultQuery("limit", "10")
-	offsetStr := c.DefaultQuery("offset", "0")
-
-	limit, err := strconv.Atoi(limitStr)
+	filter.StartDate, filter.EndDate, err = parseDateFilter(c)
 	if err != nil {
-		limit = 10
-	}
-
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil {
-		offset = 0
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
 	}
 
-	response, err := h.ParkingUC.GetHistoryByPlatNomor(platNomor, limit, offset)
+	response, err := h.ParkingUC.GetMySessions(userID.(uint), filter, limit, offset)
 	if err != nil {
-		h.Logger.Error("Failed to get parking history:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
+		h.Logger.Error("Failed to get customer sessions:", err)
+		status := http.StatusInternalServerError
+		if errors.Is(err, entities.ErrInvalidPlatNomor) {
+			status = http.StatusBadRequest
+		} else if err.Error() == "vehicle not found" {
+			status = http.StatusNotFound
+		}
+		c.JSON(status, gin.H{
 			"success": false,
 			"message": err.Error(),
 		})
@@ -437,7 +616,7 @@ func (h *Handlers) GetParkingHistory(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
-		"message": "Parking history retrieved successfully",
+		"message": "Parking sessions retrieved successfully",
 		"data":    response,
 		"meta": gin.H{
 			"pagination": gin.H{
@@ -449,15 +628,79 @@ func (h *Handlers) GetParkingHistory(c *gin.Context) {
 	})
 }
 
+// ClaimSessions godoc
+// @Summary Claim anonymous parking sessions
+// @Description Link sessions made before logging in to the current customer, using the tickets issued at check-in. Invalid tickets and sessions of another account are reported as skipped.
+// @Tags parking
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.ClaimSessionsRequest true "Tickets from check-in"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/me/sessions/claim [post]
+func (h *Handlers) ClaimSessions(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User not authenticated",
+		})
+		return
+	}
+
+	var req entities.ClaimSessionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	response, err := h.ParkingUC.ClaimSessions(userID.(uint), req.Tickets)
+	if err != nil {
+		h.Logger.Error("Failed to claim sessions:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Sessions claimed successfully",
+		"data":    response,
+	})
+}
+
 // GetParkingHistoryByIDs godoc
-// @Summary Get parking history by session IDs (bulk)
-// @Description Get parking sessions by array of session IDs (anonymous, supports bulk request)
+// @Summary Get parking history by tickets (bulk)
+// @Description Get parking sessions by array of parking tickets (anonymous, supports bulk request). Bare session_ids are only accepted during the ticket transition window.
 // @Tags parking
 // @Accept json
 // @Produce json
-// @Param request body map[string][]uint true "Session IDs array" Example({"session_ids": [1, 2, 3]})
+// @Param request body map[string][]string true "Tickets array" Example({"tickets": ["..."]})
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/v1/parking/history [post]
 func (h *Handlers) GetParkingHistoryByIDs(c *gin.Context) {
@@ -471,39 +714,50 @@ func (h *Handlers) GetParkingHistoryByIDs(c *gin.Context) {
 	}
 
 	var req struct {
-		SessionIDs []uint `json:"session_ids" binding:"required"`
+		Tickets    []string `json:"tickets"`
+		SessionIDs []uint   `json:"session_ids"` // legacy, transition window only
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"message": "Invalid request body. Expected: {\"session_ids\": [1, 2, 3]}",
+			"message": "Invalid request body. Expected: {\"tickets\": [\"...\"]}",
 			"error":   err.Error(),
 		})
 		return
 	}
 
-	if len(req.SessionIDs) == 0 {
+	count := len(req.Tickets)
+	if count == 0 {
+		count = len(req.SessionIDs)
+	}
+	if count == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"message": "session_ids array cannot be empty",
+			"message": "tickets array cannot be empty",
 		})
 		return
 	}
 
 	// Limit bulk requests to prevent abuse (max 100 sessions at once)
-	if len(req.SessionIDs) > 100 {
+	if count > 100 {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"success": false,
-			"message": "Maximum 100 session IDs allowed per request",
+			"message": "Maximum 100 sessions allowed per request",
 		})
 		return
 	}
 
-	sessions, err := h.ParkingUC.GetHistoryBySessionIDs(req.SessionIDs)
+	var sessions []entities.ParkingSession
+	var err error
+	if len(req.Tickets) > 0 {
+		sessions, err = h.ParkingUC.GetHistoryByTickets(req.Tickets)
+	} else {
+		sessions, err = h.ParkingUC.GetHistoryBySessionIDs(req.SessionIDs)
+	}
 	if err != nil {
 		h.Logger.Error("Failed to get parking history:", err)
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(ticketErrorStatus(err, http.StatusInternalServerError), gin.H{
 			"success": false,
 			"message": err.Error(),
 		})
@@ -519,3 +773,242 @@ func (h *Handlers) GetParkingHistoryByIDs(c *gin.Context) {
 		},
 	})
 }
+
+// conflictErrorStatus maps concurrent update errors to 409, anything else to the fallback status
+func conflictErrorStatus(err error, fallback int) int {
+	if errors.Is(err, usecase.ErrConcurrentUpdate) {
+		return http.StatusConflict
+	}
+	return fallback
+}
+
+// ticketErrorStatus maps parking ticket errors to 401, anything else to the fallback status
+func ticketErrorStatus(err error, fallback int) int {
+	if errors.Is(err, usecase.ErrTicketRequired) || errors.Is(err, usecase.ErrInvalidTicket) {
+		return http.StatusUnauthorized
+	}
+	return fallback
+}
+
+// CreateReservation godoc
+// @Summary Reserve a parking slot
+// @Description Hold a vehicle-type slot in an area for the given plate and time window. The slot is released if nobody checks in within the grace period after start_time.
+// @Tags reservation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.CreateReservationRequest true "Reservation data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Router /api/v1/reservations [post]
+func (h *Handlers) CreateReservation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User not authenticated",
+		})
+		return
+	}
+
+	var req entities.CreateReservationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	reservation, err := h.ReservationUC.CreateReservation(userID.(uint), &req)
+	if err != nil {
+		h.Logger.Error("Failed to create reservation:", err)
+		c.JSON(checkinErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Reservation created successfully",
+		"data":    reservation,
+	})
+}
+
+// GetMyReservations godoc
+// @Summary Get my reservations
+// @Description List the current customer's reservations, latest start time first
+// @Tags reservation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Filter by status (reserved, consumed, expired, cancelled)"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/reservations [get]
+func (h *Handlers) GetMyReservations(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User not authenticated",
+		})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil {
+		limit = 10
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		offset = 0
+	}
+
+	var status *entities.ReservationStatus
+	if statusStr := c.Query("status"); statusStr != "" {
+		s := entities.ReservationStatus(statusStr)
+		status = &s
+	}
+
+	reservations, count, err := h.ReservationUC.GetMyReservations(userID.(uint), status, limit, offset)
+	if err != nil {
+		h.Logger.Error("Failed to get reservations:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Reservations retrieved successfully",
+		"data":    reservations,
+		"meta": gin.H{
+			"pagination": gin.H{
+				"limit":  limit,
+				"offset": offset,
+				"total":  count,
+			},
+		},
+	})
+}
+
+// GetMyReservation godoc
+// @Summary Get reservation detail
+// @Description Get one of the current customer's reservations, with the session that used it
+// @Tags reservation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/reservations/{id} [get]
+func (h *Handlers) GetMyReservation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User not authenticated",
+		})
+		return
+	}
+
+	reservationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid reservation ID",
+		})
+		return
+	}
+
+	reservation, err := h.ReservationUC.GetMyReservation(userID.(uint), uint(reservationID))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Reservation retrieved successfully",
+		"data":    reservation,
+	})
+}
+
+// CancelReservation godoc
+// @Summary Cancel reservation
+// @Description Cancel an open reservation and release its slot
+// @Tags reservation
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Reservation ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/reservations/{id}/cancel [post]
+func (h *Handlers) CancelReservation(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User not authenticated",
+		})
+		return
+	}
+
+	reservationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid reservation ID",
+		})
+		return
+	}
+
+	reservation, err := h.ReservationUC.CancelReservation(userID.(uint), uint(reservationID))
+	if err != nil {
+		h.Logger.Error("Failed to cancel reservation:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Reservation cancelled successfully",
+		"data":    reservation,
+	})
+}
@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"be-parkir/internal/domain/entities"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// GetMyVehicles godoc
+// @Summary Get my vehicles
+// @Description List the vehicles saved in the current customer's garage
+// @Tags vehicle
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/me/vehicles [get]
+func (h *Handlers) GetMyVehicles(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User not authenticated",
+		})
+		return
+	}
+
+	vehicles, err := h.ParkingUC.GetMyVehicles(userID.(uint))
+	if err != nil {
+		h.Logger.Error("Failed to get vehicles:", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Vehicles retrieved successfully",
+		"data":    vehicles,
+	})
+}
+
+// AddVehicle godoc
+// @Summary Save a vehicle
+// @Description Save a plate and vehicle type with a nickname to the current customer's garage
+// @Tags vehicle
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body entities.CreateVehicleRequest true "Vehicle data"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/me/vehicles [post]
+func (h *Handlers) AddVehicle(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User not authenticated",
+		})
+		return
+	}
+
+	var req entities.CreateVehicleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	vehicle, err := h.ParkingUC.AddVehicle(userID.(uint), &req)
+	if err != nil {
+		h.Logger.Error("Failed to save vehicle:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"message": "Vehicle saved successfully",
+		"data":    vehicle,
+	})
+}
+
+// UpdateVehicle godoc
+// @Summary Update a saved vehicle
+// @Description Change the nickname or vehicle type of a saved vehicle; the plate cannot be changed
+// @Tags vehicle
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Vehicle ID"
+// @Param request body entities.UpdateVehicleRequest true "Vehicle data"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/me/vehicles/{id} [put]
+func (h *Handlers) UpdateVehicle(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User not authenticated",
+		})
+		return
+	}
+
+	vehicleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid vehicle ID",
+		})
+		return
+	}
+
+	var req entities.UpdateVehicleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.Logger.Error("Failed to bind JSON:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid request data",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	// Validate request
+	validate := validator.New()
+	if err := validate.Struct(req); err != nil {
+		h.Logger.Error("Validation failed:", err)
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Validation failed",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	vehicle, err := h.ParkingUC.UpdateVehicle(userID.(uint), uint(vehicleID), &req)
+	if err != nil {
+		h.Logger.Error("Failed to update vehicle:", err)
+		c.JSON(vehicleErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Vehicle updated successfully",
+		"data":    vehicle,
+	})
+}
+
+// DeleteVehicle godoc
+// @Summary Delete a saved vehicle
+// @Description Remove a vehicle from the garage; its sessions stay in the history
+// @Tags vehicle
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Vehicle ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Router /api/v1/me/vehicles/{id} [delete]
+func (h *Handlers) DeleteVehicle(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "User not authenticated",
+		})
+		return
+	}
+
+	vehicleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Invalid vehicle ID",
+		})
+		return
+	}
+
+	if err := h.ParkingUC.DeleteVehicle(userID.(uint), uint(vehicleID)); err != nil {
+		h.Logger.Error("Failed to delete vehicle:", err)
+		c.JSON(vehicleErrorStatus(err), gin.H{
+			"success": false,
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"message": "Vehicle deleted successfully",
+	})
+}
+
+// vehicleErrorStatus maps saved vehicle errors to a status code: 404 for a vehicle the customer
+// does not have
+func vehicleErrorStatus(err error) int {
+	if err.Error() == "vehicle not found" {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
@@ -9,6 +9,6 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRoutes(router *gin.Engine, handlers *handler.Handlers, jwtConfig usecase.JWTConfig, apiKeyConfig *middleware.APIKeyConfig, corsConfig *middleware.CORSConfig) {
-	route.SetupRoutes(router, handlers, jwtConfig, apiKeyConfig, corsConfig)
+func SetupRoutes(router *gin.Engine, handlers *handler.Handlers, jwtConfig usecase.JWTConfig, apiKeyConfig *middleware.APIKeyConfig, corsConfig *middleware.CORSConfig, idempotencyConfig *middleware.IdempotencyConfig, mockPayments bool) {
+	route.SetupRoutes(router, handlers, jwtConfig, apiKeyConfig, corsConfig, idempotencyConfig, mockPayments)
 }
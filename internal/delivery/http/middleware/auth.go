@@ -22,48 +22,69 @@ func AuthMiddleware(authUC usecase.AuthUsecase) gin.HandlerFunc {
 			return
 		}
 
-		// Extract token from "Bearer <token>"
-		tokenParts := strings.Split(authHeader, " ")
-		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"message": "Invalid authorization header format",
-			})
-			c.Abort()
+		if !authenticate(c, authUC, authHeader) {
 			return
 		}
 
-		tokenString := tokenParts[1]
+		c.Next()
+	}
+}
 
-		// Validate token
-		token, err := authUC.ValidateToken(tokenString)
-		if err != nil || !token.Valid {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"message": "Invalid or expired token",
-			})
-			c.Abort()
+// OptionalAuthMiddleware sets user context when an Authorization header is sent and lets
+// anonymous requests through. A header with a bad token is still rejected.
+func OptionalAuthMiddleware(authUC usecase.AuthUsecase) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader != "" && !authenticate(c, authUC, authHeader) {
 			return
 		}
 
-		// Get user from token
-		user, err := authUC.GetUserFromToken(token)
-		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"success": false,
-				"message": "Invalid token claims",
-			})
-			c.Abort()
-			return
-		}
+		c.Next()
+	}
+}
 
-		// Set user context
-		c.Set("user_id", user.ID)
-		c.Set("user_role", user.Role)
-		c.Set("user", user)
+// authenticate validates the bearer token and sets user context, or aborts with 401
+func authenticate(c *gin.Context, authUC usecase.AuthUsecase, authHeader string) bool {
+	// Extract token from "Bearer <token>"
+	tokenParts := strings.Split(authHeader, " ")
+	if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Invalid authorization header format",
+		})
+		c.Abort()
+		return false
+	}
 
-		c.Next()
+	tokenString := tokenParts[1]
+
+	// Validate token
+	token, err := authUC.ValidateToken(tokenString)
+	if err != nil || !token.Valid {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Invalid or expired token",
+		})
+		c.Abort()
+		return false
 	}
+
+	// Get user from token
+	user, err := authUC.GetUserFromToken(token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"success": false,
+			"message": "Invalid token claims",
+		})
+		c.Abort()
+		return false
+	}
+
+	// Set user context
+	c.Set("user_id", user.ID)
+	c.Set("user_role", user.Role)
+	c.Set("user", user)
+	return true
 }
 
 // RoleMiddleware checks if user has required role
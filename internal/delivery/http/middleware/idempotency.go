@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	defaultIdempotencyTTL    = 24 * time.Hour
+	maxIdempotencyKeyLength  = 255
+)
+
+// IdempotencyConfig represents the idempotency configuration
+type IdempotencyConfig struct {
+	Store repository.IdempotencyRepository
+	TTL   time.Duration
+}
+
+// responseRecorder keeps a copy of the response body so it can be replayed
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware replays the stored response when a request is retried with the same
+// Idempotency-Key header. Requests without the header are passed through unchanged.
+func IdempotencyMiddleware(config *IdempotencyConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || config == nil || config.Store == nil {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Idempotency-Key is too long",
+			})
+			c.Abort()
+			return
+		}
+
+		ttl := config.TTL
+		if ttl <= 0 {
+			ttl = defaultIdempotencyTTL
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid request body",
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		requestHash, err := hashRequestBody(c.GetHeader("Content-Type"), body)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid request body",
+			})
+			c.Abort()
+			return
+		}
+		storeKey := idempotencyScope(c, key)
+
+		existing, err := config.Store.Reserve(storeKey, requestHash, ttl)
+		if err != nil {
+			// Redis unavailable: process the request without idempotency protection
+			c.Next()
+			return
+		}
+
+		if existing != nil {
+			switch {
+			case existing.RequestHash != requestHash:
+				c.JSON(http.StatusConflict, gin.H{
+					"success": false,
+					"message": "Idempotency-Key was already used with a different request body",
+				})
+			case !existing.Completed:
+				c.JSON(http.StatusConflict, gin.H{
+					"success": false,
+					"message": "A request with this Idempotency-Key is still being processed",
+				})
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(existing.StatusCode, existing.ContentType, existing.Body)
+			}
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+
+		c.Next()
+
+		// Only successful responses are replayed; failures free the key so the client can retry
+		status := recorder.Status()
+		if status < http.StatusOK || status >= http.StatusMultipleChoices {
+			_ = config.Store.Release(storeKey)
+			return
+		}
+
+		_ = config.Store.Complete(storeKey, &entities.IdempotencyRecord{
+			RequestHash: requestHash,
+			Completed:   true,
+			StatusCode:  status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		}, ttl)
+	}
+}
+
+// hashRequestBody fingerprints the request body to tell a retry from another request under the
+// same key. Multipart bodies (photo uploads) are hashed by their fields and file contents
+// rather than the raw bytes, since a retry is sent with a new boundary.
+func hashRequestBody(contentType string, body []byte) (string, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		hash := sha256.Sum256(body)
+		return hex.EncodeToString(hash[:]), nil
+	}
+
+	type formPart struct {
+		name, digest string
+	}
+	var parts []formPart
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		content := sha256.New()
+		if _, err := io.Copy(content, part); err != nil {
+			return "", err
+		}
+		parts = append(parts, formPart{
+			name:   part.FormName(),
+			digest: fmt.Sprintf("%q %q %x", part.FormName(), part.FileName(), content.Sum(nil)),
+		})
+	}
+
+	// Fields may come in another order; values of the same field keep theirs
+	sort.SliceStable(parts, func(i, j int) bool { return parts[i].name < parts[j].name })
+	digests := make([]string, len(parts))
+	for i, part := range parts {
+		digests[i] = part.digest
+	}
+	hash := sha256.Sum256([]byte(strings.Join(digests, "\n")))
+	return hex.EncodeToString(hash[:]), nil
+}
+
+// idempotencyScope keeps keys from different routes and users apart
+func idempotencyScope(c *gin.Context, key string) string {
+	scope := fmt.Sprintf("%s %s", c.Request.Method, c.FullPath())
+	if userID, exists := c.Get("user_id"); exists {
+		scope = fmt.Sprintf("%s user:%v", scope, userID)
+	}
+	hash := sha256.Sum256([]byte(scope + " " + key))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"bytes"
+	"mime/multipart"
+	"testing"
+)
+
+type formField struct {
+	name, filename, value string
+}
+
+// multipartBody encodes the fields the way a client would, with the given boundary
+func multipartBody(t *testing.T, boundary string, fields ...formField) (string, []byte) {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if err := writer.SetBoundary(boundary); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range fields {
+		if field.filename == "" {
+			if err := writer.WriteField(field.name, field.value); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		part, err := writer.CreateFormFile(field.name, field.filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(field.value))
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return writer.FormDataContentType(), body.Bytes()
+}
+
+func TestHashRequestBody(t *testing.T) {
+	upload := []formField{
+		{name: "plat_nomor", value: "B 1234 XYZ"},
+		{name: "vehicle_type", value: "mobil"},
+		{name: "photo", filename: "front.jpg", value: "jpeg bytes"},
+	}
+
+	tests := []struct {
+		name      string
+		retry     []formField
+		wantEqual bool
+	}{
+		{"same upload with a new boundary", upload, true},
+		{"fields in another order", []formField{upload[2], upload[1], upload[0]}, true},
+		{"another field value", []formField{upload[0], {name: "vehicle_type", value: "motor"}, upload[2]}, false},
+		{"another photo", []formField{upload[0], upload[1], {name: "photo", filename: "front.jpg", value: "other bytes"}}, false},
+		{"photo missing", upload[:2], false},
+	}
+
+	contentType, body := multipartBody(t, "first-boundary", upload...)
+	original, err := hashRequestBody(contentType, body)
+	if err != nil {
+		t.Fatalf("hashRequestBody: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contentType, body := multipartBody(t, "retry-boundary", tt.retry...)
+			got, err := hashRequestBody(contentType, body)
+			if err != nil {
+				t.Fatalf("hashRequestBody: %v", err)
+			}
+			if (got == original) != tt.wantEqual {
+				t.Errorf("hash equal to the original = %v, want %v", got == original, tt.wantEqual)
+			}
+		})
+	}
+
+	t.Run("JSON bodies are hashed as sent", func(t *testing.T) {
+		a, _ := hashRequestBody("application/json", []byte(`{"a":1}`))
+		b, _ := hashRequestBody("application/json", []byte(`{"a": 1}`))
+		if a == b {
+			t.Error("different JSON bodies hashed the same")
+		}
+	})
+
+	t.Run("malformed multipart body", func(t *testing.T) {
+		if _, err := hashRequestBody("multipart/form-data; boundary=x", []byte("--x\r\nbroken")); err == nil {
+			t.Error("hashRequestBody accepted a truncated multipart body")
+		}
+	})
+}
@@ -10,7 +10,7 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func SetupRoutes(router *gin.Engine, handlers *handler.Handlers, jwtConfig usecase.JWTConfig, apiKeyConfig *middleware.APIKeyConfig, corsConfig *middleware.CORSConfig) {
+func SetupRoutes(router *gin.Engine, handlers *handler.Handlers, jwtConfig usecase.JWTConfig, apiKeyConfig *middleware.APIKeyConfig, corsConfig *middleware.CORSConfig, idempotencyConfig *middleware.IdempotencyConfig, mockPayments bool) {
 	// Apply CORS middleware globally
 	router.Use(middleware.CORS(corsConfig))
 
@@ -25,6 +25,23 @@ func SetupRoutes(router *gin.Engine, handlers *handler.Handlers, jwtConfig useca
 		})
 	})
 
+	// E-karcis short links are printed on receipts and scanned by inspectors, so they are public
+	// and sit outside /api/v1 (no API key required)
+	receipts := router.Group("/r")
+	{
+		receipts.GET("/:code", handlers.GetReceipt)
+		receipts.GET("/:code/verify", handlers.VerifyReceipt)
+	}
+
+	// Payment gateways sign their callbacks instead of sending an API key
+	webhooks := router.Group("/webhooks")
+	{
+		webhooks.POST("/payments", handlers.PaymentCallback)
+	}
+
+	// Retried POSTs with the same Idempotency-Key replay the first response
+	idempotent := middleware.IdempotencyMiddleware(idempotencyConfig)
+
 	// API v1 routes with API key middleware
 	v1 := router.Group("/api/v1")
 	v1.Use(middleware.APIKeyMiddleware(apiKeyConfig))
@@ -47,17 +64,48 @@ func SetupRoutes(router *gin.Engine, handlers *handler.Handlers, jwtConfig useca
 			user.PUT("/profile", handlers.UpdateProfile)
 		}
 
-		// Parking routes (anonymous)
+		// Reservation routes (customers)
+		reservations := v1.Group("/reservations")
+		reservations.Use(middleware.AuthMiddleware(handlers.AuthUC), middleware.CustomerMiddleware())
+		{
+			reservations.POST("", idempotent, handlers.CreateReservation)
+			reservations.GET("", handlers.GetMyReservations)
+			reservations.GET("/:id", handlers.GetMyReservation)
+			reservations.POST("/:id/cancel", handlers.CancelReservation)
+		}
+
+		// Customer's own parking sessions and saved vehicles
+		me := v1.Group("/me")
+		me.Use(middleware.AuthMiddleware(handlers.AuthUC), middleware.CustomerMiddleware())
+		{
+			me.GET("/sessions", handlers.GetMySessions)
+			me.POST("/sessions/claim", handlers.ClaimSessions)
+			me.GET("/vehicles", handlers.GetMyVehicles)
+			me.POST("/vehicles", handlers.AddVehicle)
+			me.PUT("/vehicles/:id", handlers.UpdateVehicle)
+			me.DELETE("/vehicles/:id", handlers.DeleteVehicle)
+		}
+
+		// Parking routes (anonymous; a customer token links the session to the account)
+		optionalAuth := middleware.OptionalAuthMiddleware(handlers.AuthUC)
 		parking := v1.Group("/parking")
 		{
 			parking.GET("/locations", handlers.GetNearbyAreas)
-			parking.POST("/checkin", handlers.Checkin)
-			parking.POST("/checkout", handlers.Checkout)
+			parking.GET("/vehicle-types", handlers.GetActiveVehicleTypes)
+			parking.POST("/checkin", optionalAuth, idempotent, handlers.Checkin)
+			parking.POST("/checkout", optionalAuth, idempotent, handlers.Checkout)
 			parking.GET("/active/:id", handlers.GetActiveSession)
+			parking.GET("/payment", handlers.GetPaymentStatus)
 			parking.GET("/history", handlers.GetParkingHistory)
 			parking.POST("/history", handlers.GetParkingHistoryByIDs)
 		}
 
+		// Mock payment gateway (development only). Anyone with the API key could pay their own
+		// charges here, so it only exists while the mock gateway is configured.
+		if mockPayments {
+			v1.POST("/payments/mock/:charge_id/pay", handlers.SimulatePayment)
+		}
+
 		// Jukir routes
 		jukir := v1.Group("/jukir")
 		jukir.Use(middleware.AuthMiddleware(handlers.AuthUC), middleware.JukirMiddleware(handlers.JukirUC))
@@ -67,9 +115,14 @@ func SetupRoutes(router *gin.Engine, handlers *handler.Handlers, jwtConfig useca
 			jukir.GET("/active-sessions", handlers.GetActiveSessions)
 			jukir.GET("/vehicle-breakdown", handlers.GetVehicleBreakdown)
 			jukir.GET("/qr-code", handlers.GetQRCode)
+			jukir.GET("/qr-code/image", handlers.GetQRImage)
 			jukir.GET("/daily-report", handlers.GetDailyReport)
-			jukir.POST("/manual-checkin", handlers.ManualCheckin)
-			jukir.POST("/manual-checkout", handlers.ManualCheckout)
+			jukir.POST("/manual-checkin", idempotent, handlers.ManualCheckin)
+			jukir.POST("/manual-checkout", idempotent, handlers.ManualCheckout)
+			jukir.POST("/sync", handlers.SyncManualRecords)
+			jukir.GET("/lost-ticket/candidates", handlers.GetLostTicketCandidates)
+			jukir.POST("/lost-ticket/checkout", idempotent, handlers.LostTicketCheckout)
+			jukir.POST("/sessions/:id/void", handlers.RequestSessionVoid)
 			jukir.GET("/events", handlers.StreamJukirEvents) // SSE endpoint
 		}
 
@@ -87,6 +140,9 @@ func SetupRoutes(router *gin.Engine, handlers *handler.Handlers, jwtConfig useca
 			admin.DELETE("/jukirs/:id", handlers.DeleteJukir)
 			admin.POST("/jukirs/manual-revenue", handlers.AddManualRevenue)
 			admin.PUT("/jukirs/:id/status", handlers.UpdateJukirStatus)
+			admin.GET("/jukirs/:id/qr-code", handlers.GetJukirQRImage)
+			admin.POST("/jukirs/:id/qr-code/rotate", handlers.RotateJukirQRToken)
+			admin.GET("/jukirs/qr-sheet", handlers.GetJukirQRSheet)
 			admin.GET("/statistics/vehicles", handlers.GetVehicleStatistics)
 			admin.GET("/statistics/areas", handlers.GetParkingAreaStatistics)
 			admin.GET("/statistics/jukirs", handlers.GetJukirStatistics)
@@ -94,6 +150,20 @@ func SetupRoutes(router *gin.Engine, handlers *handler.Handlers, jwtConfig useca
 			admin.GET("/chart/data", handlers.GetChartDataDetailed)
 			admin.GET("/reports", handlers.GetReports)
 			admin.GET("/sessions", handlers.GetAllSessions)
+			admin.GET("/sessions/:id", handlers.GetSessionDetail)
+			admin.GET("/sessions/:id/void", handlers.GetSessionVoidRequests)
+			admin.POST("/sessions/:id/void/approve", handlers.ApproveSessionVoid)
+			admin.POST("/sessions/:id/void/reject", handlers.RejectSessionVoid)
+			admin.GET("/void-requests", handlers.GetVoidRequests)
+			admin.GET("/overstays", handlers.GetOverstayEvents)
+			admin.GET("/pass-products", handlers.GetPassProducts)
+			admin.POST("/pass-products", handlers.CreatePassProduct)
+			admin.PUT("/pass-products/:id", handlers.UpdatePassProduct)
+			admin.GET("/passes", handlers.GetPasses)
+			admin.POST("/passes", handlers.IssuePass)
+			admin.GET("/passes/:id", handlers.GetPassByID)
+			admin.POST("/passes/:id/renew", handlers.RenewPass)
+			admin.POST("/passes/:id/suspend", handlers.SuspendPass)
 			admin.GET("/areas", handlers.GetParkingAreas)
 			admin.GET("/areas/:id", handlers.GetParkingAreaDetail)
 			admin.GET("/areas/:id/status", handlers.GetParkingAreaStatus)
@@ -105,12 +175,31 @@ func SetupRoutes(router *gin.Engine, handlers *handler.Handlers, jwtConfig useca
 			admin.POST("/areas", handlers.CreateParkingArea)
 			admin.PUT("/areas/:id", handlers.UpdateParkingArea)
 			admin.DELETE("/areas/:id", handlers.DeleteParkingArea)
+			admin.GET("/areas/:id/tariffs", handlers.GetAreaTariffs)
+			admin.PUT("/areas/:id/tariffs", handlers.UpsertAreaTariff)
+			admin.DELETE("/areas/:id/tariffs/:vehicle_type", handlers.DeleteAreaTariff)
+			admin.GET("/areas/:id/operating-hours", handlers.GetOperatingHours)
+			admin.PUT("/areas/:id/operating-hours", handlers.SetOperatingHours)
+			admin.POST("/areas/:id/operating-hours/exceptions", handlers.CreateScheduleException)
+			admin.DELETE("/areas/:id/operating-hours/exceptions/:exception_id", handlers.DeleteScheduleException)
+			admin.GET("/vehicle-types", handlers.GetVehicleTypes)
+			admin.POST("/vehicle-types", handlers.CreateVehicleType)
+			admin.PUT("/vehicle-types/:code", handlers.UpdateVehicleType)
+			admin.GET("/holidays", handlers.GetHolidays)
+			admin.POST("/holidays", handlers.CreateHoliday)
+			admin.DELETE("/holidays/:id", handlers.DeleteHoliday)
 			admin.GET("/jukirs/activity", handlers.GetJukirActivity)
 			admin.GET("/jukirs/:id/activity", handlers.GetJukirActivityDetail)
 			admin.GET("/jukirs/:id/activity/export", handlers.ExportJukirActivityDetailXLSX)
 			admin.GET("/jukirs/activity/export", handlers.ExportJukirActivityCSV)
 			admin.GET("/revenue-table", handlers.GetRevenueTable)
 			admin.GET("/revenue/export", handlers.ExportRevenueReport)
+			admin.GET("/deposits", handlers.GetCashDeposits)
+			admin.POST("/deposits", handlers.RecordCashDeposit)
+			admin.GET("/deposits/expected", handlers.GetExpectedCash)
+			admin.GET("/deposits/outstanding", handlers.GetOutstandingBalances)
+			admin.GET("/deposits/outstanding/export", handlers.ExportOutstandingBalances)
+			admin.PUT("/deposits/:id", handlers.UpdateCashDeposit)
 			admin.POST("/import/areas-jukirs", handlers.ImportAreasAndJukirsFromCSV)
 			admin.GET("/activity-logs", handlers.GetActivityLogs)
 			admin.GET("/files/*path", handlers.DownloadFile)        // Proxy endpoint untuk download file dari MinIO
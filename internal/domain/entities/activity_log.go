@@ -25,14 +25,15 @@ type ActivityLogArea struct {
 
 // ActivityLogItem represents a single activity event (checkin/checkout).
 type ActivityLogItem struct {
-	EventTime   time.Time         `json:"event_time"`
-	EventType   ActivityEventType `json:"event_type"`
-	SessionID   *uint             `json:"session_id,omitempty"`
-	PlatNomor   *string           `json:"plat_nomor,omitempty"`
-	VehicleType string            `json:"vehicle_type"`
-	IsManual    bool              `json:"is_manual"`
-	Jukir       *ActivityLogJukir `json:"jukir,omitempty"`
-	Area        *ActivityLogArea  `json:"area,omitempty"`
+	EventTime   time.Time           `json:"event_time"`
+	EventType   ActivityEventType   `json:"event_type"`
+	SessionID   *uint               `json:"session_id,omitempty"`
+	PlatNomor   *string             `json:"plat_nomor,omitempty"`
+	VehicleType string              `json:"vehicle_type"`
+	IsManual    bool                `json:"is_manual"`
+	Jukir       *ActivityLogJukir   `json:"jukir,omitempty"`
+	Area        *ActivityLogArea    `json:"area,omitempty"`
+	Photos      []SessionAttachment `json:"photos,omitempty"` // vehicle photos taken at this event (manual records)
 }
 
 // ActivityLogMeta contains pagination and filter metadata for activity logs.
@@ -0,0 +1,88 @@
+package entities
+
+import "time"
+
+// CashDeposit is the cash a jukir handed over to the office for one day (setoran).
+// ExpectedAmount is the paid cash the jukir had confirmed for that day when the deposit was
+// recorded; Variance is DepositedAmount - ExpectedAmount, so a shortfall is negative.
+type CashDeposit struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	JukirID         uint      `json:"jukir_id" gorm:"not null;uniqueIndex:idx_cash_deposits_jukir_date"`
+	Date            time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_cash_deposits_jukir_date;index"` // tanggal penerimaan tunai (WIB)
+	ExpectedAmount  float64   `json:"expected_amount" gorm:"not null"`
+	DepositedAmount float64   `json:"deposited_amount" gorm:"not null"`
+	Variance        float64   `json:"variance" gorm:"not null"`
+	ReceivedBy      uint      `json:"received_by" gorm:"not null"` // Admin user ID
+	ReceivedAt      time.Time `json:"received_at" gorm:"not null"`
+	Notes           *string   `json:"notes,omitempty" gorm:"type:text"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	// Relations
+	Jukir    Jukir `json:"jukir" gorm:"foreignKey:JukirID"`
+	Receiver *User `json:"receiver,omitempty" gorm:"foreignKey:ReceivedBy"`
+}
+
+type RecordCashDepositRequest struct {
+	JukirID         uint    `json:"jukir_id" validate:"required"`
+	Date            string  `json:"date" validate:"required,datetime=02-01-2006"` // DD-MM-YYYY
+	DepositedAmount float64 `json:"deposited_amount" validate:"min=0"`
+	Notes           *string `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+// UpdateCashDepositRequest corrects a recorded deposit. The expected amount is taken again
+// from the day's payments.
+type UpdateCashDepositRequest struct {
+	DepositedAmount float64 `json:"deposited_amount" validate:"min=0"`
+	Notes           *string `json:"notes,omitempty" validate:"omitempty,max=500"`
+}
+
+type CashDepositFilter struct {
+	JukirID   *uint
+	Regional  *string
+	StartDate *time.Time
+	EndDate   *time.Time
+}
+
+// ExpectedCashResponse is the cash a jukir should hand over for a day, with the deposit
+// already recorded for it, if any
+type ExpectedCashResponse struct {
+	JukirID        uint         `json:"jukir_id"`
+	Date           string       `json:"date"` // YYYY-MM-DD
+	ExpectedAmount float64      `json:"expected_amount"`
+	PaymentCount   int          `json:"payment_count"`
+	Deposit        *CashDeposit `json:"deposit,omitempty"`
+}
+
+// JukirOutstandingBalance is the cash a jukir collected over a period and has not handed over
+type JukirOutstandingBalance struct {
+	JukirID         uint    `json:"jukir_id"`
+	JukirCode       string  `json:"jukir_code"`
+	JukirName       string  `json:"jukir_name"`
+	AreaName        string  `json:"area_name"`
+	Regional        string  `json:"regional"`
+	ExpectedAmount  float64 `json:"expected_amount"`
+	DepositedAmount float64 `json:"deposited_amount"`
+	Outstanding     float64 `json:"outstanding"`      // expected - deposited; negative when overpaid
+	CollectionDays  int     `json:"collection_days"`  // days with cash collected
+	UndepositedDays int     `json:"undeposited_days"` // days with cash collected and no deposit recorded
+	LastDeposit     *string `json:"last_deposit,omitempty"`
+}
+
+type RegionOutstandingBalance struct {
+	Regional        string  `json:"regional"`
+	JukirCount      int     `json:"jukir_count"`
+	ExpectedAmount  float64 `json:"expected_amount"`
+	DepositedAmount float64 `json:"deposited_amount"`
+	Outstanding     float64 `json:"outstanding"`
+}
+
+type CashOutstandingResponse struct {
+	StartDate       string                     `json:"start_date"` // YYYY-MM-DD
+	EndDate         string                     `json:"end_date"`   // YYYY-MM-DD
+	ExpectedAmount  float64                    `json:"expected_amount"`
+	DepositedAmount float64                    `json:"deposited_amount"`
+	Outstanding     float64                    `json:"outstanding"`
+	Jukirs          []JukirOutstandingBalance  `json:"jukirs"`
+	Regions         []RegionOutstandingBalance `json:"regions"`
+}
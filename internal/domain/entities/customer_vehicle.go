@@ -0,0 +1,26 @@
+package entities
+
+import "time"
+
+// CustomerVehicle is a plate a customer saved to their garage. Anyone can save any plate, so it
+// only filters the customer's own sessions and never shows other sessions parked under it.
+type CustomerVehicle struct {
+	ID          uint        `json:"id" gorm:"primaryKey"`
+	UserID      uint        `json:"user_id" gorm:"not null;uniqueIndex:idx_customer_vehicles_user_plate"` // Customer
+	PlatNomor   string      `json:"plat_nomor" gorm:"type:varchar(20);not null;uniqueIndex:idx_customer_vehicles_user_plate;index"`
+	VehicleType VehicleType `json:"vehicle_type" gorm:"type:varchar(20);not null"`
+	Nickname    string      `json:"nickname" gorm:"type:varchar(50)"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+type CreateVehicleRequest struct {
+	PlatNomor   string      `json:"plat_nomor" validate:"required,min=1,max=20"`
+	VehicleType VehicleType `json:"vehicle_type" validate:"required,min=2,max=20"`
+	Nickname    string      `json:"nickname" validate:"max=50"`
+}
+
+type UpdateVehicleRequest struct {
+	VehicleType *VehicleType `json:"vehicle_type,omitempty" validate:"omitempty,min=2,max=20"`
+	Nickname    *string      `json:"nickname,omitempty" validate:"omitempty,max=50"`
+}
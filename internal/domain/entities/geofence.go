@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidGeofence is returned for polygons that are not a usable GeoJSON Polygon
+var ErrInvalidGeofence = errors.New("invalid geofence")
+
+// GeoPolygon is a GeoJSON Polygon geometry. Positions are [longitude, latitude]; the first ring
+// is the boundary and any further rings are holes. Stored as JSONB.
+type GeoPolygon struct {
+	Type        string         `json:"type"`
+	Coordinates [][][2]float64 `json:"coordinates"`
+}
+
+// Validate checks the polygon is a GeoJSON Polygon whose rings are closed, have at least three
+// distinct corners and only valid coordinates
+func (p *GeoPolygon) Validate() error {
+	if p.Type != "Polygon" {
+		return fmt.Errorf("%w: type must be Polygon", ErrInvalidGeofence)
+	}
+	if len(p.Coordinates) == 0 {
+		return fmt.Errorf("%w: polygon has no rings", ErrInvalidGeofence)
+	}
+	for i, ring := range p.Coordinates {
+		if len(ring) < 4 {
+			return fmt.Errorf("%w: ring %d needs at least 4 positions", ErrInvalidGeofence, i)
+		}
+		if ring[0] != ring[len(ring)-1] {
+			return fmt.Errorf("%w: ring %d is not closed (first and last positions differ)", ErrInvalidGeofence, i)
+		}
+		for _, position := range ring {
+			lng, lat := position[0], position[1]
+			if lng < -180 || lng > 180 || lat < -90 || lat > 90 {
+				return fmt.Errorf("%w: position [%g, %g] is out of range", ErrInvalidGeofence, lng, lat)
+			}
+		}
+	}
+	return nil
+}
+
+func (p GeoPolygon) Value() (driver.Value, error) {
+	return json.Marshal(p)
+}
+
+func (p *GeoPolygon) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	case nil:
+		return nil
+	}
+	return fmt.Errorf("cannot scan %T into GeoPolygon", value)
+}
@@ -0,0 +1,11 @@
+package entities
+
+// IdempotencyRecord is the stored outcome of a request sent with an Idempotency-Key header.
+// While the first request is still running, Completed is false and there is no response yet.
+type IdempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
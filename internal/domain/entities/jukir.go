@@ -25,6 +25,12 @@ type Jukir struct {
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 
+	// The token replaced by the last rotation keeps working until PreviousQRTokenExpiresAt,
+	// so printed cards can be swapped without turning customers away
+	PreviousQRToken          *string    `json:"-" gorm:"index"`
+	PreviousQRTokenExpiresAt *time.Time `json:"previous_qr_token_expires_at,omitempty"`
+	QRRotatedAt              *time.Time `json:"qr_rotated_at,omitempty"`
+
 	// Relations
 	User     User             `json:"user" gorm:"foreignKey:UserID"`
 	Area     ParkingArea      `json:"area" gorm:"foreignKey:AreaID"`
@@ -54,18 +60,27 @@ type JukirDashboardResponse struct {
 }
 
 type JukirQRResponse struct {
-	QRToken string `json:"qr_token"`
-	Area    string `json:"area_name"`
-	Code    string `json:"jukir_code"`
+	QRToken                string     `json:"qr_token"`
+	Area                   string     `json:"area_name"`
+	Code                   string     `json:"jukir_code"`
+	PreviousTokenExpiresAt *time.Time `json:"previous_token_expires_at,omitempty"` // the replaced token is accepted until then
+}
+
+// RotateQRTokenRequest replaces a jukir's QR token. With a grace period the old token keeps
+// working for that many minutes; without one it stops working immediately.
+type RotateQRTokenRequest struct {
+	GracePeriodMinutes int `json:"grace_period_minutes" validate:"omitempty,min=0,max=10080"`
 }
 	
 type VehicleBreakdownResponse struct {
-	VehiclesIn     int `json:"vehicles_in"`
-	VehiclesOut    int `json:"vehicles_out"`
-	VehiclesByType map[string]struct {
-		In  int `json:"in"`
-		Out int `json:"out"`
-	} `json:"vehicles_by_type"`
+	VehiclesIn     int                     `json:"vehicles_in"`
+	VehiclesOut    int                     `json:"vehicles_out"`
+	VehiclesByType map[string]VehicleCount `json:"vehicles_by_type"`
+}
+
+type VehicleCount struct {
+	In  int `json:"in"`
+	Out int `json:"out"`
 }
 
 type CreateJukirResponse struct {
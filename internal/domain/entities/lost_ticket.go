@@ -0,0 +1,45 @@
+package entities
+
+import "time"
+
+// LostTicketCandidate is an active session a jukir can pick when a customer has lost their ticket
+type LostTicketCandidate struct {
+	SessionID     uint        `json:"session_id"`
+	PlatNomor     *string     `json:"plat_nomor,omitempty"`
+	VehicleType   VehicleType `json:"vehicle_type"`
+	CheckinTime   time.Time   `json:"checkin_time"`
+	Duration      int         `json:"duration"` // in minutes
+	EstimatedCost float64     `json:"estimated_cost"`
+	Penalty       float64     `json:"penalty"`
+}
+
+type LostTicketCheckoutRequest struct {
+	SessionID uint     `json:"session_id" validate:"required"`
+	Latitude  *float64 `json:"latitude" validate:"required,latitude"`
+	Longitude *float64 `json:"longitude" validate:"required,longitude"`
+}
+
+type LostTicketCheckoutResponse struct {
+	SessionID     uint      `json:"session_id"`
+	PlatNomor     string    `json:"plat_nomor"`
+	VehicleType   string    `json:"vehicle_type"`
+	CheckinTime   time.Time `json:"checkin_time"`
+	CheckoutTime  time.Time `json:"checkout_time"`
+	Duration      int       `json:"duration"` // in minutes
+	ParkingCost   float64   `json:"parking_cost"`
+	Penalty       float64   `json:"penalty"`
+	TotalCost     float64   `json:"total_cost"`
+	PaymentStatus string    `json:"payment_status"`
+	ReceiptURL    string    `json:"receipt_url,omitempty"` // public e-karcis link
+}
+
+// LostTicketReportEntry is one lost-ticket checkout in the admin reports
+type LostTicketReportEntry struct {
+	SessionID    uint        `json:"session_id"`
+	PlatNomor    *string     `json:"plat_nomor,omitempty"`
+	VehicleType  VehicleType `json:"vehicle_type"`
+	AreaID       uint        `json:"area_id"`
+	JukirID      *uint       `json:"jukir_id,omitempty"`
+	CheckoutTime *time.Time  `json:"checkout_time,omitempty"`
+	Penalty      float64     `json:"penalty"`
+}
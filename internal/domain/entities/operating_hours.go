@@ -0,0 +1,180 @@
+package entities
+
+import "time"
+
+// AreaOperatingHours is an area's opening window on one day of the week (jam operasional).
+// Windows that close before they open run past midnight (e.g. 18:00-02:00); an open time
+// equal to the close time means open all day.
+type AreaOperatingHours struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	AreaID    uint      `json:"area_id" gorm:"not null;uniqueIndex:idx_area_operating_hours_area_day"`
+	DayOfWeek int       `json:"day_of_week" gorm:"not null;uniqueIndex:idx_area_operating_hours_area_day"` // 0 = Minggu ... 6 = Sabtu
+	OpenTime  string    `json:"open_time" gorm:"type:varchar(5);not null"`                                 // HH:MM (WIB)
+	CloseTime string    `json:"close_time" gorm:"type:varchar(5);not null"`                                // HH:MM (WIB)
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AreaScheduleException replaces the weekly hours on one date, e.g. closed for Lebaran or
+// open late for an event
+type AreaScheduleException struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	AreaID    uint      `json:"area_id" gorm:"not null;uniqueIndex:idx_area_schedule_exceptions_area_date"`
+	Date      time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_area_schedule_exceptions_area_date"`
+	IsClosed  bool      `json:"is_closed" gorm:"not null;default:false"`
+	OpenTime  *string   `json:"open_time,omitempty" gorm:"type:varchar(5)"`  // HH:MM (WIB), when not closed
+	CloseTime *string   `json:"close_time,omitempty" gorm:"type:varchar(5)"` // HH:MM (WIB), when not closed
+	Reason    string    `json:"reason" gorm:"type:varchar(255)"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type OperatingHoursRequest struct {
+	DayOfWeek int    `json:"day_of_week" validate:"min=0,max=6"`
+	OpenTime  string `json:"open_time" validate:"required,datetime=15:04"`
+	CloseTime string `json:"close_time" validate:"required,datetime=15:04"`
+}
+
+// SetOperatingHoursRequest replaces an area's weekly hours. Days left out are closed; an empty
+// list removes the schedule so status_operasional is only changed by hand again.
+type SetOperatingHoursRequest struct {
+	Hours         []OperatingHoursRequest `json:"hours" validate:"dive"`
+	HoursOverride *bool                   `json:"hours_override,omitempty"`
+}
+
+type CreateScheduleExceptionRequest struct {
+	Date      string  `json:"date" validate:"required,datetime=02-01-2006"` // DD-MM-YYYY
+	IsClosed  bool    `json:"is_closed"`
+	OpenTime  *string `json:"open_time,omitempty" validate:"required_if=IsClosed false,omitempty,datetime=15:04"`
+	CloseTime *string `json:"close_time,omitempty" validate:"required_if=IsClosed false,omitempty,datetime=15:04"`
+	Reason    string  `json:"reason" validate:"max=255"`
+}
+
+type OperatingHoursResponse struct {
+	AreaID            uint                    `json:"area_id"`
+	StatusOperasional string                  `json:"status_operasional"`
+	HoursOverride     bool                    `json:"hours_override"`
+	IsOpen            bool                    `json:"is_open"`
+	Weekly            []AreaOperatingHours    `json:"weekly"`
+	Exceptions        []AreaScheduleException `json:"exceptions"`
+}
+
+// OperatingSchedule is an area's weekly hours with the exceptions around the moment being checked
+type OperatingSchedule struct {
+	Weekly     []AreaOperatingHours
+	Exceptions []AreaScheduleException
+}
+
+// operatingWindow is one day's opening window in minutes after midnight
+type operatingWindow struct {
+	open, close int
+	closed      bool
+}
+
+// covers reports whether the minute of the window's own day is inside it
+func (w operatingWindow) covers(minute int) bool {
+	if w.closed {
+		return false
+	}
+	if w.open == w.close {
+		return true
+	}
+	if w.open < w.close {
+		return minute >= w.open && minute < w.close
+	}
+	return minute >= w.open
+}
+
+// spillsInto reports whether an overnight window is still open at the minute of the next day
+func (w operatingWindow) spillsInto(minute int) bool {
+	return !w.closed && w.open > w.close && minute < w.close
+}
+
+// windowOn returns the window for t's date: the exception for the date if there is one, else
+// the weekly hours. known is false when the schedule says nothing about the date, in which case
+// the zero window (open all day) is returned.
+func (s OperatingSchedule) windowOn(t time.Time) (window operatingWindow, known bool) {
+	date := t.Format("2006-01-02")
+	for _, exception := range s.Exceptions {
+		if exception.Date.Format("2006-01-02") != date {
+			continue
+		}
+		if exception.IsClosed || exception.OpenTime == nil || exception.CloseTime == nil {
+			return operatingWindow{closed: true}, true
+		}
+		return parseWindow(*exception.OpenTime, *exception.CloseTime), true
+	}
+
+	if len(s.Weekly) == 0 {
+		return operatingWindow{}, false
+	}
+	for _, hours := range s.Weekly {
+		if hours.DayOfWeek == int(t.Weekday()) {
+			return parseWindow(hours.OpenTime, hours.CloseTime), true
+		}
+	}
+	return operatingWindow{closed: true}, true
+}
+
+// OpenAt reports whether the area is open at t, which should be in WIB. Without weekly hours
+// the area counts as open all day on dates without an exception. scheduled is false when the
+// schedule has no say at t (no weekly hours and no exception today or yesterday).
+func (s OperatingSchedule) OpenAt(t time.Time) (open, scheduled bool) {
+	today, todayKnown := s.windowOn(t)
+	yesterday, yesterdayKnown := s.windowOn(t.AddDate(0, 0, -1))
+	if !todayKnown && !yesterdayKnown {
+		return false, false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	if today.covers(minute) {
+		return true, true
+	}
+	if yesterdayKnown && yesterday.spillsInto(minute) {
+		return true, true
+	}
+	return false, true
+}
+
+func parseWindow(openTime, closeTime string) operatingWindow {
+	openMinute, err := minuteOfDay(openTime)
+	if err != nil {
+		return operatingWindow{closed: true}
+	}
+	closeMinute, err := minuteOfDay(closeTime)
+	if err != nil {
+		return operatingWindow{closed: true}
+	}
+	return operatingWindow{open: openMinute, close: closeMinute}
+}
+
+// closingLookaheadDays bounds how far past t NextClosing looks for the area to close
+const closingLookaheadDays = 8
+
+// NextClosing returns the first moment after t (in WIB) at which the area stops being open by
+// its schedule. A window ending where the next one starts is not a closing. ok is false when
+// the schedule sets no closing within closingLookaheadDays, e.g. without weekly hours or when
+// open all day every day.
+func (s OperatingSchedule) NextClosing(t time.Time) (closesAt time.Time, ok bool) {
+	for offset := -1; offset <= closingLookaheadDays; offset++ {
+		day := time.Date(t.Year(), t.Month(), t.Day()+offset, 0, 0, 0, 0, t.Location())
+		window, known := s.windowOn(day)
+		if !known || window.closed {
+			continue
+		}
+
+		end := day.Add(time.Duration(window.close) * time.Minute)
+		if window.open >= window.close {
+			// Overnight, or all day until the next midnight
+			end = end.AddDate(0, 0, 1)
+		}
+		if !end.After(t) || (ok && !end.Before(closesAt)) {
+			continue
+		}
+		if open, _ := s.OpenAt(end); open {
+			continue
+		}
+		closesAt, ok = end, true
+	}
+	return closesAt, ok
+}
@@ -0,0 +1,118 @@
+package entities
+
+import (
+	"testing"
+	"time"
+)
+
+var wib = time.FixedZone("WIB", 7*60*60)
+
+func at(date, clock string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02 15:04", date+" "+clock, wib)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func weekly(day int, open, close string) AreaOperatingHours {
+	return AreaOperatingHours{DayOfWeek: day, OpenTime: open, CloseTime: close}
+}
+
+func exception(date string, open, close string) AreaScheduleException {
+	d, err := time.ParseInLocation("2006-01-02", date, wib)
+	if err != nil {
+		panic(err)
+	}
+	if open == "" {
+		return AreaScheduleException{Date: d, IsClosed: true}
+	}
+	return AreaScheduleException{Date: d, OpenTime: &open, CloseTime: &close}
+}
+
+// 2026-10-15 is a Thursday, 10-16 a Friday, 10-17 a Saturday and 10-18 a Sunday
+var (
+	nightlyWeekend = OperatingSchedule{Weekly: []AreaOperatingHours{
+		weekly(5, "18:00", "02:00"),
+		weekly(6, "18:00", "02:00"),
+	}}
+	allDay = OperatingSchedule{Weekly: []AreaOperatingHours{
+		weekly(0, "00:00", "00:00"), weekly(1, "00:00", "00:00"), weekly(2, "00:00", "00:00"),
+		weekly(3, "00:00", "00:00"), weekly(4, "00:00", "00:00"), weekly(5, "00:00", "00:00"),
+		weekly(6, "00:00", "00:00"),
+	}}
+)
+
+func withExceptions(s OperatingSchedule, exceptions ...AreaScheduleException) OperatingSchedule {
+	s.Exceptions = exceptions
+	return s
+}
+
+func TestOperatingScheduleOpenAt(t *testing.T) {
+	tests := []struct {
+		name          string
+		schedule      OperatingSchedule
+		at            time.Time
+		wantOpen      bool
+		wantScheduled bool
+	}{
+		{"before the evening window", nightlyWeekend, at("2026-10-16", "17:59"), false, true},
+		{"window opens", nightlyWeekend, at("2026-10-16", "18:00"), true, true},
+		{"past midnight inside the overnight window", nightlyWeekend, at("2026-10-17", "01:30"), true, true},
+		{"overnight window closes", nightlyWeekend, at("2026-10-17", "02:00"), false, true},
+		{"Sunday morning from Saturday's window", nightlyWeekend, at("2026-10-18", "01:00"), true, true},
+		{"day without weekly hours is closed", nightlyWeekend, at("2026-10-18", "12:00"), false, true},
+		{"closed exception", withExceptions(nightlyWeekend, exception("2026-10-17", "", "")), at("2026-10-17", "19:00"), false, true},
+		{"closed exception still lets the previous night run out", withExceptions(nightlyWeekend, exception("2026-10-17", "", "")), at("2026-10-17", "01:00"), true, true},
+		{"closed exception ends its own overnight window", withExceptions(nightlyWeekend, exception("2026-10-17", "", "")), at("2026-10-18", "01:00"), false, true},
+		{"exception opens a closed day", withExceptions(nightlyWeekend, exception("2026-10-15", "10:00", "14:00")), at("2026-10-15", "12:00"), true, true},
+		{"exception hours replace the weekly window", withExceptions(nightlyWeekend, exception("2026-10-16", "18:00", "23:00")), at("2026-10-16", "23:30"), false, true},
+		{"open all day", allDay, at("2026-10-16", "03:00"), true, true},
+		{"no hours at all", OperatingSchedule{}, at("2026-10-16", "12:00"), false, false},
+		{"exception without weekly hours", withExceptions(OperatingSchedule{}, exception("2026-10-16", "", "")), at("2026-10-16", "12:00"), false, true},
+		{"date away from the only exception", withExceptions(OperatingSchedule{}, exception("2026-10-16", "", "")), at("2026-10-18", "12:00"), false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			open, scheduled := tt.schedule.OpenAt(tt.at)
+			if open != tt.wantOpen || scheduled != tt.wantScheduled {
+				t.Errorf("OpenAt(%s) = (%v, %v), want (%v, %v)", tt.at.Format(time.RFC3339), open, scheduled, tt.wantOpen, tt.wantScheduled)
+			}
+		})
+	}
+}
+
+func TestOperatingScheduleNextClosing(t *testing.T) {
+	chained := OperatingSchedule{Weekly: []AreaOperatingHours{
+		weekly(5, "18:00", "00:00"),
+		weekly(6, "00:00", "02:00"),
+	}}
+
+	tests := []struct {
+		name     string
+		schedule OperatingSchedule
+		from     time.Time
+		want     time.Time // zero = no closing
+	}{
+		{"inside an overnight window", nightlyWeekend, at("2026-10-16", "20:00"), at("2026-10-17", "02:00")},
+		{"after midnight in the same window", nightlyWeekend, at("2026-10-17", "01:00"), at("2026-10-17", "02:00")},
+		{"before the window opens", nightlyWeekend, at("2026-10-16", "10:00"), at("2026-10-17", "02:00")},
+		{"after the last closing of the week", nightlyWeekend, at("2026-10-18", "03:00"), at("2026-10-24", "02:00")},
+		{"window running into the next day's window", chained, at("2026-10-16", "20:00"), at("2026-10-17", "02:00")},
+		{"exception closes earlier", withExceptions(nightlyWeekend, exception("2026-10-16", "18:00", "23:00")), at("2026-10-16", "20:00"), at("2026-10-16", "23:00")},
+		{"closed exception skips the day", withExceptions(nightlyWeekend, exception("2026-10-17", "", "")), at("2026-10-17", "03:00"), at("2026-10-24", "02:00")},
+		{"open all day never closes", allDay, at("2026-10-16", "12:00"), time.Time{}},
+		{"exception closes an all-day area", withExceptions(allDay, exception("2026-10-17", "", "")), at("2026-10-16", "12:00"), at("2026-10-17", "00:00")},
+		{"no weekly hours", OperatingSchedule{}, at("2026-10-16", "12:00"), time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.schedule.NextClosing(tt.from)
+			if ok != !tt.want.IsZero() || (ok && !got.Equal(tt.want)) {
+				t.Errorf("NextClosing(%s) = (%s, %v), want %s", tt.from.Format(time.RFC3339), got.Format(time.RFC3339), ok, tt.want.Format(time.RFC3339))
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package entities
+
+import "time"
+
+type OverstayReason string
+
+const (
+	OverstayReasonMaxDuration OverstayReason = "max_duration" // melewati batas lama parkir
+	OverstayReasonClosingTime OverstayReason = "closing_time" // masih parkir setelah jam tutup area
+)
+
+type OverstayAction string
+
+const (
+	OverstayActionAlert         OverstayAction = "alert"
+	OverstayActionAutoCompleted OverstayAction = "auto_completed"
+)
+
+// OverstayEvent is the audit record of what the overstay scheduler did with a session.
+// A session gets at most one event, so alerts are not repeated on every run.
+type OverstayEvent struct {
+	ID         uint           `json:"id" gorm:"primaryKey"`
+	SessionID  uint           `json:"session_id" gorm:"not null;uniqueIndex"`
+	AreaID     uint           `json:"area_id" gorm:"not null;index"`
+	JukirID    *uint          `json:"jukir_id,omitempty"`
+	Reason     OverstayReason `json:"reason" gorm:"type:varchar(20);not null"`
+	Action     OverstayAction `json:"action" gorm:"type:varchar(20);not null"`
+	Deadline   time.Time      `json:"deadline" gorm:"not null"`
+	DetectedAt time.Time      `json:"detected_at" gorm:"not null"`
+	CreatedAt  time.Time      `json:"created_at"`
+
+	// Relations
+	Session ParkingSession `json:"session" gorm:"foreignKey:SessionID"`
+	Area    ParkingArea    `json:"area" gorm:"foreignKey:AreaID"`
+}
+
+// OverstayRunResult summarises one run of the overstay scheduler
+type OverstayRunResult struct {
+	Checked       int `json:"checked"`
+	Alerted       int `json:"alerted"`
+	AutoCompleted int `json:"auto_completed"`
+	Failed        int `json:"failed"`
+}
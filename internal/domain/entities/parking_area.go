@@ -22,6 +22,29 @@ const (
 	JenisAreaMix     JenisArea = "mix"
 )
 
+// Nilai status_operasional
+const (
+	StatusOperasionalBuka        = "buka"
+	StatusOperasionalTutup       = "tutup"
+	StatusOperasionalMaintenance = "maintenance"
+)
+
+// CapacityPolicy decides what check-in does once an area is full for a vehicle type
+type CapacityPolicy string
+
+const (
+	CapacityPolicyReject   CapacityPolicy = "reject"   // tolak check-in
+	CapacityPolicyOverflow CapacityPolicy = "overflow" // terima check-in dengan peringatan
+)
+
+// OverstayPolicy decides what the overstay scheduler does with a session past its deadline
+type OverstayPolicy string
+
+const (
+	OverstayPolicyAlert        OverstayPolicy = "alert"         // kirim peringatan ke jukir
+	OverstayPolicyAutoComplete OverstayPolicy = "auto_complete" // tutup sesi otomatis oleh sistem
+)
+
 type ParkingArea struct {
 	ID                uint           `json:"id" gorm:"primaryKey"`
 	Name              string         `json:"name" gorm:"not null" validate:"required,min=2,max=100"`
@@ -37,13 +60,47 @@ type ParkingArea struct {
 	MaxMotor          *int           `json:"max_motor,omitempty" gorm:"type:int;default:0"`
 	StatusOperasional string         `json:"status_operasional" gorm:"type:varchar(20);not null;default:'buka'" validate:"required,oneof=buka tutup maintenance"`
 	JenisArea         JenisArea      `json:"jenis_area" gorm:"type:varchar(10);not null;default:'outdoor'" validate:"required,oneof=indoor outdoor mix"`
+	CapacityPolicy    CapacityPolicy `json:"capacity_policy" gorm:"type:varchar(10);not null;default:'reject'" validate:"required,oneof=reject overflow"`
+	MaxDuration       *int           `json:"max_duration,omitempty"`                        // menit; nil/0 = tanpa batas
+	ClosingTime       *string        `json:"closing_time,omitempty" gorm:"type:varchar(5)"` // HH:MM (WIB); batas overstay saja, tidak menutup check-in
+	OverstayPolicy    OverstayPolicy `json:"overstay_policy" gorm:"type:varchar(20);not null;default:'alert'" validate:"required,oneof=alert auto_complete"`
+	LostTicketPenalty float64        `json:"lost_ticket_penalty" gorm:"not null;default:0" validate:"min=0"`
+	Geofence          *GeoPolygon    `json:"geofence,omitempty" gorm:"type:jsonb"`         // batas area; diutamakan di atas radius
+	GeofenceRadius    *float64       `json:"geofence_radius,omitempty"`                    // meter dari titik area; nil = radius default
+	HoursOverride     bool           `json:"hours_override" gorm:"not null;default:false"` // status_operasional diatur manual, jam operasional diabaikan
 	CreatedAt         time.Time      `json:"created_at"`
 	UpdatedAt         time.Time      `json:"updated_at"`
 	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`
 
+	// Diisi repository dari latitude/longitude; indeks prefix untuk pencarian lokasi terdekat
+	Geohash string `json:"-" gorm:"type:varchar(12);index:idx_parking_areas_geohash,expression:geohash varchar_pattern_ops"`
+
 	// Relations
-	Jukirs   []Jukir          `json:"jukirs,omitempty" gorm:"foreignKey:AreaID"`
-	Sessions []ParkingSession `json:"sessions,omitempty" gorm:"foreignKey:AreaID"`
+	Jukirs          []Jukir           `json:"jukirs,omitempty" gorm:"foreignKey:AreaID"`
+	Sessions        []ParkingSession  `json:"sessions,omitempty" gorm:"foreignKey:AreaID"`
+	TariffSchedules []TariffSchedule  `json:"tariff_schedules,omitempty" gorm:"foreignKey:AreaID"`
+	VehicleRates    []AreaVehicleRate `json:"vehicle_rates,omitempty" gorm:"foreignKey:AreaID"`
+
+	// Jam operasional; pengecualian hanya diisi untuk tanggal mendatang
+	OperatingHours     []AreaOperatingHours    `json:"operating_hours,omitempty" gorm:"foreignKey:AreaID"`
+	ScheduleExceptions []AreaScheduleException `json:"schedule_exceptions,omitempty" gorm:"foreignKey:AreaID"`
+
+	// Sisa slot dan tarif per jenis kendaraan, jarak dan status buka, hanya diisi untuk daftar lokasi parkir
+	Availability []VehicleAvailability `json:"availability,omitempty" gorm:"-"`
+	DistanceM    *float64              `json:"distance_m,omitempty" gorm:"-"`
+	IsOpen       *bool                 `json:"is_open,omitempty" gorm:"-"`
+}
+
+// VehicleAvailability is the live occupancy of an area for one vehicle type.
+// Capacity and Remaining are nil when the area has no limit for the type.
+type VehicleAvailability struct {
+	VehicleType   VehicleType `json:"vehicle_type"`
+	Capacity      *int        `json:"capacity"`
+	Occupied      int64       `json:"occupied"`
+	Remaining     *int64      `json:"remaining"`
+	IsFull        bool        `json:"is_full"`
+	FirstHourRate float64     `json:"first_hour_rate"` // tariff in effect now
+	HourlyRate    float64     `json:"hourly_rate"`
 }
 
 type CreateParkingAreaRequest struct {
@@ -59,6 +116,21 @@ type CreateParkingAreaRequest struct {
 	MaxMotor          *int      `json:"max_motor,omitempty" validate:"omitempty,min=0"`
 	StatusOperasional string    `json:"status_operasional" validate:"required,oneof=buka tutup maintenance"`
 	JenisArea         JenisArea `json:"jenis_area" validate:"required,oneof=indoor outdoor mix"`
+	// kosong = reject
+	CapacityPolicy CapacityPolicy `json:"capacity_policy,omitempty" validate:"omitempty,oneof=reject overflow"`
+	// batas lama parkir (menit) dan jam tutup untuk deteksi overstay; kosong = alert
+	MaxDuration    *int           `json:"max_duration,omitempty" validate:"omitempty,min=0"`
+	ClosingTime    *string        `json:"closing_time,omitempty" validate:"omitempty,datetime=15:04"`
+	OverstayPolicy OverstayPolicy `json:"overstay_policy,omitempty" validate:"omitempty,oneof=alert auto_complete"`
+	// denda karcis hilang, ditagih sebagai baris pembayaran terpisah
+	LostTicketPenalty float64 `json:"lost_ticket_penalty,omitempty" validate:"omitempty,min=0"`
+	// batas lokasi check-in: poligon GeoJSON atau radius (meter); untuk form-data poligon dikirim sebagai JSON string
+	Geofence       *GeoPolygon `json:"geofence,omitempty"`
+	GeofenceRadius *float64    `json:"geofence_radius,omitempty" validate:"omitempty,min=10,max=5000"`
+	// jadwal tarif malam/akhir pekan/libur; untuk form-data dikirim sebagai JSON string
+	TariffSchedules []TariffScheduleRequest `json:"tariff_schedules,omitempty" validate:"omitempty,dive"`
+	// tarif & kapasitas jenis kendaraan lain dari registry (truk, bus, ...)
+	VehicleRates []AreaVehicleRateRequest `json:"vehicle_rates,omitempty" validate:"omitempty,dive"`
 }
 
 type UpdateParkingAreaRequest struct {
@@ -76,12 +148,31 @@ type UpdateParkingAreaRequest struct {
 	MaxMotor          *int        `json:"max_motor,omitempty" validate:"omitempty,min=0"`
 	StatusOperasional *string     `json:"status_operasional,omitempty" validate:"omitempty,oneof=buka tutup maintenance"`
 	JenisArea         *JenisArea  `json:"jenis_area,omitempty" validate:"omitempty,oneof=indoor outdoor mix"`
+	// nil = kebijakan kapasitas tidak diubah
+	CapacityPolicy *CapacityPolicy `json:"capacity_policy,omitempty" validate:"omitempty,oneof=reject overflow"`
+	// nil = tidak diubah; max_duration 0 dan closing_time "" menghapus batas
+	MaxDuration    *int            `json:"max_duration,omitempty" validate:"omitempty,min=0"`
+	ClosingTime    *string         `json:"closing_time,omitempty" validate:"omitempty,datetime=15:04|eq="`
+	OverstayPolicy *OverstayPolicy `json:"overstay_policy,omitempty" validate:"omitempty,oneof=alert auto_complete"`
+	// nil = denda karcis hilang tidak diubah
+	LostTicketPenalty *float64 `json:"lost_ticket_penalty,omitempty" validate:"omitempty,min=0"`
+	// nil = tidak diubah; clear_geofence menghapus poligon, geofence_radius 0 kembali ke radius default
+	Geofence       *GeoPolygon `json:"geofence,omitempty"`
+	ClearGeofence  bool        `json:"clear_geofence,omitempty"`
+	GeofenceRadius *float64    `json:"geofence_radius,omitempty" validate:"omitempty,max=5000,eq=0|min=10"`
+	// nil = jadwal tidak diubah, array kosong = hapus semua jadwal
+	TariffSchedules *[]TariffScheduleRequest `json:"tariff_schedules,omitempty" validate:"omitempty,dive"`
+	// nil = tidak diubah, array kosong = hapus semua tarif jenis kendaraan lain
+	VehicleRates *[]AreaVehicleRateRequest `json:"vehicle_rates,omitempty" validate:"omitempty,dive"`
 }
 
 type NearbyAreasRequest struct {
-	Latitude  *float64 `json:"latitude,omitempty" validate:"omitempty,latitude"`
-	Longitude *float64 `json:"longitude,omitempty" validate:"omitempty,longitude"`
-	Radius    float64  `json:"radius" validate:"omitempty,min=0.1,max=10"`
+	Latitude    *float64     `json:"latitude,omitempty" validate:"omitempty,latitude"`
+	Longitude   *float64     `json:"longitude,omitempty" validate:"omitempty,longitude"`
+	Radius      float64      `json:"radius" validate:"omitempty,min=0.1,max=10"`
+	JenisArea   *JenisArea   `json:"jenis_area,omitempty" validate:"omitempty,oneof=indoor outdoor mix"`
+	VehicleType *VehicleType `json:"vehicle_type,omitempty" validate:"omitempty,min=2,max=20"` // only areas that accept this type
+	OpenNow     bool         `json:"open_now,omitempty"`
 }
 
 type NearbyAreasResponse struct {
@@ -91,8 +182,49 @@ type NearbyAreasResponse struct {
 
 // GetRateByVehicleType returns the appropriate rate based on vehicle type
 func (p *ParkingArea) GetRateByVehicleType(vehicleType VehicleType) float64 {
-	if vehicleType == VehicleTypeMobil {
+	switch vehicleType {
+	case VehicleTypeMobil:
 		return p.HourlyRateMobil
+	case VehicleTypeMotor:
+		return p.HourlyRateMotor
+	}
+	if rate := p.findVehicleRate(vehicleType); rate != nil {
+		return rate.HourlyRate
+	}
+	return 0
+}
+
+// GetCapacityByVehicleType returns the configured capacity for the vehicle type, nil if unset
+func (p *ParkingArea) GetCapacityByVehicleType(vehicleType VehicleType) *int {
+	switch vehicleType {
+	case VehicleTypeMobil:
+		return p.MaxMobil
+	case VehicleTypeMotor:
+		return p.MaxMotor
+	}
+	if rate := p.findVehicleRate(vehicleType); rate != nil {
+		return rate.Capacity
 	}
-	return p.HourlyRateMotor
+	return nil
+}
+
+// AcceptsVehicleType reports whether the area is set up for the vehicle type.
+// mobil and motor are always accepted; other types need an AreaVehicleRate (VehicleRates must be loaded).
+func (p *ParkingArea) AcceptsVehicleType(vehicleType VehicleType) bool {
+	return IsLegacyVehicleType(vehicleType) || p.findVehicleRate(vehicleType) != nil
+}
+
+func (p *ParkingArea) findVehicleRate(vehicleType VehicleType) *AreaVehicleRate {
+	for i := range p.VehicleRates {
+		if p.VehicleRates[i].VehicleType == vehicleType {
+			return &p.VehicleRates[i]
+		}
+	}
+	return nil
+}
+
+// HasCapacityLimit reports whether check-ins of the vehicle type are limited in this area
+func (p *ParkingArea) HasCapacityLimit(vehicleType VehicleType) bool {
+	capacity := p.GetCapacityByVehicleType(vehicleType)
+	return capacity != nil && *capacity > 0
 }
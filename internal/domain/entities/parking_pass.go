@@ -0,0 +1,115 @@
+package entities
+
+import "time"
+
+type PassStatus string
+
+const (
+	PassStatusActive    PassStatus = "active"
+	PassStatusSuspended PassStatus = "suspended"
+)
+
+type PassPurchaseKind string
+
+const (
+	PassPurchaseKindPurchase PassPurchaseKind = "purchase"
+	PassPurchaseKindRenewal  PassPurchaseKind = "renewal"
+)
+
+// PassProduct is a monthly pass (langganan) sold for one area or for every area in a region.
+// Exactly one of AreaID and Regional is set.
+type PassProduct struct {
+	ID           uint        `json:"id" gorm:"primaryKey"`
+	Name         string      `json:"name" gorm:"not null"`
+	AreaID       *uint       `json:"area_id,omitempty" gorm:"index"`
+	Regional     *string     `json:"regional,omitempty" gorm:"type:varchar(50);index"`
+	VehicleType  VehicleType `json:"vehicle_type" gorm:"type:varchar(20);not null"`
+	DurationDays int         `json:"duration_days" gorm:"not null;default:30"`
+	Price        float64     `json:"price" gorm:"not null;default:0"`
+	IsActive     bool        `json:"is_active" gorm:"not null;default:true"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+
+	// Relations
+	Area *ParkingArea `json:"area,omitempty" gorm:"foreignKey:AreaID"`
+}
+
+// ParkingPass is a pass issued to one license plate. Sessions checked in with the plate while the
+// pass is valid cost nothing and carry the pass ID.
+type ParkingPass struct {
+	ID            uint        `json:"id" gorm:"primaryKey"`
+	ProductID     uint        `json:"product_id" gorm:"not null;index"`
+	PlatNomor     string      `json:"plat_nomor" gorm:"type:varchar(20);not null;index"` // uppercase without spaces
+	VehicleType   VehicleType `json:"vehicle_type" gorm:"type:varchar(20);not null"`
+	HolderName    *string     `json:"holder_name,omitempty"`
+	HolderPhone   *string     `json:"holder_phone,omitempty" gorm:"type:varchar(20)"`
+	ValidFrom     time.Time   `json:"valid_from" gorm:"not null"`
+	ValidUntil    time.Time   `json:"valid_until" gorm:"not null;index"` // exclusive
+	Status        PassStatus  `json:"status" gorm:"type:varchar(10);not null;default:'active';index"`
+	IssuedBy      uint        `json:"issued_by" gorm:"not null"` // Admin user ID
+	SuspendedAt   *time.Time  `json:"suspended_at,omitempty"`
+	SuspendReason *string     `json:"suspend_reason,omitempty" gorm:"type:text"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+
+	// Relations
+	Product   PassProduct    `json:"product" gorm:"foreignKey:ProductID"`
+	Purchases []PassPurchase `json:"purchases,omitempty" gorm:"foreignKey:PassID"`
+}
+
+// PassPurchase records money taken for a pass, either the first purchase or a renewal.
+// Pass sales are kept apart from payments, which only cover per-visit parking.
+type PassPurchase struct {
+	ID            uint             `json:"id" gorm:"primaryKey"`
+	PassID        uint             `json:"pass_id" gorm:"not null;index"`
+	ProductID     uint             `json:"product_id" gorm:"not null"`
+	Kind          PassPurchaseKind `json:"kind" gorm:"type:varchar(10);not null"`
+	Amount        float64          `json:"amount" gorm:"not null"`
+	PaymentMethod PaymentMethod    `json:"payment_method" gorm:"type:varchar(20);not null"`
+	PeriodStart   time.Time        `json:"period_start" gorm:"not null"`
+	PeriodEnd     time.Time        `json:"period_end" gorm:"not null"`
+	RecordedBy    uint             `json:"recorded_by" gorm:"not null"` // Admin user ID
+	CreatedAt     time.Time        `json:"created_at" gorm:"index"`
+}
+
+type CreatePassProductRequest struct {
+	Name         string      `json:"name" validate:"required,min=2,max=100"`
+	AreaID       *uint       `json:"area_id,omitempty" validate:"required_without=Regional"`
+	Regional     *string     `json:"regional,omitempty" validate:"required_without=AreaID,omitempty,min=1,max=50"`
+	VehicleType  VehicleType `json:"vehicle_type" validate:"required,min=2,max=20"`
+	DurationDays int         `json:"duration_days" validate:"required,min=1,max=366"`
+	Price        float64     `json:"price" validate:"min=0"`
+}
+
+type UpdatePassProductRequest struct {
+	Name         *string  `json:"name,omitempty" validate:"omitempty,min=2,max=100"`
+	DurationDays *int     `json:"duration_days,omitempty" validate:"omitempty,min=1,max=366"`
+	Price        *float64 `json:"price,omitempty" validate:"omitempty,min=0"`
+	IsActive     *bool    `json:"is_active,omitempty"`
+}
+
+type IssuePassRequest struct {
+	ProductID     uint          `json:"product_id" validate:"required"`
+	PlatNomor     string        `json:"plat_nomor" validate:"required,min=1,max=20"`
+	HolderName    *string       `json:"holder_name,omitempty" validate:"omitempty,max=100"`
+	HolderPhone   *string       `json:"holder_phone,omitempty" validate:"omitempty,max=20"`
+	StartDate     *string       `json:"start_date,omitempty" validate:"omitempty,datetime=2006-01-02"` // default: today
+	PaymentMethod PaymentMethod `json:"payment_method" validate:"required,oneof=cash qris bank_transfer"`
+}
+
+type RenewPassRequest struct {
+	PaymentMethod PaymentMethod `json:"payment_method" validate:"required,oneof=cash qris bank_transfer"`
+}
+
+type SuspendPassRequest struct {
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// PassSalesSummary is the pass revenue shown next to per-visit revenue in reports
+type PassSalesSummary struct {
+	Purchases      int64   `json:"purchases"`
+	Renewals       int64   `json:"renewals"`
+	PurchaseAmount float64 `json:"purchase_amount"`
+	RenewalAmount  float64 `json:"renewal_amount"`
+	TotalAmount    float64 `json:"total_amount"`
+}
@@ -25,47 +25,70 @@ const (
 type ParkingSession struct {
 	ID             uint           `json:"id" gorm:"primaryKey"`
 	JukirID        *uint          `json:"jukir_id,omitempty"`
+	UserID         *uint          `json:"user_id,omitempty" gorm:"index"` // akun pelanggan; nil untuk sesi anonim
 	AreaID         uint           `json:"area_id" gorm:"not null"`
-	VehicleType    VehicleType    `json:"vehicle_type" gorm:"type:varchar(10);not null" validate:"required,oneof=mobil motor"`
+	VehicleType    VehicleType    `json:"vehicle_type" gorm:"type:varchar(20);not null" validate:"required,min=2,max=20"`
 	PlatNomor      *string        `json:"plat_nomor,omitempty" gorm:"null" validate:"omitempty,min=1,max=20"`
 	IsManualRecord bool           `json:"is_manual_record" gorm:"not null;default:false"`
 	CheckinTime    time.Time      `json:"checkin_time" gorm:"not null"`
 	CheckoutTime   *time.Time     `json:"checkout_time,omitempty"`
 	Duration       *int           `json:"duration,omitempty"` // in minutes
 	TotalCost      *float64       `json:"total_cost,omitempty"`
-	PaymentStatus  PaymentStatus  `json:"payment_status" gorm:"type:varchar(20);not null;default:'pending'" validate:"required,oneof=pending paid failed"`
+	PaymentStatus  PaymentStatus  `json:"payment_status" gorm:"type:varchar(20);not null;default:'pending'" validate:"required,oneof=pending paid failed refunded"`
 	SessionStatus  SessionStatus  `json:"session_status" gorm:"type:varchar(20);not null;default:'active'" validate:"required,oneof=active pending_payment completed cancelled"`
+	Version        uint           `json:"version" gorm:"not null;default:1"`         // bumped on every update, see ParkingSessionRepository.Update
+	AutoClosed     bool           `json:"auto_closed" gorm:"not null;default:false"` // checked out by the overstay scheduler, not by a person
+	LostTicket     bool           `json:"lost_ticket" gorm:"not null;default:false"` // checked out without ticket; TotalCost includes the penalty
+	PassID         *uint          `json:"pass_id,omitempty" gorm:"index"`            // checked in with a valid monthly pass; the session costs nothing
 	CreatedAt      time.Time      `json:"created_at"`
 	UpdatedAt      time.Time      `json:"updated_at"`
 	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Relations
-	Jukir   *Jukir      `json:"jukir,omitempty" gorm:"foreignKey:JukirID"`
-	Area    ParkingArea `json:"area" gorm:"foreignKey:AreaID"`
-	Payment *Payment    `json:"payment,omitempty" gorm:"foreignKey:SessionID"`
+	Jukir       *Jukir              `json:"jukir,omitempty" gorm:"foreignKey:JukirID"`
+	Area        ParkingArea         `json:"area" gorm:"foreignKey:AreaID"`
+	Payment     *Payment            `json:"payment,omitempty" gorm:"foreignKey:SessionID"`
+	Attachments []SessionAttachment `json:"attachments,omitempty" gorm:"foreignKey:SessionID"`
 }
 
 type CheckinRequest struct {
-	QRToken     string      `json:"qr_token" validate:"required"`
-	Latitude    *float64    `json:"latitude,omitempty" validate:"omitempty,latitude"`
-	Longitude   *float64    `json:"longitude,omitempty" validate:"omitempty,longitude"`
-	VehicleType VehicleType `json:"vehicle_type" validate:"required,oneof=mobil motor"`
-	PlatNomor   *string     `json:"plat_nomor,omitempty" validate:"omitempty,min=1,max=20"`
+	QRToken       string        `json:"qr_token" validate:"required"`
+	Latitude      *float64      `json:"latitude,omitempty" validate:"omitempty,latitude"`
+	Longitude     *float64      `json:"longitude,omitempty" validate:"omitempty,longitude"`
+	VehicleType   VehicleType   `json:"vehicle_type" validate:"required_without=VehicleID,omitempty,min=2,max=20"`
+	PlatNomor     *string       `json:"plat_nomor,omitempty" validate:"omitempty,min=1,max=20"`
+	ReservationID *uint         `json:"reservation_id,omitempty" validate:"omitempty"`                 // optional; otherwise matched by plate
+	VehicleID     *uint         `json:"vehicle_id,omitempty" validate:"omitempty"`                     // saved vehicle of the logged-in customer; fills plate and type
+	PaymentMethod PaymentMethod `json:"payment_method,omitempty" validate:"omitempty,oneof=cash qris"` // cash (default) or qris; checkout follows it
+
+	UserID *uint `json:"-"` // logged-in customer from the optional bearer token
 }
 
 type CheckoutRequest struct {
+	Ticket    *string  `json:"ticket,omitempty" validate:"omitempty"`
 	SessionID *uint    `json:"session_id,omitempty" validate:"omitempty"`
 	QRToken   string   `json:"qr_token" validate:"required"`
 	PlatNomor *string  `json:"plat_nomor,omitempty" validate:"omitempty,min=1,max=20"`
 	Latitude  *float64 `json:"latitude,omitempty" validate:"omitempty,latitude"`
 	Longitude *float64 `json:"longitude,omitempty" validate:"omitempty,longitude"`
+
+	UserID *uint `json:"-"` // logged-in customer from the optional bearer token
 }
 
 type CheckinResponse struct {
-	SessionID   uint      `json:"session_id"`
-	CheckinTime time.Time `json:"checkin_time"`
-	Area        string    `json:"area_name"`
-	HourlyRate  float64   `json:"hourly_rate"`
+	SessionID       uint      `json:"session_id"`
+	CheckinTime     time.Time `json:"checkin_time"`
+	Area            string    `json:"area_name"`
+	HourlyRate      float64   `json:"hourly_rate"`
+	FirstHourRate   float64   `json:"first_hour_rate"`
+	Warning         string    `json:"warning,omitempty"` // set when checked in over capacity
+	Ticket          string    `json:"ticket"`            // signed token required for checkout and session lookups
+	TicketExpiresAt time.Time `json:"ticket_expires_at"`
+	PassID          *uint     `json:"pass_id,omitempty"`        // set when the plate has a valid monthly pass
+	ReservationID   *uint     `json:"reservation_id,omitempty"` // set when the check-in used a reservation
+	ReceiptURL      string    `json:"receipt_url,omitempty"`    // public e-karcis link
+
+	Payment *PaymentChargeResponse `json:"payment,omitempty"` // QRIS charge to pay, for payment_method qris
 }
 
 type CheckoutResponse struct {
@@ -74,16 +97,22 @@ type CheckoutResponse struct {
 	Duration      int       `json:"duration"` // in minutes
 	TotalCost     float64   `json:"total_cost"`
 	PaymentStatus string    `json:"payment_status"`
+	ReceiptURL    string    `json:"receipt_url,omitempty"` // public e-karcis link
+
+	Payment *PaymentChargeResponse `json:"payment,omitempty"` // QRIS charge for the rest of the cost
 }
 
 type ActiveSessionResponse struct {
-	SessionID   uint      `json:"session_id"`
-	CheckinTime time.Time `json:"checkin_time"`
-	Area        string    `json:"area_name"`
-	PlatNomor   *string   `json:"plat_nomor,omitempty"` // Optional - bisa null jika tidak diisi saat checkin
-	HourlyRate  float64   `json:"hourly_rate"`
-	Duration    int       `json:"duration"` // in minutes
-	CurrentCost float64   `json:"current_cost"`
+	SessionID       uint       `json:"session_id"`
+	CheckinTime     time.Time  `json:"checkin_time"`
+	Area            string     `json:"area_name"`
+	PlatNomor       *string    `json:"plat_nomor,omitempty"` // Optional - bisa null jika tidak diisi saat checkin
+	HourlyRate      float64    `json:"hourly_rate"`
+	FirstHourRate   float64    `json:"first_hour_rate"`
+	Duration        int        `json:"duration"` // in minutes
+	CurrentCost     float64    `json:"current_cost"`
+	Ticket          string     `json:"ticket,omitempty"`
+	TicketExpiresAt *time.Time `json:"ticket_expires_at,omitempty"`
 }
 
 type SessionHistoryResponse struct {
@@ -91,38 +120,76 @@ type SessionHistoryResponse struct {
 	Count    int64            `json:"count"`
 }
 
+// CustomerSessionFilter narrows a customer's session history; nil fields are not filtered on
+type CustomerSessionFilter struct {
+	SessionStatus *SessionStatus
+	AreaID        *uint
+	VehicleType   *VehicleType
+	PlatNomor     *string
+	VehicleID     *uint // saved vehicle; replaces PlatNomor with its plate
+	StartDate     *time.Time
+	EndDate       *time.Time
+}
+
+// ClaimSessionsRequest links anonymous sessions to the logged-in customer. The tickets the app
+// stored at check-in are the proof that the sessions were made on this device.
+type ClaimSessionsRequest struct {
+	Tickets []string `json:"tickets" validate:"required,min=1,max=100,dive,required"`
+}
+
+type ClaimSessionsResponse struct {
+	Claimed []uint               `json:"claimed"` // session IDs now linked to the account
+	Skipped []ClaimSkippedTicket `json:"skipped"`
+}
+
+type ClaimSkippedTicket struct {
+	Index  int    `json:"index"` // position in the request's tickets
+	Reason string `json:"reason"`
+}
+
 // Manual Record DTOs
 type ManualCheckinRequest struct {
-	PlatNomor   string      `json:"plat_nomor" validate:"required,min=1,max=20"`
-	VehicleType VehicleType `json:"vehicle_type" validate:"required,oneof=mobil motor"`
-	WaktuMasuk  time.Time   `json:"waktu_masuk" validate:"required"`
-	Latitude    *float64    `json:"latitude" validate:"required,latitude"`
-	Longitude   *float64    `json:"longitude" validate:"required,longitude"`
+	PlatNomor   string      `json:"plat_nomor" form:"plat_nomor" validate:"required,min=1,max=20"`
+	VehicleType VehicleType `json:"vehicle_type" form:"vehicle_type" validate:"required,min=2,max=20"`
+	WaktuMasuk  time.Time   `json:"waktu_masuk" form:"waktu_masuk" validate:"required"`
+	Latitude    *float64    `json:"latitude" form:"latitude" validate:"required,latitude"`
+	Longitude   *float64    `json:"longitude" form:"longitude" validate:"required,longitude"`
+
+	Photo *UploadedPhoto `json:"-" form:"-"` // optional vehicle photo sent as multipart "photo"
 }
 
 type ManualCheckoutRequest struct {
-	SessionID   uint      `json:"session_id" validate:"required"`
-	WaktuKeluar time.Time `json:"waktu_keluar" validate:"required"`
-	Latitude    *float64  `json:"latitude" validate:"required,latitude"`
-	Longitude   *float64  `json:"longitude" validate:"required,longitude"`
+	SessionID   uint      `json:"session_id" form:"session_id" validate:"required"`
+	WaktuKeluar time.Time `json:"waktu_keluar" form:"waktu_keluar" validate:"required"`
+	Latitude    *float64  `json:"latitude" form:"latitude" validate:"required,latitude"`
+	Longitude   *float64  `json:"longitude" form:"longitude" validate:"required,longitude"`
+
+	Photo *UploadedPhoto `json:"-" form:"-"` // optional vehicle photo sent as multipart "photo"
 }
 
 type ManualCheckinResponse struct {
-	SessionID   uint      `json:"session_id"`
-	PlatNomor   string    `json:"plat_nomor"`
-	VehicleType string    `json:"vehicle_type"`
-	WaktuMasuk  time.Time `json:"waktu_masuk"`
-	Area        string    `json:"area_name"`
-	ParkingCost float64   `json:"parking_cost"`
+	SessionID     uint               `json:"session_id"`
+	PlatNomor     string             `json:"plat_nomor"`
+	VehicleType   string             `json:"vehicle_type"`
+	WaktuMasuk    time.Time          `json:"waktu_masuk"`
+	Area          string             `json:"area_name"`
+	ParkingCost   float64            `json:"parking_cost"`
+	Warning       string             `json:"warning,omitempty"`        // set when checked in over capacity
+	PassID        *uint              `json:"pass_id,omitempty"`        // set when the plate has a valid monthly pass
+	ReservationID *uint              `json:"reservation_id,omitempty"` // set when the check-in used a reservation
+	Photo         *SessionAttachment `json:"photo,omitempty"`
+	ReceiptURL    string             `json:"receipt_url,omitempty"` // public e-karcis link
 }
 
 type ManualCheckoutResponse struct {
-	SessionID     uint      `json:"session_id"`
-	PlatNomor     string    `json:"plat_nomor"`
-	VehicleType   string    `json:"vehicle_type"`
-	WaktuMasuk    time.Time `json:"waktu_masuk"`
-	WaktuKeluar   time.Time `json:"waktu_keluar"`
-	Duration      int       `json:"duration"` // in minutes
-	TotalCost     float64   `json:"total_cost"`
-	PaymentStatus string    `json:"payment_status"`
+	SessionID     uint               `json:"session_id"`
+	PlatNomor     string             `json:"plat_nomor"`
+	VehicleType   string             `json:"vehicle_type"`
+	WaktuMasuk    time.Time          `json:"waktu_masuk"`
+	WaktuKeluar   time.Time          `json:"waktu_keluar"`
+	Duration      int                `json:"duration"` // in minutes
+	TotalCost     float64            `json:"total_cost"`
+	PaymentStatus string             `json:"payment_status"`
+	Photo         *SessionAttachment `json:"photo,omitempty"`
+	ReceiptURL    string             `json:"receipt_url,omitempty"` // public e-karcis link
 }
@@ -23,23 +23,53 @@ const (
 	PaymentMethodBankTransfer PaymentMethod = "bank_transfer"
 )
 
+// PaymentKind separates the parking fee from extra lines charged on the same session. The
+// parking fee itself can be split over a parking line paid at check-in and balance lines.
+type PaymentKind string
+
+const (
+	PaymentKindParking           PaymentKind = "parking"
+	PaymentKindLostTicketPenalty PaymentKind = "lost_ticket_penalty"
+	PaymentKindParkingBalance    PaymentKind = "parking_balance" // rest of the parking fee, collected at checkout
+)
+
 type Payment struct {
 	ID            uint           `json:"id" gorm:"primaryKey"`
 	SessionID     uint           `json:"session_id" gorm:"not null"`
+	Kind          PaymentKind    `json:"kind" gorm:"type:varchar(30);not null;default:'parking'"`
 	Amount        float64        `json:"amount" gorm:"not null" validate:"required,min=0"`
 	PaymentMethod PaymentMethod  `json:"payment_method" gorm:"type:varchar(20);not null" validate:"required,oneof=cash qris bank_transfer"`
 	ConfirmedBy   *uint          `json:"confirmed_by,omitempty"` // Jukir ID
 	ConfirmedAt   *time.Time     `json:"confirmed_at,omitempty"`
 	Status        PaymentStatus  `json:"status" gorm:"type:varchar(20);not null;default:'pending'" validate:"required,oneof=pending paid failed refunded"`
+	Version       uint           `json:"version" gorm:"not null;default:1"` // bumped on every update, see PaymentRepository.Update
 	CreatedAt     time.Time      `json:"created_at"`
 	UpdatedAt     time.Time      `json:"updated_at"`
 	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
 
+	// Tagihan online (QRIS) yang sedang berjalan; kosong untuk pembayaran tunai
+	GatewayProvider *string    `json:"gateway_provider,omitempty" gorm:"type:varchar(30)"`
+	GatewayChargeID *string    `json:"gateway_charge_id,omitempty" gorm:"type:varchar(100);uniqueIndex"`
+	ChargeAmount    *float64   `json:"charge_amount,omitempty"` // part of Amount the charge collects
+	QRString        *string    `json:"qr_string,omitempty" gorm:"type:text"`
+	ChargeExpiresAt *time.Time `json:"charge_expires_at,omitempty"`
+
 	// Relations
 	Session ParkingSession `json:"session" gorm:"foreignKey:SessionID"`
 	Jukir   *Jukir         `json:"jukir,omitempty" gorm:"foreignKey:ConfirmedBy"`
 }
 
+// PaymentChargeResponse is an online charge the customer still has to pay
+type PaymentChargeResponse struct {
+	PaymentID     uint          `json:"payment_id"`
+	PaymentMethod PaymentMethod `json:"payment_method"`
+	Status        PaymentStatus `json:"status"`
+	Amount        float64       `json:"amount"` // amount of this charge
+	QRString      string        `json:"qr_string,omitempty"`
+	ExpiresAt     *time.Time    `json:"expires_at,omitempty"`
+	Provider      string        `json:"provider,omitempty"`
+}
+
 type PendingPaymentResponse struct {
 	SessionID     uint      `json:"session_id"`
 	PlatNomor     string    `json:"plat_nomor"`
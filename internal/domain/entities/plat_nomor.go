@@ -0,0 +1,82 @@
+package entities
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidPlatNomor is returned for plates that cannot be a TNKB (Indonesian registration) plate
+var ErrInvalidPlatNomor = errors.New("invalid plat nomor")
+
+// platNomorPattern is the TNKB layout once separators are removed: a one or two letter region
+// code, a number of one to four digits not starting with 0, and an optional suffix of up to
+// three letters
+var platNomorPattern = regexp.MustCompile(`^([A-Z]{1,2})([1-9][0-9]{0,3})([A-Z]{0,3})$`)
+
+// platRegionCodes are the region codes issued by Korlantas, plus RI for state vehicles
+var platRegionCodes = map[string]bool{
+	// Sumatera
+	"BL": true, "BB": true, "BK": true, "BA": true, "BM": true, "BP": true,
+	"BH": true, "BD": true, "BG": true, "BN": true, "BE": true,
+	// Jawa
+	"A": true, "B": true, "D": true, "E": true, "F": true, "T": true, "Z": true,
+	"G": true, "H": true, "K": true, "R": true, "AA": true, "AD": true,
+	"AB": true, "L": true, "M": true, "N": true, "P": true, "S": true,
+	"W": true, "AE": true, "AG": true,
+	// Bali dan Nusa Tenggara
+	"DK": true, "DR": true, "EA": true, "DH": true, "EB": true, "ED": true,
+	// Kalimantan
+	"KB": true, "DA": true, "KH": true, "KT": true, "KU": true,
+	// Sulawesi
+	"DB": true, "DL": true, "DM": true, "DN": true, "DD": true, "DP": true,
+	"DW": true, "DC": true, "DT": true,
+	// Maluku dan Papua
+	"DE": true, "DG": true, "PA": true, "PB": true, "DS": true, "PG": true,
+	"PS": true, "PT": true, "PY": true,
+	// Kendaraan dinas negara
+	"RI": true,
+}
+
+// NormalizePlatNomor parses a license plate typed in any common form ("bg 1234 ab", "BG-1234-AB",
+// "BG1234AB") and returns it in TNKB form with single spaces: "BG 1234 AB". Plates that do not
+// fit the layout or use an unknown region code are rejected with ErrInvalidPlatNomor.
+func NormalizePlatNomor(raw string) (string, error) {
+	compact := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '-', '.', '_':
+			return -1
+		}
+		return r
+	}, strings.ToUpper(raw))
+	if compact == "" {
+		return "", fmt.Errorf("%w: plate is empty", ErrInvalidPlatNomor)
+	}
+
+	parts := platNomorPattern.FindStringSubmatch(compact)
+	if parts == nil {
+		return "", fmt.Errorf("%w: %q is not in the form region code, number, suffix (e.g. BG 1234 AB)", ErrInvalidPlatNomor, raw)
+	}
+	region, number, suffix := parts[1], parts[2], parts[3]
+	if !platRegionCodes[region] {
+		return "", fmt.Errorf("%w: unknown region code %q", ErrInvalidPlatNomor, region)
+	}
+
+	if suffix == "" {
+		return region + " " + number, nil
+	}
+	return region + " " + number + " " + suffix, nil
+}
+
+// NormalizeOptionalPlatNomor normalizes a plate that may be left out; nil and blank stay nil
+func NormalizeOptionalPlatNomor(raw *string) (*string, error) {
+	if raw == nil || strings.TrimSpace(*raw) == "" {
+		return nil, nil
+	}
+	normalized, err := NormalizePlatNomor(*raw)
+	if err != nil {
+		return nil, err
+	}
+	return &normalized, nil
+}
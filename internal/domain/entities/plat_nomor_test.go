@@ -0,0 +1,70 @@
+package entities
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizePlatNomor(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"BG 1234 AB", "BG 1234 AB", false},
+		{"bg1234ab", "BG 1234 AB", false},
+		{"BG-1234-AB", "BG 1234 AB", false},
+		{"  bg.1234_ab ", "BG 1234 AB", false},
+		{"bg\t1234 ab", "BG 1234 AB", false},
+		{"B 1 A", "B 1 A", false},
+		{"B1", "B 1", false},
+		{"D 9999 XYZ", "D 9999 XYZ", false},
+		{"RI 1", "RI 1", false},
+		{"", "", true},
+		{"   ", "", true},
+		{"BG 0123 AB", "", true},   // number may not start with 0
+		{"BG 12345 AB", "", true},  // at most four digits
+		{"BG 1234 ABCD", "", true}, // at most three suffix letters
+		{"BGX 1234", "", true},     // at most two region letters
+		{"1234 AB", "", true},      // region code is required
+		{"BG AB", "", true},        // number is required
+		{"XX 1234 AB", "", true},   // unknown region code
+		{"BG 12A4 AB", "", true},   // digits and letters mixed
+		{"BG 1234 AB!", "", true},  // stray punctuation
+		{"ＢＧ 1234 AB", "", true},   // full-width letters
+		{"C 1234 AB", "", true},    // C is not issued
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := NormalizePlatNomor(tt.raw)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPlatNomor) {
+					t.Errorf("NormalizePlatNomor(%q) = %q, %v; want ErrInvalidPlatNomor", tt.raw, got, err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("NormalizePlatNomor(%q) = %q, %v; want %q", tt.raw, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeOptionalPlatNomor(t *testing.T) {
+	blank := "  "
+	valid := "bg1234ab"
+	invalid := "XX 1"
+
+	if got, err := NormalizeOptionalPlatNomor(nil); got != nil || err != nil {
+		t.Errorf("NormalizeOptionalPlatNomor(nil) = %v, %v; want nil, nil", got, err)
+	}
+	if got, err := NormalizeOptionalPlatNomor(&blank); got != nil || err != nil {
+		t.Errorf("NormalizeOptionalPlatNomor(blank) = %v, %v; want nil, nil", got, err)
+	}
+	if got, err := NormalizeOptionalPlatNomor(&valid); err != nil || got == nil || *got != "BG 1234 AB" {
+		t.Errorf("NormalizeOptionalPlatNomor(%q) = %v, %v; want BG 1234 AB", valid, got, err)
+	}
+	if _, err := NormalizeOptionalPlatNomor(&invalid); !errors.Is(err, ErrInvalidPlatNomor) {
+		t.Errorf("NormalizeOptionalPlatNomor(%q) error = %v, want ErrInvalidPlatNomor", invalid, err)
+	}
+}
@@ -0,0 +1,43 @@
+package entities
+
+import "time"
+
+type ReservationStatus string
+
+const (
+	ReservationStatusReserved  ReservationStatus = "reserved"
+	ReservationStatusConsumed  ReservationStatus = "consumed"
+	ReservationStatusExpired   ReservationStatus = "expired"
+	ReservationStatusCancelled ReservationStatus = "cancelled"
+)
+
+// Reservation holds one vehicle-type slot in an area for a customer's plate. While reserved it
+// counts against the area's capacity like an active session; the check-in that consumes it
+// takes over the slot.
+type Reservation struct {
+	ID          uint              `json:"id" gorm:"primaryKey"`
+	UserID      uint              `json:"user_id" gorm:"not null;index"` // Customer
+	AreaID      uint              `json:"area_id" gorm:"not null;index"`
+	VehicleType VehicleType       `json:"vehicle_type" gorm:"type:varchar(20);not null"`
+	PlatNomor   string            `json:"plat_nomor" gorm:"type:varchar(20);not null;index"` // uppercase without spaces
+	StartTime   time.Time         `json:"start_time" gorm:"not null"`
+	EndTime     time.Time         `json:"end_time" gorm:"not null"`
+	ExpiresAt   time.Time         `json:"expires_at" gorm:"not null;index"` // StartTime + grace period
+	Status      ReservationStatus `json:"status" gorm:"type:varchar(10);not null;default:'reserved';index"`
+	SessionID   *uint             `json:"session_id,omitempty"`
+	ClosedAt    *time.Time        `json:"closed_at,omitempty"` // when it was consumed, expired or cancelled
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+
+	// Relations
+	Area    ParkingArea     `json:"area" gorm:"foreignKey:AreaID"`
+	Session *ParkingSession `json:"session,omitempty" gorm:"foreignKey:SessionID"`
+}
+
+type CreateReservationRequest struct {
+	AreaID      uint        `json:"area_id" validate:"required"`
+	VehicleType VehicleType `json:"vehicle_type" validate:"required,min=2,max=20"`
+	PlatNomor   string      `json:"plat_nomor" validate:"required,min=1,max=20"`
+	StartTime   time.Time   `json:"start_time" validate:"required"`
+	EndTime     time.Time   `json:"end_time" validate:"required,gtfield=StartTime"`
+}
@@ -0,0 +1,32 @@
+package entities
+
+import "time"
+
+type AttachmentKind string
+
+const (
+	AttachmentKindCheckin  AttachmentKind = "checkin"
+	AttachmentKindCheckout AttachmentKind = "checkout"
+)
+
+// SessionAttachment is a vehicle photo taken by the jukir at a manual check-in or checkout,
+// kept as evidence when a manual record is disputed. The file itself lives in MinIO.
+type SessionAttachment struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	SessionID   uint           `json:"session_id" gorm:"not null;index"`
+	Kind        AttachmentKind `json:"kind" gorm:"type:varchar(10);not null"`
+	ObjectName  string         `json:"object_name" gorm:"type:varchar(255);not null"` // MinIO object key
+	ContentType string         `json:"content_type" gorm:"type:varchar(100);not null"`
+	Size        int64          `json:"size" gorm:"not null"`
+	UploadedBy  uint           `json:"uploaded_by" gorm:"not null"` // Jukir ID
+	CreatedAt   time.Time      `json:"created_at"`
+
+	URL string `json:"url,omitempty" gorm:"-"` // admin download path, set in admin responses
+}
+
+// UploadedPhoto is a photo already stored in MinIO, waiting to be linked to a session
+type UploadedPhoto struct {
+	ObjectName  string
+	ContentType string
+	Size        int64
+}
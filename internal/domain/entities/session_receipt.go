@@ -0,0 +1,41 @@
+package entities
+
+import "time"
+
+// SessionReceipt is the e-karcis of a session. It is issued at check-in and re-issued at checkout
+// under the same code, so the short link printed or shared with the customer keeps working.
+type SessionReceipt struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	SessionID uint      `json:"session_id" gorm:"not null;uniqueIndex"`
+	Code      string    `json:"code" gorm:"type:varchar(16);not null;uniqueIndex"`
+	PDFObject string    `json:"-" gorm:"column:pdf_object;type:varchar(255);not null"` // MinIO object keys
+	PNGObject string    `json:"-" gorm:"column:png_object;type:varchar(255);not null"`
+	Amount    float64   `json:"amount" gorm:"type:decimal(10,2);not null"` // amount printed on the latest version
+	IssuedAt  time.Time `json:"issued_at" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReceiptVerification is what the public verification endpoint reports for a receipt code.
+// Amounts come from the database, not from the document, so an edited receipt shows up as a
+// mismatch with what the inspector holds.
+type ReceiptVerification struct {
+	Code          string        `json:"code"`
+	Genuine       bool          `json:"genuine"`
+	SessionID     uint          `json:"session_id"`
+	AreaName      string        `json:"area_name"`
+	JukirCode     string        `json:"jukir_code,omitempty"`
+	VehicleType   VehicleType   `json:"vehicle_type"`
+	PlatNomor     *string       `json:"plat_nomor,omitempty"`
+	CheckinTime   time.Time     `json:"checkin_time"`
+	CheckoutTime  *time.Time    `json:"checkout_time,omitempty"`
+	SessionStatus SessionStatus `json:"session_status"`
+	PassID        *uint         `json:"pass_id,omitempty"`
+	LostTicket    bool          `json:"lost_ticket"`
+	AmountCharged float64       `json:"amount_charged"`
+	// OfficialAmount is the session's cost under the area's tariff, including the lost ticket
+	// penalty when one was charged
+	OfficialAmount  float64   `json:"official_amount"`
+	OfficialRate    bool      `json:"official_rate"` // AmountCharged does not exceed OfficialAmount
+	ReceiptIssuedAt time.Time `json:"receipt_issued_at"`
+}
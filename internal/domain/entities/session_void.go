@@ -0,0 +1,49 @@
+package entities
+
+import "time"
+
+type VoidReason string
+
+const (
+	VoidReasonWrongPlate        VoidReason = "wrong_plate"
+	VoidReasonWrongVehicleType  VoidReason = "wrong_vehicle_type"
+	VoidReasonAccidentalCheckin VoidReason = "accidental_checkin"
+)
+
+type VoidStatus string
+
+const (
+	VoidStatusPending  VoidStatus = "pending"
+	VoidStatusApproved VoidStatus = "approved"
+	VoidStatusRejected VoidStatus = "rejected"
+)
+
+// SessionVoidRequest is a jukir's request to cancel a session recorded by mistake. It also
+// serves as the audit trail: who asked, why, and which admin approved or rejected it.
+type SessionVoidRequest struct {
+	ID          uint       `json:"id" gorm:"primaryKey"`
+	SessionID   uint       `json:"session_id" gorm:"not null;index"`
+	RequestedBy uint       `json:"requested_by" gorm:"not null;index"` // Jukir ID
+	ReasonCode  VoidReason `json:"reason_code" gorm:"type:varchar(30);not null"`
+	Note        *string    `json:"note,omitempty" gorm:"type:text"`
+	Status      VoidStatus `json:"status" gorm:"type:varchar(10);not null;default:'pending';index"`
+	ReviewedBy  *uint      `json:"reviewed_by,omitempty"` // Admin user ID
+	ReviewedAt  *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNote  *string    `json:"review_note,omitempty" gorm:"type:text"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Relations
+	Session  ParkingSession `json:"session" gorm:"foreignKey:SessionID"`
+	Jukir    Jukir          `json:"jukir" gorm:"foreignKey:RequestedBy"`
+	Reviewer *User          `json:"reviewer,omitempty" gorm:"foreignKey:ReviewedBy"`
+}
+
+type CreateVoidRequest struct {
+	ReasonCode VoidReason `json:"reason_code" validate:"required,oneof=wrong_plate wrong_vehicle_type accidental_checkin"`
+	Note       *string    `json:"note,omitempty" validate:"omitempty,max=500"`
+}
+
+type ReviewVoidRequest struct {
+	Note *string `json:"note,omitempty" validate:"omitempty,max=500"`
+}
@@ -0,0 +1,66 @@
+package entities
+
+import "time"
+
+type SyncAction string
+
+const (
+	SyncActionCheckin  SyncAction = "checkin"
+	SyncActionCheckout SyncAction = "checkout"
+)
+
+type SyncStatus string
+
+const (
+	SyncStatusApplied   SyncStatus = "applied"   // diterapkan sekarang
+	SyncStatusDuplicate SyncStatus = "duplicate" // sudah diterapkan pada sync sebelumnya
+	SyncStatusConflict  SyncStatus = "conflict"  // bertentangan dengan data server, mis. sesi tidak dikenal
+	SyncStatusFailed    SyncStatus = "failed"
+)
+
+// SyncRecord remembers which offline items have been applied, keyed by the client-generated UUID
+type SyncRecord struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	ClientID  string     `json:"client_id" gorm:"type:varchar(36);not null;uniqueIndex"`
+	JukirID   uint       `json:"jukir_id" gorm:"not null;index"`
+	Action    SyncAction `json:"action" gorm:"type:varchar(10);not null"`
+	SessionID *uint      `json:"session_id,omitempty"` // written with the session; nil only on records of interrupted older syncs
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type SyncRequest struct {
+	Items []SyncItem `json:"items" validate:"required,min=1,max=200,dive"`
+}
+
+// SyncItem is one manual check-in or checkout recorded while the jukir was offline.
+// A checkout refers to its session by server session_id, or by checkin_client_id when the
+// check-in was also recorded offline.
+type SyncItem struct {
+	ClientID        string      `json:"client_id" validate:"required,uuid"`
+	Action          SyncAction  `json:"action" validate:"required,oneof=checkin checkout"`
+	PlatNomor       string      `json:"plat_nomor,omitempty" validate:"required_if=Action checkin,omitempty,min=1,max=20"`
+	VehicleType     VehicleType `json:"vehicle_type,omitempty" validate:"required_if=Action checkin,omitempty,min=2,max=20"`
+	WaktuMasuk      *time.Time  `json:"waktu_masuk,omitempty" validate:"required_if=Action checkin"`
+	SessionID       *uint       `json:"session_id,omitempty"`
+	CheckinClientID *string     `json:"checkin_client_id,omitempty" validate:"omitempty,uuid"`
+	WaktuKeluar     *time.Time  `json:"waktu_keluar,omitempty" validate:"required_if=Action checkout"`
+	Latitude        *float64    `json:"latitude" validate:"required,latitude"`
+	Longitude       *float64    `json:"longitude" validate:"required,longitude"`
+}
+
+type SyncItemResult struct {
+	ClientID  string     `json:"client_id"`
+	Action    SyncAction `json:"action"`
+	Status    SyncStatus `json:"status"`
+	SessionID *uint      `json:"session_id,omitempty"`
+	Message   string     `json:"message,omitempty"`
+}
+
+type SyncResponse struct {
+	Results   []SyncItemResult `json:"results"`
+	Applied   int              `json:"applied"`
+	Duplicate int              `json:"duplicate"`
+	Conflict  int              `json:"conflict"`
+	Failed    int              `json:"failed"`
+}
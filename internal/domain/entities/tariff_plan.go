@@ -0,0 +1,80 @@
+package entities
+
+import "time"
+
+const minutesPerDay = 24 * 60
+
+// TariffPlan describes progressive pricing for one vehicle type in a parking area
+type TariffPlan struct {
+	ID                 uint        `json:"id" gorm:"primaryKey"`
+	AreaID             uint        `json:"area_id" gorm:"not null;uniqueIndex:idx_tariff_plans_area_vehicle"`
+	VehicleType        VehicleType `json:"vehicle_type" gorm:"type:varchar(20);not null;uniqueIndex:idx_tariff_plans_area_vehicle" validate:"required,min=2,max=20"`
+	FirstHourRate      float64     `json:"first_hour_rate" gorm:"not null;default:0" validate:"min=0"`
+	NextHourRate       float64     `json:"next_hour_rate" gorm:"not null;default:0" validate:"min=0"`
+	DailyCap           float64     `json:"daily_cap" gorm:"not null;default:0" validate:"min=0"` // 0 = no cap
+	GracePeriodMinutes int         `json:"grace_period_minutes" gorm:"not null;default:0" validate:"min=0"`
+	CreatedAt          time.Time   `json:"created_at"`
+	UpdatedAt          time.Time   `json:"updated_at"`
+
+	// Relations
+	Area ParkingArea `json:"-" gorm:"foreignKey:AreaID"`
+
+	flat bool // set by FlatTariffPlan: one charge for the whole stay
+}
+
+type UpsertTariffPlanRequest struct {
+	VehicleType        VehicleType `json:"vehicle_type" validate:"required,min=2,max=20"`
+	FirstHourRate      float64     `json:"first_hour_rate" validate:"min=0"`
+	NextHourRate       float64     `json:"next_hour_rate" validate:"min=0"`
+	DailyCap           float64     `json:"daily_cap" validate:"min=0"`
+	GracePeriodMinutes int         `json:"grace_period_minutes" validate:"min=0,max=59"`
+}
+
+// FlatTariffPlan builds a plan that charges the area's flat rate once, however long the stay,
+// used for areas that have no tariff plan configured yet
+func FlatTariffPlan(area ParkingArea, vehicleType VehicleType) TariffPlan {
+	return TariffPlan{
+		AreaID:        area.ID,
+		VehicleType:   vehicleType,
+		FirstHourRate: area.GetRateByVehicleType(vehicleType),
+		flat:          true,
+	}
+}
+
+// CalculateCost returns the parking fee for a stay of the given length.
+// The stay is split into 24 hour blocks. In each block the first (started) hour costs
+// FirstHourRate and every further started hour NextHourRate, counted after taking
+// GracePeriodMinutes off the block once; a new block is only charged when it runs longer
+// than the grace period. Each block is capped at DailyCap when it is set. A flat plan
+// charges FirstHourRate once.
+func (p *TariffPlan) CalculateCost(durationMinutes int) float64 {
+	if p.flat {
+		return p.FirstHourRate
+	}
+	if durationMinutes < 0 {
+		durationMinutes = 0
+	}
+
+	fullDays := durationMinutes / minutesPerDay
+	remainder := durationMinutes % minutesPerDay
+
+	total := float64(fullDays) * p.blockCost(minutesPerDay)
+	if fullDays == 0 || remainder > p.GracePeriodMinutes {
+		total += p.blockCost(remainder)
+	}
+	return total
+}
+
+// blockCost prices a stay of at most one day
+func (p *TariffPlan) blockCost(minutes int) float64 {
+	hours := 1
+	if billable := minutes - p.GracePeriodMinutes; billable > 60 {
+		hours = (billable + 59) / 60
+	}
+
+	cost := p.FirstHourRate + float64(hours-1)*p.NextHourRate
+	if p.DailyCap > 0 && cost > p.DailyCap {
+		cost = p.DailyCap
+	}
+	return cost
+}
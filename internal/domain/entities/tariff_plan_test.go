@@ -0,0 +1,66 @@
+package entities
+
+import "testing"
+
+func TestTariffPlanCalculateCost(t *testing.T) {
+	progressive := TariffPlan{FirstHourRate: 5000, NextHourRate: 3000, DailyCap: 20000, GracePeriodMinutes: 10}
+	uncapped := TariffPlan{FirstHourRate: 2000, NextHourRate: 1000}
+	firstHourOnly := TariffPlan{FirstHourRate: 3000}
+	flat := FlatTariffPlan(ParkingArea{HourlyRateMobil: 3000}, VehicleTypeMobil)
+
+	tests := []struct {
+		name     string
+		plan     TariffPlan
+		duration int
+		want     float64
+	}{
+		{"negative duration is the first hour", progressive, -5, 5000},
+		{"zero minutes is the first hour", progressive, 0, 5000},
+		{"one full hour", progressive, 60, 5000},
+		{"inside the grace period", progressive, 70, 5000},
+		{"one minute past the grace period", progressive, 71, 8000},
+		{"two hours with grace", progressive, 130, 8000},
+		{"third hour started", progressive, 131, 11000},
+		{"capped within the first day", progressive, 600, 20000},
+		{"exactly one day", progressive, 1440, 20000},
+		{"next day inside the grace period", progressive, 1450, 20000},
+		{"next day started", progressive, 1451, 25000},
+		{"next day second hour", progressive, 1511, 28000},
+		{"two full days", progressive, 2880, 40000},
+		{"no grace: one minute past the hour", uncapped, 61, 3000},
+		{"no cap: a whole day of hours", uncapped, 1440, 25000},
+		{"no cap: next day started", uncapped, 1441, 27000},
+		{"first hour rate only: a long stay", firstHourOnly, 300, 3000},
+		{"first hour rate only: per started day", firstHourOnly, 1500, 6000},
+		{"flat rate for a short stay", flat, 10, 3000},
+		{"flat rate once for several days", flat, 4000, 3000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.plan.CalculateCost(tt.duration); got != tt.want {
+				t.Errorf("CalculateCost(%d) = %v, want %v", tt.duration, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlatTariffPlan(t *testing.T) {
+	area := ParkingArea{ID: 7, HourlyRateMobil: 5000, HourlyRateMotor: 2000}
+
+	tests := []struct {
+		vehicleType VehicleType
+		want        float64
+	}{
+		{VehicleTypeMobil, 5000},
+		{VehicleTypeMotor, 2000},
+	}
+	for _, tt := range tests {
+		plan := FlatTariffPlan(area, tt.vehicleType)
+		if plan.AreaID != area.ID || plan.VehicleType != tt.vehicleType {
+			t.Errorf("FlatTariffPlan(%s) = area %d type %s", tt.vehicleType, plan.AreaID, plan.VehicleType)
+		}
+		if plan.FirstHourRate != tt.want || plan.NextHourRate != 0 || plan.DailyCap != 0 {
+			t.Errorf("FlatTariffPlan(%s) rates = %v/%v/%v, want %v/0/0", tt.vehicleType, plan.FirstHourRate, plan.NextHourRate, plan.DailyCap, tt.want)
+		}
+	}
+}
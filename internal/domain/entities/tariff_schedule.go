@@ -0,0 +1,92 @@
+package entities
+
+import (
+	"fmt"
+	"time"
+)
+
+type ScheduleType string
+
+const (
+	ScheduleTypeNight   ScheduleType = "night"
+	ScheduleTypeWeekend ScheduleType = "weekend"
+	ScheduleTypeHoliday ScheduleType = "holiday"
+)
+
+// TariffSchedule overrides an area's first-hour rate when a session starts
+// at night, on a weekend, or on a national holiday
+type TariffSchedule struct {
+	ID           uint         `json:"id" gorm:"primaryKey"`
+	AreaID       uint         `json:"area_id" gorm:"not null;uniqueIndex:idx_tariff_schedules_area_type"`
+	ScheduleType ScheduleType `json:"schedule_type" gorm:"type:varchar(10);not null;uniqueIndex:idx_tariff_schedules_area_type"`
+	RateMobil    float64      `json:"rate_mobil" gorm:"not null;default:0"`
+	RateMotor    float64      `json:"rate_motor" gorm:"not null;default:0"`
+	StartTime    *string      `json:"start_time,omitempty" gorm:"type:varchar(5)"` // HH:MM, night only
+	EndTime      *string      `json:"end_time,omitempty" gorm:"type:varchar(5)"`   // HH:MM, night only
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+type TariffScheduleRequest struct {
+	ScheduleType ScheduleType `json:"schedule_type" validate:"required,oneof=night weekend holiday"`
+	RateMobil    float64      `json:"rate_mobil" validate:"min=0"`
+	RateMotor    float64      `json:"rate_motor" validate:"min=0"`
+	StartTime    *string      `json:"start_time,omitempty" validate:"required_if=ScheduleType night,omitempty,datetime=15:04"`
+	EndTime      *string      `json:"end_time,omitempty" validate:"required_if=ScheduleType night,omitempty,datetime=15:04"`
+}
+
+// Holiday is a national holiday (tanggal merah) on which holiday schedules apply
+type Holiday struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Date      time.Time `json:"date" gorm:"type:date;not null;uniqueIndex"`
+	Name      string    `json:"name" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CreateHolidayRequest struct {
+	Date string `json:"date" validate:"required,datetime=02-01-2006"` // DD-MM-YYYY
+	Name string `json:"name" validate:"required,min=2,max=100"`
+}
+
+// GetRateByVehicleType returns the scheduled rate for the vehicle type. Schedules only carry
+// rates for mobil and motor; other types get 0 and keep their own area rate.
+func (s *TariffSchedule) GetRateByVehicleType(vehicleType VehicleType) float64 {
+	switch vehicleType {
+	case VehicleTypeMobil:
+		return s.RateMobil
+	case VehicleTypeMotor:
+		return s.RateMotor
+	}
+	return 0
+}
+
+// CoversTimeOfDay reports whether t falls inside the schedule's HH:MM window.
+// Windows that end before they start wrap past midnight (e.g. 22:00-06:00).
+func (s *TariffSchedule) CoversTimeOfDay(t time.Time) bool {
+	if s.StartTime == nil || s.EndTime == nil {
+		return false
+	}
+	start, err := minuteOfDay(*s.StartTime)
+	if err != nil {
+		return false
+	}
+	end, err := minuteOfDay(*s.EndTime)
+	if err != nil {
+		return false
+	}
+
+	minute := t.Hour()*60 + t.Minute()
+	if start <= end {
+		return minute >= start && minute < end
+	}
+	return minute >= start || minute < end
+}
+
+func minuteOfDay(hhmm string) (int, error) {
+	parsed, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q: %w", hhmm, err)
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
@@ -0,0 +1,60 @@
+package entities
+
+import "time"
+
+// VehicleTypeConfig is an entry in the vehicle type registry (mobil, motor, truk, bus, ...).
+// Rates and capacities for mobil and motor stay in their ParkingArea columns; every other
+// type is priced per area through AreaVehicleRate.
+type VehicleTypeConfig struct {
+	ID        uint        `json:"id" gorm:"primaryKey"`
+	Code      VehicleType `json:"code" gorm:"type:varchar(20);not null;uniqueIndex"`
+	Name      string      `json:"name" gorm:"not null"`
+	IsActive  bool        `json:"is_active" gorm:"not null;default:true"`
+	SortOrder int         `json:"sort_order" gorm:"not null;default:0"`
+	CreatedAt time.Time   `json:"created_at"`
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+func (VehicleTypeConfig) TableName() string {
+	return "vehicle_types"
+}
+
+// DefaultVehicleTypes are seeded into the registry and used when it cannot be read
+var DefaultVehicleTypes = []VehicleTypeConfig{
+	{Code: VehicleTypeMobil, Name: "Mobil Penumpang", IsActive: true, SortOrder: 1},
+	{Code: VehicleTypeMotor, Name: "Motor", IsActive: true, SortOrder: 2},
+}
+
+// IsLegacyVehicleType reports whether the type is stored in the mobil/motor area columns
+func IsLegacyVehicleType(vehicleType VehicleType) bool {
+	return vehicleType == VehicleTypeMobil || vehicleType == VehicleTypeMotor
+}
+
+// AreaVehicleRate holds an area's rate and capacity for a registry vehicle type
+type AreaVehicleRate struct {
+	ID          uint        `json:"id" gorm:"primaryKey"`
+	AreaID      uint        `json:"area_id" gorm:"not null;uniqueIndex:idx_area_vehicle_rates_area_type"`
+	VehicleType VehicleType `json:"vehicle_type" gorm:"type:varchar(20);not null;uniqueIndex:idx_area_vehicle_rates_area_type"`
+	HourlyRate  float64     `json:"hourly_rate" gorm:"not null;default:0"`
+	Capacity    *int        `json:"capacity,omitempty" gorm:"type:int;default:0"`
+	CreatedAt   time.Time   `json:"created_at"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+type CreateVehicleTypeRequest struct {
+	Code      VehicleType `json:"code" validate:"required,min=2,max=20,lowercase"`
+	Name      string      `json:"name" validate:"required,min=2,max=50"`
+	SortOrder int         `json:"sort_order" validate:"min=0"`
+}
+
+type UpdateVehicleTypeRequest struct {
+	Name      *string `json:"name,omitempty" validate:"omitempty,min=2,max=50"`
+	IsActive  *bool   `json:"is_active,omitempty"`
+	SortOrder *int    `json:"sort_order,omitempty" validate:"omitempty,min=0"`
+}
+
+type AreaVehicleRateRequest struct {
+	VehicleType VehicleType `json:"vehicle_type" validate:"required,min=2,max=20"`
+	HourlyRate  float64     `json:"hourly_rate" validate:"min=0"`
+	Capacity    *int        `json:"capacity,omitempty" validate:"omitempty,min=0"`
+}
@@ -0,0 +1,137 @@
+// Package geohash encodes coordinates as geohashes so nearby parking areas can be found with an
+// indexed prefix search instead of a scan
+package geohash
+
+import (
+	"math"
+	"strings"
+)
+
+const base32 = "0123456789bcdefghjkmnpqrstuvwxyz"
+
+// MaxPrecision is the precision stored for parking areas, about 5 m x 5 m
+const MaxPrecision = 9
+
+// cellSizesKM are the width and height of a cell at each precision, measured at the equator
+var cellSizesKM = [...][2]float64{
+	{5009.4, 4992.6}, {1252.3, 624.1}, {156.5, 156.0}, {39.1, 19.5},
+	{4.89, 4.87}, {1.22, 0.61}, {0.153, 0.152}, {0.038, 0.019}, {0.0048, 0.0048},
+}
+
+// Encode returns the geohash of a coordinate with the given number of characters
+func Encode(lat, lng float64, precision int) string {
+	latRange := [2]float64{-90, 90}
+	lngRange := [2]float64{-180, 180}
+
+	var hash strings.Builder
+	bits, ch := 0, 0
+	even := true
+	for hash.Len() < precision {
+		if even {
+			mid := (lngRange[0] + lngRange[1]) / 2
+			if lng >= mid {
+				ch = ch<<1 | 1
+				lngRange[0] = mid
+			} else {
+				ch <<= 1
+				lngRange[1] = mid
+			}
+		} else {
+			mid := (latRange[0] + latRange[1]) / 2
+			if lat >= mid {
+				ch = ch<<1 | 1
+				latRange[0] = mid
+			} else {
+				ch <<= 1
+				latRange[1] = mid
+			}
+		}
+		even = !even
+		if bits++; bits == 5 {
+			hash.WriteByte(base32[ch])
+			bits, ch = 0, 0
+		}
+	}
+	return hash.String()
+}
+
+// PrecisionForRadius returns the longest precision whose cells are at least radiusKM across at
+// the given latitude, so the cell containing a point and its eight neighbours cover every point
+// within radiusKM of it
+func PrecisionForRadius(lat, radiusKM float64) int {
+	shrink := math.Cos(lat * math.Pi / 180)
+	for precision := len(cellSizesKM); precision > 1; precision-- {
+		size := cellSizesKM[precision-1]
+		if math.Min(size[0]*shrink, size[1]) >= radiusKM {
+			return precision
+		}
+	}
+	return 1
+}
+
+// CoveringCells returns the cell containing the point and its eight neighbours at the precision
+// chosen by PrecisionForRadius. Cells past the poles are left out.
+func CoveringCells(lat, lng, radiusKM float64) []string {
+	precision := PrecisionForRadius(lat, radiusKM)
+	center := Encode(lat, lng, precision)
+
+	// Step to the neighbouring cells through their centers; half a cell away from this
+	// cell's center is always inside the next cell
+	minLat, maxLat, minLng, maxLng := bounds(center)
+	latStep := maxLat - minLat
+	lngStep := maxLng - minLng
+	centerLat := (minLat + maxLat) / 2
+	centerLng := (minLng + maxLng) / 2
+
+	seen := map[string]bool{}
+	cells := make([]string, 0, 9)
+	for _, dLat := range []float64{-1, 0, 1} {
+		for _, dLng := range []float64{-1, 0, 1} {
+			cellLat := centerLat + dLat*latStep
+			if cellLat < -90 || cellLat > 90 {
+				continue
+			}
+			cellLng := centerLng + dLng*lngStep
+			if cellLng < -180 {
+				cellLng += 360
+			} else if cellLng > 180 {
+				cellLng -= 360
+			}
+			cell := Encode(cellLat, cellLng, precision)
+			if !seen[cell] {
+				seen[cell] = true
+				cells = append(cells, cell)
+			}
+		}
+	}
+	return cells
+}
+
+// bounds decodes a geohash into the latitude and longitude range of its cell
+func bounds(hash string) (minLat, maxLat, minLng, maxLng float64) {
+	minLat, maxLat, minLng, maxLng = -90, 90, -180, 180
+	even := true
+	for i := 0; i < len(hash); i++ {
+		ch := strings.IndexByte(base32, hash[i])
+		for bit := 4; bit >= 0; bit-- {
+			on := ch>>bit&1 == 1
+			if even {
+				mid := (minLng + maxLng) / 2
+				if on {
+					minLng = mid
+				} else {
+					maxLng = mid
+				}
+			} else {
+				mid := (minLat + maxLat) / 2
+				if on {
+					minLat = mid
+				} else {
+					maxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+	return minLat, maxLat, minLng, maxLng
+}
@@ -0,0 +1,100 @@
+package geohash
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		lat, lng  float64
+		precision int
+		want      string
+	}{
+		{57.64911, 10.40744, 11, "u4pruydqqvj"},
+		{42.6, -5.6, 5, "ezs42"},
+		{-2.990, 104.761, MaxPrecision, "qr6qzzstu"},
+		{0, 0, 1, "s"},
+		{-90, -180, 3, "000"},
+		{90, 180, 3, "zzz"},
+	}
+	for _, tt := range tests {
+		if got := Encode(tt.lat, tt.lng, tt.precision); got != tt.want {
+			t.Errorf("Encode(%v, %v, %d) = %q, want %q", tt.lat, tt.lng, tt.precision, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeIsPrefixOfLongerHash(t *testing.T) {
+	full := Encode(-2.990, 104.761, MaxPrecision)
+	for precision := 1; precision < MaxPrecision; precision++ {
+		if got := Encode(-2.990, 104.761, precision); !strings.HasPrefix(full, got) {
+			t.Errorf("Encode(precision %d) = %q, not a prefix of %q", precision, got, full)
+		}
+	}
+}
+
+func TestBoundsContainsEncodedPoint(t *testing.T) {
+	points := [][2]float64{{-2.990, 104.761}, {57.64911, 10.40744}, {-33.8688, 151.2093}, {40.7128, -74.0060}}
+	for _, p := range points {
+		for precision := 1; precision <= MaxPrecision; precision++ {
+			minLat, maxLat, minLng, maxLng := bounds(Encode(p[0], p[1], precision))
+			if p[0] < minLat || p[0] > maxLat || p[1] < minLng || p[1] > maxLng {
+				t.Errorf("bounds(Encode(%v, %v, %d)) = [%v, %v] x [%v, %v], point outside", p[0], p[1], precision, minLat, maxLat, minLng, maxLng)
+			}
+		}
+	}
+}
+
+func TestPrecisionForRadius(t *testing.T) {
+	tests := []struct {
+		lat, radiusKM float64
+		want          int
+	}{
+		{0, 1, 5},
+		{0, 0.5, 6},
+		{0, 0.1, 7},
+		{0, 0.004, 9},
+		{60, 1, 5},
+		{0, 4.8, 5},
+		{60, 4.8, 4}, // cells are narrower away from the equator
+		{0, 10000, 1},
+	}
+	for _, tt := range tests {
+		if got := PrecisionForRadius(tt.lat, tt.radiusKM); got != tt.want {
+			t.Errorf("PrecisionForRadius(%v, %v) = %d, want %d", tt.lat, tt.radiusKM, got, tt.want)
+		}
+	}
+}
+
+func TestCoveringCells(t *testing.T) {
+	tests := []struct {
+		name         string
+		lat, lng, km float64
+		want         []string
+	}{
+		{
+			// The cell dqcjq and its eight neighbours
+			name: "neighbours", lat: 38.91357421875, lng: -77.05810546875, km: 1,
+			want: []string{"dqcjj", "dqcjm", "dqcjn", "dqcjp", "dqcjq", "dqcjr", "dqcjt", "dqcjw", "dqcjx"},
+		},
+		{
+			name: "across the antimeridian", lat: 0.01, lng: 179.999, km: 1,
+			want: []string{"2pbpb", "80000", "80002", "rzzzy", "rzzzz", "xbpbn", "xbpbp", "xbpbq", "xbpbr"},
+		},
+		{
+			name: "at the pole", lat: 89.99, lng: 0, km: 100,
+			want: []string{"e", "g", "s", "t", "u", "v"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CoveringCells(tt.lat, tt.lng, tt.km)
+			sort.Strings(got)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("CoveringCells(%v, %v, %v) = %v, want %v", tt.lat, tt.lng, tt.km, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Package paygate talks to online payment providers. Each provider implements Gateway; the
+// parking flows only see charges and their status.
+package paygate
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrInvalidSignature is returned for callbacks that were not signed by the provider
+	ErrInvalidSignature = errors.New("invalid callback signature")
+	// ErrChargeNotFound is returned when the provider does not know the charge
+	ErrChargeNotFound = errors.New("charge not found")
+)
+
+type ChargeStatus string
+
+const (
+	ChargeStatusPending ChargeStatus = "pending"
+	ChargeStatusPaid    ChargeStatus = "paid"
+	ChargeStatusExpired ChargeStatus = "expired"
+	ChargeStatusFailed  ChargeStatus = "failed"
+)
+
+// MethodQRIS is a dynamic QRIS code for the exact amount, paid from any bank or e-wallet app
+const MethodQRIS = "qris"
+
+type ChargeRequest struct {
+	OrderID     string // our reference, unique per charge
+	Amount      float64
+	Method      string
+	Description string
+	ExpiresAt   time.Time
+}
+
+type Charge struct {
+	ID        string       `json:"id"` // provider's reference
+	OrderID   string       `json:"order_id"`
+	Amount    float64      `json:"amount"`
+	Method    string       `json:"method"`
+	Status    ChargeStatus `json:"status"`
+	QRString  string       `json:"qr_string,omitempty"` // QRIS payload to render as a QR code
+	ExpiresAt time.Time    `json:"expires_at"`
+	PaidAt    *time.Time   `json:"paid_at,omitempty"`
+}
+
+// Gateway is an online payment provider
+type Gateway interface {
+	// Name identifies the provider on stored payments
+	Name() string
+	// CreateCharge asks the provider for a new pending charge
+	CreateCharge(req ChargeRequest) (*Charge, error)
+	// GetCharge queries the current status of a charge
+	GetCharge(chargeID string) (*Charge, error)
+	// ExpireCharge ends a pending charge so it can no longer be paid and returns its final
+	// state. A charge the customer paid first comes back paid.
+	ExpireCharge(chargeID string) (*Charge, error)
+	// ParseCallback checks the signature of a status callback and returns the charge it reports
+	ParseCallback(body []byte, header http.Header) (*Charge, error)
+}
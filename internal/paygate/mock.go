@@ -0,0 +1,266 @@
+package paygate
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// MockSignatureHeader carries the hex HMAC-SHA256 of a mock callback body
+const MockSignatureHeader = "X-Callback-Signature"
+
+// MockGateway is a provider for development. Charges are kept in a JSON file so they survive
+// restarts, and Pay plays the part of a customer paying, producing the signed callback a real
+// provider would send.
+type MockGateway struct {
+	mu      sync.Mutex
+	path    string
+	secret  []byte
+	charges map[string]*Charge
+}
+
+// mockCallback is the body of a mock status callback
+type mockCallback struct {
+	ChargeID string       `json:"charge_id"`
+	OrderID  string       `json:"order_id"`
+	Amount   float64      `json:"amount"`
+	Status   ChargeStatus `json:"status"`
+	PaidAt   *time.Time   `json:"paid_at,omitempty"`
+}
+
+func NewMockGateway(path, secret string) (*MockGateway, error) {
+	if secret == "" {
+		return nil, errors.New("mock payment gateway needs PAYMENT_WEBHOOK_SECRET")
+	}
+	g := &MockGateway{
+		path:    path,
+		secret:  []byte(secret),
+		charges: make(map[string]*Charge),
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return g, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock payments: %w", err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &g.charges); err != nil {
+			return nil, fmt.Errorf("failed to parse mock payments: %w", err)
+		}
+	}
+	return g, nil
+}
+
+func (g *MockGateway) Name() string {
+	return "mock"
+}
+
+func (g *MockGateway) CreateCharge(req ChargeRequest) (*Charge, error) {
+	if req.Method != MethodQRIS {
+		return nil, fmt.Errorf("payment method %s is not supported", req.Method)
+	}
+	if req.Amount <= 0 {
+		return nil, errors.New("charge amount must be positive")
+	}
+
+	id, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+	charge := &Charge{
+		ID:        id,
+		OrderID:   req.OrderID,
+		Amount:    req.Amount,
+		Method:    req.Method,
+		Status:    ChargeStatusPending,
+		QRString:  qrisPayload(id, req.Amount),
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.charges[id] = charge
+	if err := g.save(); err != nil {
+		delete(g.charges, id)
+		return nil, err
+	}
+	copied := *charge
+	return &copied, nil
+}
+
+func (g *MockGateway) GetCharge(chargeID string) (*Charge, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, ok := g.charges[chargeID]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+	if charge.Status == ChargeStatusPending && time.Now().After(charge.ExpiresAt) {
+		charge.Status = ChargeStatusExpired
+		if err := g.save(); err != nil {
+			return nil, err
+		}
+	}
+	copied := *charge
+	return &copied, nil
+}
+
+func (g *MockGateway) ExpireCharge(chargeID string) (*Charge, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, ok := g.charges[chargeID]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+	if charge.Status == ChargeStatusPending {
+		charge.Status = ChargeStatusExpired
+		if err := g.save(); err != nil {
+			return nil, err
+		}
+	}
+	copied := *charge
+	return &copied, nil
+}
+
+func (g *MockGateway) ParseCallback(body []byte, header http.Header) (*Charge, error) {
+	signature, err := hex.DecodeString(header.Get(MockSignatureHeader))
+	if err != nil || !hmac.Equal(signature, g.sign(body)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var callback mockCallback
+	if err := json.Unmarshal(body, &callback); err != nil {
+		return nil, fmt.Errorf("invalid callback body: %w", err)
+	}
+	return &Charge{
+		ID:      callback.ChargeID,
+		OrderID: callback.OrderID,
+		Amount:  callback.Amount,
+		Method:  MethodQRIS,
+		Status:  callback.Status,
+		PaidAt:  callback.PaidAt,
+	}, nil
+}
+
+// Pay marks a pending charge paid and returns the signed callback for it, as the provider would
+// send it to the webhook
+func (g *MockGateway) Pay(chargeID string) ([]byte, http.Header, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	charge, ok := g.charges[chargeID]
+	if !ok {
+		return nil, nil, ErrChargeNotFound
+	}
+	now := time.Now()
+	if charge.Status == ChargeStatusPending && now.After(charge.ExpiresAt) {
+		charge.Status = ChargeStatusExpired
+	}
+	if charge.Status == ChargeStatusPending {
+		charge.Status = ChargeStatusPaid
+		charge.PaidAt = &now
+	}
+	if err := g.save(); err != nil {
+		return nil, nil, err
+	}
+	if charge.Status != ChargeStatusPaid {
+		return nil, nil, fmt.Errorf("charge is %s", charge.Status)
+	}
+
+	body, err := json.Marshal(mockCallback{
+		ChargeID: charge.ID,
+		OrderID:  charge.OrderID,
+		Amount:   charge.Amount,
+		Status:   charge.Status,
+		PaidAt:   charge.PaidAt,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	header := http.Header{}
+	header.Set(MockSignatureHeader, hex.EncodeToString(g.sign(body)))
+	return body, header, nil
+}
+
+func (g *MockGateway) sign(body []byte) []byte {
+	mac := hmac.New(sha256.New, g.secret)
+	mac.Write(body)
+	return mac.Sum(nil)
+}
+
+// save writes the charges to a temporary file and renames it over the old one. Callers hold mu.
+func (g *MockGateway) save() error {
+	data, err := json.MarshalIndent(g.charges, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(g.path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to save mock payments: %w", err)
+		}
+	}
+	tmp := g.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to save mock payments: %w", err)
+	}
+	return os.Rename(tmp, g.path)
+}
+
+func randomID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "mock-" + hex.EncodeToString(b), nil
+}
+
+// qrisPayload builds a dynamic QRIS (EMVCo merchant-presented) payload for the amount, with the
+// charge ID as reference label. Any QRIS reader parses it, but the merchant is not real.
+func qrisPayload(reference string, amount float64) string {
+	merchant := tlv("00", "ID.CO.MOCK.WWW") + tlv("01", "936000000000000001") + tlv("03", "UMI")
+	payload := tlv("00", "01") + // payload format indicator
+		tlv("01", "12") + // dynamic, single use
+		tlv("26", merchant) +
+		tlv("52", "7523") + // MCC: parking lots and garages
+		tlv("53", "360") + // IDR
+		tlv("54", fmt.Sprintf("%.0f", amount)) +
+		tlv("58", "ID") +
+		tlv("59", "PARKIR DIGITAL") +
+		tlv("60", "PALEMBANG") +
+		tlv("62", tlv("05", reference)) +
+		"6304"
+	return payload + fmt.Sprintf("%04X", crc16CCITT([]byte(payload)))
+}
+
+func tlv(tag, value string) string {
+	return fmt.Sprintf("%s%02d%s", tag, len(value), value)
+}
+
+// crc16CCITT is CRC-16/CCITT-FALSE, the checksum QRIS payloads end with
+func crc16CCITT(data []byte) uint16 {
+	crc := uint16(0xFFFF)
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
@@ -0,0 +1,85 @@
+package paygate
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestCRC16CCITT(t *testing.T) {
+	tests := []struct {
+		data string
+		want uint16
+	}{
+		{"", 0xFFFF},
+		{"123456789", 0x29B1}, // CRC-16/CCITT-FALSE check value
+		{"A", 0xB915},
+	}
+	for _, tt := range tests {
+		if got := crc16CCITT([]byte(tt.data)); got != tt.want {
+			t.Errorf("crc16CCITT(%q) = %04X, want %04X", tt.data, got, tt.want)
+		}
+	}
+}
+
+// parseTLV splits an EMVCo payload into its tags, failing the test on a malformed length
+func parseTLV(t *testing.T, payload string) map[string]string {
+	t.Helper()
+	fields := map[string]string{}
+	for len(payload) > 0 {
+		if len(payload) < 4 {
+			t.Fatalf("truncated field %q", payload)
+		}
+		length, err := strconv.Atoi(payload[2:4])
+		if err != nil || len(payload) < 4+length {
+			t.Fatalf("bad length in %q", payload)
+		}
+		fields[payload[:2]] = payload[4 : 4+length]
+		payload = payload[4+length:]
+	}
+	return fields
+}
+
+func TestQRISPayload(t *testing.T) {
+	tests := []struct {
+		reference string
+		amount    float64
+		want      string
+	}{
+		{"mock-0123456789abcdef", 5000, "5000"},
+		{"mock-fedcba9876543210", 12500.4, "12500"},
+		{"mock-0000000000000000", 2999.6, "3000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reference, func(t *testing.T) {
+			payload := qrisPayload(tt.reference, tt.amount)
+
+			body, checksum := payload[:len(payload)-4], payload[len(payload)-4:]
+			if want := fmt.Sprintf("%04X", crc16CCITT([]byte(body))); checksum != want {
+				t.Errorf("checksum = %s, want %s", checksum, want)
+			}
+
+			fields := parseTLV(t, payload)
+			expected := map[string]string{
+				"00": "01",
+				"01": "12",
+				"52": "7523",
+				"53": "360",
+				"54": tt.want,
+				"58": "ID",
+				"63": checksum,
+			}
+			for tag, want := range expected {
+				if fields[tag] != want {
+					t.Errorf("tag %s = %q, want %q", tag, fields[tag], want)
+				}
+			}
+			if reference := parseTLV(t, fields["62"])["05"]; reference != tt.reference {
+				t.Errorf("reference label = %q, want %q", reference, tt.reference)
+			}
+			if merchant := parseTLV(t, fields["26"]); merchant["00"] == "" {
+				t.Errorf("merchant account info = %v, want a globally unique identifier", merchant)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// Package qrcard renders the QR cards jukirs show to customers: the check-in QR with the jukir
+// code and area name printed underneath, as a PNG, an SVG, or a printable PDF sheet of cards.
+package qrcard
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Card is one jukir's QR card
+type Card struct {
+	Token     string
+	JukirCode string
+	AreaName  string
+}
+
+// Format is an image format a single card can be rendered in
+type Format string
+
+const (
+	FormatPNG Format = "png"
+	FormatSVG Format = "svg"
+)
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	if f == FormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// Render renders a card in the given format
+func Render(card Card, format Format) ([]byte, error) {
+	switch format {
+	case FormatPNG:
+		return RenderPNG(card)
+	case FormatSVG:
+		return RenderSVG(card)
+	}
+	return nil, fmt.Errorf("unsupported QR format %q", format)
+}
+
+const (
+	cardQRSize     = 320
+	cardMargin     = 20
+	cardLineHeight = 22
+)
+
+// RenderPNG draws the card as a 360 px wide image
+func RenderPNG(card Card) ([]byte, error) {
+	q, err := qrcode.New(card.Token, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR: %w", err)
+	}
+
+	width := cardQRSize + 2*cardMargin
+	height := cardMargin + cardQRSize + 2*cardLineHeight + cardMargin
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	qrImage := q.Image(cardQRSize)
+	qrRect := image.Rect(cardMargin, cardMargin, cardMargin+cardQRSize, cardMargin+cardQRSize)
+	draw.Draw(img, qrRect, qrImage, qrImage.Bounds().Min, draw.Src)
+
+	drawer := &font.Drawer{Dst: img, Src: image.NewUniform(color.Black), Face: basicfont.Face7x13}
+	centered := func(s string, y int) {
+		drawer.Dot = fixed.P((width-drawer.MeasureString(s).Round())/2, y)
+		drawer.DrawString(s)
+	}
+	y := cardMargin + cardQRSize + cardLineHeight/2
+	centered(card.JukirCode, y)
+	centered(card.AreaName, y+cardLineHeight)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to render QR PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderSVG draws the card as an SVG with one rect per dark module, so it scales to any print size
+func RenderSVG(card Card) ([]byte, error) {
+	q, err := qrcode.New(card.Token, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR: %w", err)
+	}
+	bitmap := q.Bitmap()
+	modules := len(bitmap)
+	textHeight := 8 // in modules, room for two lines of text
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, modules, modules+textHeight)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/>`, modules, modules+textHeight)
+	buf.WriteString(`<path fill="#000" d="`)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	buf.WriteString(`"/>`)
+	writeText := func(s string, y int, size int) {
+		fmt.Fprintf(&buf, `<text x="%d" y="%d" font-family="Helvetica, Arial, sans-serif" font-size="%d" text-anchor="middle">`, modules/2, y, size)
+		xml.EscapeText(&buf, []byte(s))
+		buf.WriteString(`</text>`)
+	}
+	writeText(card.JukirCode, modules+2, 3)
+	writeText(card.AreaName, modules+6, 3)
+	buf.WriteString(`</svg>`)
+	return buf.Bytes(), nil
+}
+
+// RenderSheetPDF lays cards out on A4 pages, three across and four down, with cut lines around
+// each card. The title is printed at the top of every page.
+func RenderSheetPDF(title string, cards []Card) ([]byte, error) {
+	const (
+		columns    = 3
+		rows       = 4
+		cardWidth  = 60.0
+		cardHeight = 64.0
+		qrSize     = 48.0
+		marginLeft = 15.0
+		marginTop  = 20.0
+	)
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetAutoPageBreak(false, 0)
+	options := gofpdf.ImageOptions{ImageType: "PNG"}
+
+	for i, card := range cards {
+		slot := i % (columns * rows)
+		if slot == 0 {
+			pdf.AddPage()
+			pdf.SetFont("Helvetica", "B", 12)
+			pdf.SetXY(marginLeft, 8)
+			pdf.CellFormat(columns*cardWidth, 8, title, "", 0, "C", false, 0, "")
+		}
+
+		qr, err := qrcode.Encode(card.Token, qrcode.Medium, 512)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode QR for %s: %w", card.JukirCode, err)
+		}
+		imageName := fmt.Sprintf("qr-%d", i)
+		pdf.RegisterImageOptionsReader(imageName, options, bytes.NewReader(qr))
+
+		x := marginLeft + float64(slot%columns)*cardWidth
+		y := marginTop + float64(slot/columns)*cardHeight
+		pdf.SetDrawColor(180, 180, 180)
+		pdf.Rect(x, y, cardWidth, cardHeight, "D")
+		pdf.ImageOptions(imageName, x+(cardWidth-qrSize)/2, y+3, qrSize, qrSize, false, options, 0, "")
+
+		pdf.SetFont("Helvetica", "B", 10)
+		pdf.SetXY(x, y+qrSize+3)
+		pdf.CellFormat(cardWidth, 5, card.JukirCode, "", 0, "C", false, 0, "")
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetXY(x, y+qrSize+8)
+		pdf.CellFormat(cardWidth, 4, card.AreaName, "", 0, "C", false, 0, "")
+	}
+	if len(cards) == 0 {
+		pdf.AddPage()
+		pdf.SetFont("Helvetica", "", 10)
+		pdf.CellFormat(0, 10, title+": no active jukirs", "", 0, "C", false, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render QR sheet: %w", err)
+	}
+	return buf.Bytes(), nil
+}
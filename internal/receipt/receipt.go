@@ -0,0 +1,172 @@
+// Package receipt renders e-karcis (parking receipts) as PDF and PNG documents with a QR code
+// that links to the public verification endpoint.
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// Data is everything printed on an e-karcis
+type Data struct {
+	Code         string
+	AreaName     string
+	JukirCode    string
+	VehicleType  string
+	PlatNomor    string
+	CheckinTime  time.Time
+	CheckoutTime *time.Time
+	Amount       float64
+	VerifyURL    string
+}
+
+const title = "E-KARCIS PARKIR"
+
+// lines returns the label/value rows shared by the PDF and PNG layouts
+func (d Data) lines() [][2]string {
+	plate := d.PlatNomor
+	if plate == "" {
+		plate = "-"
+	}
+	jukirCode := d.JukirCode
+	if jukirCode == "" {
+		jukirCode = "-"
+	}
+	checkout := "-"
+	if d.CheckoutTime != nil {
+		checkout = formatTime(*d.CheckoutTime)
+	}
+	return [][2]string{
+		{"No. Karcis", d.Code},
+		{"Area", d.AreaName},
+		{"Jukir", jukirCode},
+		{"Kendaraan", d.VehicleType},
+		{"Plat Nomor", plate},
+		{"Masuk", formatTime(d.CheckinTime)},
+		{"Keluar", checkout},
+		{"Biaya", FormatRupiah(d.Amount)},
+	}
+}
+
+// RenderPDF lays the receipt out on an 80 mm wide page, the width of a thermal printer roll
+func RenderPDF(d Data) ([]byte, error) {
+	qr, err := qrcode.Encode(d.VerifyURL, qrcode.Medium, 256)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode verification QR: %w", err)
+	}
+
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		UnitStr: "mm",
+		Size:    gofpdf.SizeType{Wd: 80, Ht: 150},
+	})
+	pdf.SetMargins(6, 6, 6)
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.AddPage()
+
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(0, 7, title, "", 1, "C", false, 0, "")
+	pdf.Ln(2)
+
+	pdf.SetFont("Helvetica", "", 9)
+	for _, line := range d.lines() {
+		pdf.CellFormat(24, 5, line[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 5, line[1], "", 1, "L", false, 0, "")
+	}
+
+	pdf.Ln(3)
+	options := gofpdf.ImageOptions{ImageType: "PNG"}
+	pdf.RegisterImageOptionsReader("verify-qr", options, bytes.NewReader(qr))
+	pdf.ImageOptions("verify-qr", 22, pdf.GetY(), 36, 36, false, options, 0, "")
+	pdf.SetY(pdf.GetY() + 37)
+
+	pdf.SetFont("Helvetica", "", 7)
+	pdf.CellFormat(0, 4, "Pindai untuk memeriksa keaslian karcis", "", 1, "C", false, 0, "")
+	pdf.CellFormat(0, 4, d.VerifyURL, "", 1, "C", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("failed to render receipt PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderPNG draws the same receipt as an image for sharing in chat apps
+func RenderPNG(d Data) ([]byte, error) {
+	q, err := qrcode.New(d.VerifyURL, qrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode verification QR: %w", err)
+	}
+
+	const (
+		width      = 400
+		margin     = 20
+		lineHeight = 20
+		qrSize     = 200
+	)
+	lines := d.lines()
+	height := margin + 2*lineHeight + len(lines)*lineHeight + margin + qrSize + 2*lineHeight + margin
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	drawer := &font.Drawer{Dst: img, Src: image.NewUniform(color.Black), Face: basicfont.Face7x13}
+	text := func(s string, x, y int) {
+		drawer.Dot = fixed.P(x, y)
+		drawer.DrawString(s)
+	}
+	centered := func(s string, y int) {
+		text(s, (width-drawer.MeasureString(s).Round())/2, y)
+	}
+
+	y := margin + lineHeight
+	centered(title, y)
+	y += 2 * lineHeight
+	for _, line := range lines {
+		text(line[0], margin, y)
+		text(line[1], margin+110, y)
+		y += lineHeight
+	}
+
+	y += margin / 2
+	qrImage := q.Image(qrSize)
+	qrRect := image.Rect((width-qrSize)/2, y, (width+qrSize)/2, y+qrSize)
+	draw.Draw(img, qrRect, qrImage, qrImage.Bounds().Min, draw.Src)
+	y += qrSize + lineHeight
+	centered("Pindai untuk memeriksa keaslian karcis", y)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to render receipt PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// FormatRupiah formats an amount the way it is printed on karcis: "Rp 12.500"
+func FormatRupiah(amount float64) string {
+	digits := strconv.FormatInt(int64(amount+0.5), 10)
+	var grouped strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	return "Rp " + grouped.String()
+}
+
+func formatTime(t time.Time) string {
+	return t.Format("02 Jan 2006 15:04") + " WIB"
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"be-parkir/internal/domain/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type CashDepositRepository interface {
+	Create(deposit *entities.CashDeposit) error
+	GetByID(id uint) (*entities.CashDeposit, error)
+	GetByJukirAndDate(jukirID uint, date time.Time) (*entities.CashDeposit, error)
+	Update(deposit *entities.CashDeposit) error
+	List(filter entities.CashDepositFilter, limit, offset int) ([]entities.CashDeposit, int64, error)
+	ListByDateRange(jukirIDs []uint, from, to time.Time) ([]entities.CashDeposit, error)
+}
+
+type cashDepositRepository struct {
+	db *gorm.DB
+}
+
+func NewCashDepositRepository(db *gorm.DB) CashDepositRepository {
+	return &cashDepositRepository{db: db}
+}
+
+func (r *cashDepositRepository) Create(deposit *entities.CashDeposit) error {
+	return r.db.Create(deposit).Error
+}
+
+func (r *cashDepositRepository) GetByID(id uint) (*entities.CashDeposit, error) {
+	var deposit entities.CashDeposit
+	err := r.db.Preload("Jukir.User").Preload("Jukir.Area").Preload("Receiver").First(&deposit, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &deposit, nil
+}
+
+func (r *cashDepositRepository) GetByJukirAndDate(jukirID uint, date time.Time) (*entities.CashDeposit, error) {
+	var deposit entities.CashDeposit
+	err := r.db.Preload("Receiver").
+		Where("jukir_id = ? AND date = ?", jukirID, date.Format("2006-01-02")).
+		First(&deposit).Error
+	if err != nil {
+		return nil, err
+	}
+	return &deposit, nil
+}
+
+func (r *cashDepositRepository) Update(deposit *entities.CashDeposit) error {
+	return r.db.Model(&entities.CashDeposit{}).Where("id = ?", deposit.ID).Updates(map[string]interface{}{
+		"expected_amount":  deposit.ExpectedAmount,
+		"deposited_amount": deposit.DepositedAmount,
+		"variance":         deposit.Variance,
+		"notes":            deposit.Notes,
+	}).Error
+}
+
+// List returns deposits newest date first. StartDate and EndDate are compared as calendar dates.
+func (r *cashDepositRepository) List(filter entities.CashDepositFilter, limit, offset int) ([]entities.CashDeposit, int64, error) {
+	deposits := make([]entities.CashDeposit, 0)
+	var count int64
+
+	query := r.db.Model(&entities.CashDeposit{})
+	if filter.JukirID != nil {
+		query = query.Where("cash_deposits.jukir_id = ?", *filter.JukirID)
+	}
+	if filter.Regional != nil {
+		query = query.
+			Joins("JOIN jukirs ON jukirs.id = cash_deposits.jukir_id").
+			Joins("JOIN parking_areas ON parking_areas.id = jukirs.area_id").
+			Where("parking_areas.regional = ?", *filter.Regional)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("cash_deposits.date >= ?", filter.StartDate.Format("2006-01-02"))
+	}
+	if filter.EndDate != nil {
+		query = query.Where("cash_deposits.date <= ?", filter.EndDate.Format("2006-01-02"))
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Jukir.User").Preload("Jukir.Area").Preload("Receiver").
+		Order("cash_deposits.date DESC, cash_deposits.id DESC").
+		Limit(limit).Offset(offset).
+		Find(&deposits).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return deposits, count, nil
+}
+
+// ListByDateRange returns the deposits dated from..to (calendar dates, inclusive) of the given
+// jukirs, or of every jukir when jukirIDs is nil
+func (r *cashDepositRepository) ListByDateRange(jukirIDs []uint, from, to time.Time) ([]entities.CashDeposit, error) {
+	deposits := make([]entities.CashDeposit, 0)
+	query := r.db.Model(&entities.CashDeposit{}).
+		Where("date >= ? AND date <= ?", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if jukirIDs != nil {
+		query = query.Where("jukir_id IN ?", jukirIDs)
+	}
+	err := query.Order("jukir_id ASC, date ASC").Find(&deposits).Error
+	return deposits, err
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"be-parkir/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+type CustomerVehicleRepository interface {
+	Create(vehicle *entities.CustomerVehicle) error
+	GetByID(id uint) (*entities.CustomerVehicle, error)
+	GetByUserAndPlate(userID uint, platNomor string) (*entities.CustomerVehicle, error)
+	ListByUser(userID uint) ([]entities.CustomerVehicle, error)
+	Update(vehicle *entities.CustomerVehicle) error
+	Delete(id uint) error
+}
+
+type customerVehicleRepository struct {
+	db *gorm.DB
+}
+
+func NewCustomerVehicleRepository(db *gorm.DB) CustomerVehicleRepository {
+	return &customerVehicleRepository{db: db}
+}
+
+func (r *customerVehicleRepository) Create(vehicle *entities.CustomerVehicle) error {
+	return r.db.Create(vehicle).Error
+}
+
+func (r *customerVehicleRepository) GetByID(id uint) (*entities.CustomerVehicle, error) {
+	var vehicle entities.CustomerVehicle
+	err := r.db.First(&vehicle, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &vehicle, nil
+}
+
+func (r *customerVehicleRepository) GetByUserAndPlate(userID uint, platNomor string) (*entities.CustomerVehicle, error) {
+	var vehicle entities.CustomerVehicle
+	err := r.db.Where("user_id = ? AND plat_nomor = ?", userID, platNomor).First(&vehicle).Error
+	if err != nil {
+		return nil, err
+	}
+	return &vehicle, nil
+}
+
+func (r *customerVehicleRepository) ListByUser(userID uint) ([]entities.CustomerVehicle, error) {
+	vehicles := make([]entities.CustomerVehicle, 0)
+	err := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&vehicles).Error
+	return vehicles, err
+}
+
+func (r *customerVehicleRepository) Update(vehicle *entities.CustomerVehicle) error {
+	return r.db.Save(vehicle).Error
+}
+
+func (r *customerVehicleRepository) Delete(id uint) error {
+	return r.db.Delete(&entities.CustomerVehicle{}, id).Error
+}
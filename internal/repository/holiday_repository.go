@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"be-parkir/internal/domain/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type HolidayRepository interface {
+	Create(holiday *entities.Holiday) error
+	GetByID(id uint) (*entities.Holiday, error)
+	List(startDate, endDate *time.Time) ([]entities.Holiday, error)
+	IsHoliday(date time.Time) (bool, error)
+	Delete(id uint) error
+}
+
+type holidayRepository struct {
+	db *gorm.DB
+}
+
+func NewHolidayRepository(db *gorm.DB) HolidayRepository {
+	return &holidayRepository{db: db}
+}
+
+func (r *holidayRepository) Create(holiday *entities.Holiday) error {
+	return r.db.Create(holiday).Error
+}
+
+func (r *holidayRepository) GetByID(id uint) (*entities.Holiday, error) {
+	var holiday entities.Holiday
+	err := r.db.First(&holiday, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &holiday, nil
+}
+
+func (r *holidayRepository) List(startDate, endDate *time.Time) ([]entities.Holiday, error) {
+	holidays := make([]entities.Holiday, 0)
+	query := r.db.Model(&entities.Holiday{})
+	if startDate != nil {
+		query = query.Where("date >= ?", startDate.Format("2006-01-02"))
+	}
+	if endDate != nil {
+		query = query.Where("date <= ?", endDate.Format("2006-01-02"))
+	}
+	err := query.Order("date ASC").Find(&holidays).Error
+	return holidays, err
+}
+
+// IsHoliday checks the calendar date of the given time, in its own location
+func (r *holidayRepository) IsHoliday(date time.Time) (bool, error) {
+	var count int64
+	err := r.db.Model(&entities.Holiday{}).Where("date = ?", date.Format("2006-01-02")).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *holidayRepository) Delete(id uint) error {
+	return r.db.Delete(&entities.Holiday{}, id).Error
+}
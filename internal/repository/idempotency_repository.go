@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"be-parkir/internal/domain/entities"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type IdempotencyRepository interface {
+	// Reserve claims the key for a new request. If the key is already taken, the existing record is returned.
+	Reserve(key, requestHash string, ttl time.Duration) (*entities.IdempotencyRecord, error)
+	Complete(key string, record *entities.IdempotencyRecord, ttl time.Duration) error
+	Release(key string) error
+}
+
+type idempotencyRepository struct {
+	redis *redis.Client
+}
+
+func NewIdempotencyRepository(redis *redis.Client) IdempotencyRepository {
+	return &idempotencyRepository{redis: redis}
+}
+
+func idempotencyKey(key string) string {
+	return "idempotency:" + key
+}
+
+func (r *idempotencyRepository) Reserve(key, requestHash string, ttl time.Duration) (*entities.IdempotencyRecord, error) {
+	ctx := context.Background()
+	pending, err := json.Marshal(entities.IdempotencyRecord{RequestHash: requestHash})
+	if err != nil {
+		return nil, err
+	}
+
+	reserved, err := r.redis.SetNX(ctx, idempotencyKey(key), pending, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if reserved {
+		return nil, nil
+	}
+
+	data, err := r.redis.Get(ctx, idempotencyKey(key)).Bytes()
+	if err != nil {
+		return nil, err
+	}
+	var existing entities.IdempotencyRecord
+	if err := json.Unmarshal(data, &existing); err != nil {
+		return nil, err
+	}
+	return &existing, nil
+}
+
+func (r *idempotencyRepository) Complete(key string, record *entities.IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return r.redis.Set(context.Background(), idempotencyKey(key), data, ttl).Err()
+}
+
+func (r *idempotencyRepository) Release(key string) error {
+	return r.redis.Del(context.Background(), idempotencyKey(key)).Err()
+}
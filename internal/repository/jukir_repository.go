@@ -2,6 +2,7 @@ package repository
 
 import (
 	"be-parkir/internal/domain/entities"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -17,6 +18,9 @@ type JukirRepository interface {
 	List(limit, offset int) ([]entities.Jukir, int64, error)
 	GetByAreaID(areaID uint) ([]entities.Jukir, error)
 	GetPendingJukirs() ([]entities.Jukir, error)
+	GetActiveByRegional(regional string) ([]entities.Jukir, error)
+	GetByIDs(ids []uint) ([]entities.Jukir, error)
+	UpdateQRToken(jukirID uint, qrToken string, previousToken *string, previousExpiresAt *time.Time, rotatedAt time.Time) error
 }
 
 type jukirRepository struct {
@@ -33,7 +37,7 @@ func (r *jukirRepository) Create(jukir *entities.Jukir) error {
 
 func (r *jukirRepository) GetByID(id uint) (*entities.Jukir, error) {
 	var jukir entities.Jukir
-	err := r.db.Preload("User").Preload("Area").First(&jukir, id).Error
+	err := r.db.Preload("User").Preload("Area.VehicleRates").First(&jukir, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -42,16 +46,19 @@ func (r *jukirRepository) GetByID(id uint) (*entities.Jukir, error) {
 
 func (r *jukirRepository) GetByUserID(userID uint) (*entities.Jukir, error) {
 	var jukir entities.Jukir
-	err := r.db.Preload("User").Preload("Area").Where("user_id = ?", userID).First(&jukir).Error
+	err := r.db.Preload("User").Preload("Area.VehicleRates").Where("user_id = ?", userID).First(&jukir).Error
 	if err != nil {
 		return nil, err
 	}
 	return &jukir, nil
 }
 
+// GetByQRToken also accepts the token replaced by the last rotation while its grace period lasts
 func (r *jukirRepository) GetByQRToken(qrToken string) (*entities.Jukir, error) {
 	var jukir entities.Jukir
-	err := r.db.Preload("User").Preload("Area").Where("qr_token = ?", qrToken).First(&jukir).Error
+	err := r.db.Preload("User").Preload("Area.VehicleRates").
+		Where("qr_token = ? OR (previous_qr_token = ? AND previous_qr_token_expires_at > ?)", qrToken, qrToken, time.Now()).
+		First(&jukir).Error
 	if err != nil {
 		return nil, err
 	}
@@ -60,7 +67,7 @@ func (r *jukirRepository) GetByQRToken(qrToken string) (*entities.Jukir, error)
 
 func (r *jukirRepository) GetByJukirCode(jukirCode string) (*entities.Jukir, error) {
 	var jukir entities.Jukir
-	err := r.db.Preload("User").Preload("Area").Where("jukir_code = ?", jukirCode).First(&jukir).Error
+	err := r.db.Preload("User").Preload("Area.VehicleRates").Where("jukir_code = ?", jukirCode).First(&jukir).Error
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +92,7 @@ func (r *jukirRepository) List(limit, offset int) ([]entities.Jukir, int64, erro
 	}
 
 	// Fetch jukirs with preloaded relations
-	err := r.db.Preload("User").Preload("Area").Limit(limit).Offset(offset).Find(&jukirs).Error
+	err := r.db.Preload("User").Preload("Area.VehicleRates").Limit(limit).Offset(offset).Find(&jukirs).Error
 	if err != nil {
 		return nil, 0, err
 	}
@@ -95,12 +102,56 @@ func (r *jukirRepository) List(limit, offset int) ([]entities.Jukir, int64, erro
 
 func (r *jukirRepository) GetByAreaID(areaID uint) ([]entities.Jukir, error) {
 	var jukirs []entities.Jukir
-	err := r.db.Preload("User").Preload("Area").Where("area_id = ?", areaID).Find(&jukirs).Error
+	err := r.db.Preload("User").Preload("Area.VehicleRates").Where("area_id = ?", areaID).Find(&jukirs).Error
 	return jukirs, err
 }
 
 func (r *jukirRepository) GetPendingJukirs() ([]entities.Jukir, error) {
 	var jukirs []entities.Jukir
-	err := r.db.Preload("User").Preload("Area").Where("status = ?", entities.JukirStatusPending).Find(&jukirs).Error
+	err := r.db.Preload("User").Preload("Area.VehicleRates").Where("status = ?", entities.JukirStatusPending).Find(&jukirs).Error
 	return jukirs, err
 }
+
+// GetActiveByRegional returns the active jukirs of every area in a region, ordered by area then code
+func (r *jukirRepository) GetActiveByRegional(regional string) ([]entities.Jukir, error) {
+	var jukirs []entities.Jukir
+	err := r.db.Preload("User").Preload("Area.VehicleRates").
+		Joins("JOIN parking_areas ON parking_areas.id = jukirs.area_id").
+		Where("parking_areas.regional = ? AND jukirs.status = ?", regional, entities.JukirStatusActive).
+		Order("parking_areas.name ASC, jukirs.jukir_code ASC").
+		Find(&jukirs).Error
+	return jukirs, err
+}
+
+// GetByIDs returns the given jukirs, including deleted ones, so reports over past days still
+// name everyone who worked them
+func (r *jukirRepository) GetByIDs(ids []uint) ([]entities.Jukir, error) {
+	jukirs := make([]entities.Jukir, 0)
+	if len(ids) == 0 {
+		return jukirs, nil
+	}
+	err := r.db.Unscoped().Preload("User", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Preload("Area", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Where("id IN ?", ids).Find(&jukirs).Error
+	return jukirs, err
+}
+
+// UpdateQRToken sets a new QR token. previousToken and previousExpiresAt may be nil to stop
+// accepting the old token at once.
+func (r *jukirRepository) UpdateQRToken(jukirID uint, qrToken string, previousToken *string, previousExpiresAt *time.Time, rotatedAt time.Time) error {
+	result := r.db.Model(&entities.Jukir{}).Where("id = ?", jukirID).Updates(map[string]interface{}{
+		"qr_token":                     qrToken,
+		"previous_qr_token":            previousToken,
+		"previous_qr_token_expires_at": previousExpiresAt,
+		"qr_rotated_at":                rotatedAt,
+	})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
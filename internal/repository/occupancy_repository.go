@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"be-parkir/internal/domain/entities"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// occupancyTTL bounds how long a Redis counter can drift from the database:
+// expired counters are re-seeded from active sessions and open reservations on next use
+const occupancyTTL = 10 * time.Minute
+
+// adjustOccupancyScript applies a delta to an existing counter, never going below zero.
+// It returns nil when the counter is missing so the caller can seed it first.
+var adjustOccupancyScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return false
+end
+local value = redis.call('INCRBY', KEYS[1], ARGV[1])
+if value < 0 then
+	redis.call('SET', KEYS[1], 0, 'KEEPTTL')
+	value = 0
+end
+return value
+`)
+
+// OccupancyRepository keeps the number of occupied slots per area and vehicle type in Redis.
+// A slot is occupied by an active session or held by an open reservation.
+type OccupancyRepository interface {
+	Get(areaID uint, vehicleType entities.VehicleType) (int64, error)
+	Increment(areaID uint, vehicleType entities.VehicleType) (int64, error)
+	Decrement(areaID uint, vehicleType entities.VehicleType) error
+	CountActive(areaID uint, vehicleType entities.VehicleType) (int64, error)
+	ReconcileAll() error
+}
+
+type occupancyRepository struct {
+	db    *gorm.DB
+	redis *redis.Client
+}
+
+func NewOccupancyRepository(db *gorm.DB, redis *redis.Client) OccupancyRepository {
+	return &occupancyRepository{db: db, redis: redis}
+}
+
+func occupancyKey(areaID uint, vehicleType entities.VehicleType) string {
+	return fmt.Sprintf("occupancy:%d:%s", areaID, vehicleType)
+}
+
+func (r *occupancyRepository) Get(areaID uint, vehicleType entities.VehicleType) (int64, error) {
+	ctx := context.Background()
+	value, err := r.redis.Get(ctx, occupancyKey(areaID, vehicleType)).Int64()
+	if err == redis.Nil {
+		return r.seed(ctx, areaID, vehicleType)
+	}
+	return value, err
+}
+
+func (r *occupancyRepository) Increment(areaID uint, vehicleType entities.VehicleType) (int64, error) {
+	return r.adjust(areaID, vehicleType, 1)
+}
+
+func (r *occupancyRepository) Decrement(areaID uint, vehicleType entities.VehicleType) error {
+	_, err := r.adjust(areaID, vehicleType, -1)
+	return err
+}
+
+func (r *occupancyRepository) CountActive(areaID uint, vehicleType entities.VehicleType) (int64, error) {
+	var sessions, reservations int64
+	err := r.db.Model(&entities.ParkingSession{}).
+		Where("area_id = ? AND vehicle_type = ? AND session_status = ?", areaID, vehicleType, entities.SessionStatusActive).
+		Count(&sessions).Error
+	if err != nil {
+		return 0, err
+	}
+	err = r.db.Model(&entities.Reservation{}).
+		Where("area_id = ? AND vehicle_type = ? AND status = ?", areaID, vehicleType, entities.ReservationStatusReserved).
+		Count(&reservations).Error
+	return sessions + reservations, err
+}
+
+// ReconcileAll overwrites every counter with the active session and open reservation counts from the database
+func (r *occupancyRepository) ReconcileAll() error {
+	type occupancyRow struct {
+		AreaID      uint
+		VehicleType entities.VehicleType
+		Count       int64
+	}
+	var sessionRows, reservationRows []occupancyRow
+	err := r.db.Model(&entities.ParkingSession{}).
+		Select("area_id, vehicle_type, COUNT(*) AS count").
+		Where("session_status = ?", entities.SessionStatusActive).
+		Group("area_id, vehicle_type").
+		Scan(&sessionRows).Error
+	if err != nil {
+		return err
+	}
+	err = r.db.Model(&entities.Reservation{}).
+		Select("area_id, vehicle_type, COUNT(*) AS count").
+		Where("status = ?", entities.ReservationStatusReserved).
+		Group("area_id, vehicle_type").
+		Scan(&reservationRows).Error
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[string]int64, len(sessionRows))
+	for _, row := range append(sessionRows, reservationRows...) {
+		counts[occupancyKey(row.AreaID, row.VehicleType)] += row.Count
+	}
+
+	ctx := context.Background()
+	// Drop existing counters so types without active sessions or reservations are re-seeded at zero
+	iter := r.redis.Scan(ctx, 0, "occupancy:*", 100).Iterator()
+	for iter.Next(ctx) {
+		if err := r.redis.Del(ctx, iter.Val()).Err(); err != nil {
+			return err
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+
+	pipe := r.redis.Pipeline()
+	for key, count := range counts {
+		pipe.Set(ctx, key, count, occupancyTTL)
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+func (r *occupancyRepository) adjust(areaID uint, vehicleType entities.VehicleType, delta int64) (int64, error) {
+	ctx := context.Background()
+	key := occupancyKey(areaID, vehicleType)
+
+	value, err := adjustOccupancyScript.Run(ctx, r.redis, []string{key}, delta).Int64()
+	if err != redis.Nil {
+		return value, err
+	}
+
+	// Counter expired or never existed: seed it from the database. Check-outs are counted
+	// after the session is closed, so the seeded value already reflects a decrement.
+	seeded, err := r.seed(ctx, areaID, vehicleType)
+	if err != nil || delta < 0 {
+		return seeded, err
+	}
+	return adjustOccupancyScript.Run(ctx, r.redis, []string{key}, delta).Int64()
+}
+
+func (r *occupancyRepository) seed(ctx context.Context, areaID uint, vehicleType entities.VehicleType) (int64, error) {
+	count, err := r.CountActive(areaID, vehicleType)
+	if err != nil {
+		return 0, err
+	}
+
+	key := occupancyKey(areaID, vehicleType)
+	// SetNX so a counter seeded concurrently by another request is kept
+	if err := r.redis.SetNX(ctx, key, count, occupancyTTL).Err(); err != nil {
+		return 0, err
+	}
+	return r.redis.Get(ctx, key).Int64()
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"be-parkir/internal/domain/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OperatingHoursRepository interface {
+	ListWeekly(areaIDs []uint) ([]entities.AreaOperatingHours, error)
+	ReplaceWeekly(areaID uint, hours []entities.AreaOperatingHours) error
+	ListExceptions(areaIDs []uint, from, to time.Time) ([]entities.AreaScheduleException, error)
+	GetExceptionByID(id uint) (*entities.AreaScheduleException, error)
+	CreateException(exception *entities.AreaScheduleException) error
+	DeleteException(id uint) error
+}
+
+type operatingHoursRepository struct {
+	db *gorm.DB
+}
+
+func NewOperatingHoursRepository(db *gorm.DB) OperatingHoursRepository {
+	return &operatingHoursRepository{db: db}
+}
+
+// ListWeekly returns the weekly hours of the given areas, or of every area when areaIDs is nil
+func (r *operatingHoursRepository) ListWeekly(areaIDs []uint) ([]entities.AreaOperatingHours, error) {
+	hours := make([]entities.AreaOperatingHours, 0)
+	query := r.db.Model(&entities.AreaOperatingHours{})
+	if areaIDs != nil {
+		query = query.Where("area_id IN ?", areaIDs)
+	}
+	err := query.Order("area_id ASC, day_of_week ASC").Find(&hours).Error
+	return hours, err
+}
+
+// ReplaceWeekly swaps the area's weekly hours for the given set in one transaction
+func (r *operatingHoursRepository) ReplaceWeekly(areaID uint, hours []entities.AreaOperatingHours) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("area_id = ?", areaID).Delete(&entities.AreaOperatingHours{}).Error; err != nil {
+			return err
+		}
+		if len(hours) == 0 {
+			return nil
+		}
+		for i := range hours {
+			hours[i].AreaID = areaID
+		}
+		return tx.Create(&hours).Error
+	})
+}
+
+// ListExceptions returns the exceptions dated from..to (calendar dates, inclusive) of the given
+// areas, or of every area when areaIDs is nil
+func (r *operatingHoursRepository) ListExceptions(areaIDs []uint, from, to time.Time) ([]entities.AreaScheduleException, error) {
+	exceptions := make([]entities.AreaScheduleException, 0)
+	query := r.db.Model(&entities.AreaScheduleException{}).
+		Where("date >= ? AND date <= ?", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if areaIDs != nil {
+		query = query.Where("area_id IN ?", areaIDs)
+	}
+	err := query.Order("date ASC").Find(&exceptions).Error
+	return exceptions, err
+}
+
+func (r *operatingHoursRepository) GetExceptionByID(id uint) (*entities.AreaScheduleException, error) {
+	var exception entities.AreaScheduleException
+	err := r.db.First(&exception, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &exception, nil
+}
+
+func (r *operatingHoursRepository) CreateException(exception *entities.AreaScheduleException) error {
+	return r.db.Create(exception).Error
+}
+
+func (r *operatingHoursRepository) DeleteException(id uint) error {
+	return r.db.Delete(&entities.AreaScheduleException{}, id).Error
+}
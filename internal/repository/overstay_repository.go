@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"be-parkir/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+type OverstayRepository interface {
+	Create(event *entities.OverstayEvent) error
+	GetUnflaggedActiveSessions(areaID uint) ([]entities.ParkingSession, error)
+	List(areaID *uint, action *entities.OverstayAction, limit, offset int) ([]entities.OverstayEvent, int64, error)
+}
+
+type overstayRepository struct {
+	db *gorm.DB
+}
+
+func NewOverstayRepository(db *gorm.DB) OverstayRepository {
+	return &overstayRepository{db: db}
+}
+
+func (r *overstayRepository) Create(event *entities.OverstayEvent) error {
+	return r.db.Omit("Session", "Area").Create(event).Error
+}
+
+// GetUnflaggedActiveSessions returns the area's active sessions the scheduler has not acted on yet
+func (r *overstayRepository) GetUnflaggedActiveSessions(areaID uint) ([]entities.ParkingSession, error) {
+	var sessions []entities.ParkingSession
+	err := r.db.
+		Where("area_id = ? AND session_status = ?", areaID, entities.SessionStatusActive).
+		Where("NOT EXISTS (SELECT 1 FROM overstay_events WHERE overstay_events.session_id = parking_sessions.id)").
+		Order("checkin_time ASC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+func (r *overstayRepository) List(areaID *uint, action *entities.OverstayAction, limit, offset int) ([]entities.OverstayEvent, int64, error) {
+	var events []entities.OverstayEvent
+	var count int64
+
+	query := r.db.Model(&entities.OverstayEvent{})
+	if areaID != nil {
+		query = query.Where("area_id = ?", *areaID)
+	}
+	if action != nil {
+		query = query.Where("action = ?", *action)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Session").Preload("Area").
+		Order("detected_at DESC").
+		Limit(limit).Offset(offset).Find(&events).Error
+	return events, count, err
+}
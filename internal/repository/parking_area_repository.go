@@ -2,7 +2,9 @@ package repository
 
 import (
 	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/geohash"
 	"math"
+	"strings"
 
 	"gorm.io/gorm"
 )
@@ -15,6 +17,9 @@ type ParkingAreaRepository interface {
 	List(limit, offset int) ([]entities.ParkingArea, int64, error)
 	GetNearbyAreas(lat, lng, radius float64) ([]entities.ParkingArea, error)
 	GetActiveAreas() ([]entities.ParkingArea, error)
+	ReplaceVehicleRates(areaID uint, rates []entities.AreaVehicleRate) error
+	BackfillGeohashes() (int, error)
+	UpdateStatusOperasional(areaID uint, status string) error
 }
 
 type parkingAreaRepository struct {
@@ -26,12 +31,13 @@ func NewParkingAreaRepository(db *gorm.DB) ParkingAreaRepository {
 }
 
 func (r *parkingAreaRepository) Create(area *entities.ParkingArea) error {
+	area.Geohash = geohash.Encode(area.Latitude, area.Longitude, geohash.MaxPrecision)
 	return r.db.Create(area).Error
 }
 
 func (r *parkingAreaRepository) GetByID(id uint) (*entities.ParkingArea, error) {
 	var area entities.ParkingArea
-	err := r.db.Preload("Jukirs").First(&area, id).Error
+	err := r.db.Preload("Jukirs").Preload("VehicleRates").First(&area, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -39,7 +45,8 @@ func (r *parkingAreaRepository) GetByID(id uint) (*entities.ParkingArea, error)
 }
 
 func (r *parkingAreaRepository) Update(area *entities.ParkingArea) error {
-	return r.db.Save(area).Error
+	area.Geohash = geohash.Encode(area.Latitude, area.Longitude, geohash.MaxPrecision)
+	return r.db.Omit("VehicleRates", "TariffSchedules", "OperatingHours", "ScheduleExceptions").Save(area).Error
 }
 
 func (r *parkingAreaRepository) Delete(id uint) error {
@@ -55,22 +62,31 @@ func (r *parkingAreaRepository) List(limit, offset int) ([]entities.ParkingArea,
 		return nil, 0, err
 	}
 
-	err := query.Preload("Jukirs").Limit(limit).Offset(offset).Find(&areas).Error
+	err := query.Preload("Jukirs").Preload("VehicleRates").Limit(limit).Offset(offset).Find(&areas).Error
 	return areas, count, err
 }
 
+// GetNearbyAreas narrows the search to the geohash cells covering the radius, which the prefix
+// index on geohash answers without a scan, then trims the cells' corners with a bounding box.
+// Exact distances are left to the caller.
 func (r *parkingAreaRepository) GetNearbyAreas(lat, lng, radius float64) ([]entities.ParkingArea, error) {
 	var areas []entities.ParkingArea
 
-	// Using Haversine formula for distance calculation
-	// For simplicity, we'll use a bounding box approach
-	// In production, consider using PostGIS for better performance
+	cells := geohash.CoveringCells(lat, lng, radius)
+	conditions := make([]string, len(cells))
+	args := make([]interface{}, len(cells))
+	for i, cell := range cells {
+		conditions[i] = "geohash LIKE ?"
+		args[i] = cell + "%"
+	}
+
 	latRange := radius / 111.0                               // Approximate km per degree latitude
 	lngRange := radius / (111.0 * math.Cos(lat*math.Pi/180)) // Adjust for longitude
 
-	err := r.db.Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ? AND status = ?",
-		lat-latRange, lat+latRange, lng-lngRange, lng+lngRange, entities.AreaStatusActive).
-		Preload("Jukirs").
+	err := r.db.Where("("+strings.Join(conditions, " OR ")+")", args...).
+		Where("latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ? AND status = ?",
+			lat-latRange, lat+latRange, lng-lngRange, lng+lngRange, entities.AreaStatusActive).
+		Preload("Jukirs").Preload("VehicleRates").
 		Find(&areas).Error
 
 	return areas, err
@@ -78,6 +94,44 @@ func (r *parkingAreaRepository) GetNearbyAreas(lat, lng, radius float64) ([]enti
 
 func (r *parkingAreaRepository) GetActiveAreas() ([]entities.ParkingArea, error) {
 	var areas []entities.ParkingArea
-	err := r.db.Where("status = ?", entities.AreaStatusActive).Preload("Jukirs").Find(&areas).Error
+	err := r.db.Where("status = ?", entities.AreaStatusActive).Preload("Jukirs").Preload("VehicleRates").Find(&areas).Error
 	return areas, err
 }
+
+// ReplaceVehicleRates swaps the area's registry vehicle rates for the given set in one transaction
+func (r *parkingAreaRepository) ReplaceVehicleRates(areaID uint, rates []entities.AreaVehicleRate) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("area_id = ?", areaID).Delete(&entities.AreaVehicleRate{}).Error; err != nil {
+			return err
+		}
+		if len(rates) == 0 {
+			return nil
+		}
+		for i := range rates {
+			rates[i].AreaID = areaID
+		}
+		return tx.Create(&rates).Error
+	})
+}
+
+// BackfillGeohashes fills in the geohash of areas saved before it was indexed and returns how
+// many were updated
+func (r *parkingAreaRepository) BackfillGeohashes() (int, error) {
+	var areas []entities.ParkingArea
+	if err := r.db.Select("id", "latitude", "longitude").Where("geohash IS NULL OR geohash = ''").Find(&areas).Error; err != nil {
+		return 0, err
+	}
+
+	for _, area := range areas {
+		hash := geohash.Encode(area.Latitude, area.Longitude, geohash.MaxPrecision)
+		if err := r.db.Model(&entities.ParkingArea{}).Where("id = ?", area.ID).Update("geohash", hash).Error; err != nil {
+			return 0, err
+		}
+	}
+	return len(areas), nil
+}
+
+// UpdateStatusOperasional sets only the operational status, leaving the rest of the area as is
+func (r *parkingAreaRepository) UpdateStatusOperasional(areaID uint, status string) error {
+	return r.db.Model(&entities.ParkingArea{}).Where("id = ?", areaID).Update("status_operasional", status).Error
+}
@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"be-parkir/internal/domain/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ParkingPassRepository interface {
+	CreateProduct(product *entities.PassProduct) error
+	GetProductByID(id uint) (*entities.PassProduct, error)
+	UpdateProduct(product *entities.PassProduct) error
+	ListProducts(areaID *uint, regional *string, limit, offset int) ([]entities.PassProduct, int64, error)
+
+	Create(pass *entities.ParkingPass) error
+	GetByID(id uint) (*entities.ParkingPass, error)
+	Update(pass *entities.ParkingPass) error
+	List(status *entities.PassStatus, platNomor string, productID *uint, limit, offset int) ([]entities.ParkingPass, int64, error)
+	GetCurrentByPlateAndProduct(platNomor string, productID uint, at time.Time) (*entities.ParkingPass, error)
+	GetValidForCheckin(platNomor string, vehicleType entities.VehicleType, area entities.ParkingArea, at time.Time) (*entities.ParkingPass, error)
+
+	CreatePurchase(purchase *entities.PassPurchase) error
+	GetSalesSummary(startDate, endDate time.Time, area *entities.ParkingArea) (*entities.PassSalesSummary, error)
+}
+
+type parkingPassRepository struct {
+	db *gorm.DB
+}
+
+func NewParkingPassRepository(db *gorm.DB) ParkingPassRepository {
+	return &parkingPassRepository{db: db}
+}
+
+func (r *parkingPassRepository) CreateProduct(product *entities.PassProduct) error {
+	return r.db.Omit("Area").Create(product).Error
+}
+
+func (r *parkingPassRepository) GetProductByID(id uint) (*entities.PassProduct, error) {
+	var product entities.PassProduct
+	err := r.db.Preload("Area").First(&product, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *parkingPassRepository) UpdateProduct(product *entities.PassProduct) error {
+	return r.db.Omit("Area").Save(product).Error
+}
+
+func (r *parkingPassRepository) ListProducts(areaID *uint, regional *string, limit, offset int) ([]entities.PassProduct, int64, error) {
+	var products []entities.PassProduct
+	var count int64
+
+	query := r.db.Model(&entities.PassProduct{})
+	if areaID != nil {
+		query = query.Where("area_id = ?", *areaID)
+	}
+	if regional != nil {
+		query = query.Where("regional = ?", *regional)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Area").
+		Order("created_at DESC").
+		Limit(limit).Offset(offset).Find(&products).Error
+	return products, count, err
+}
+
+func (r *parkingPassRepository) Create(pass *entities.ParkingPass) error {
+	return r.db.Omit("Product", "Purchases").Create(pass).Error
+}
+
+func (r *parkingPassRepository) GetByID(id uint) (*entities.ParkingPass, error) {
+	var pass entities.ParkingPass
+	err := r.db.Preload("Product.Area").
+		Preload("Purchases", func(db *gorm.DB) *gorm.DB { return db.Order("created_at ASC") }).
+		First(&pass, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pass, nil
+}
+
+func (r *parkingPassRepository) Update(pass *entities.ParkingPass) error {
+	return r.db.Omit("Product", "Purchases").Save(pass).Error
+}
+
+func (r *parkingPassRepository) List(status *entities.PassStatus, platNomor string, productID *uint, limit, offset int) ([]entities.ParkingPass, int64, error) {
+	var passes []entities.ParkingPass
+	var count int64
+
+	query := r.db.Model(&entities.ParkingPass{})
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+	if platNomor != "" {
+		query = query.Where("plat_nomor LIKE ?", "%"+platNomor+"%")
+	}
+	if productID != nil {
+		query = query.Where("product_id = ?", *productID)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Product").
+		Order("created_at DESC").
+		Limit(limit).Offset(offset).Find(&passes).Error
+	return passes, count, err
+}
+
+// GetCurrentByPlateAndProduct returns the plate's pass for the product that has not expired yet, if any
+func (r *parkingPassRepository) GetCurrentByPlateAndProduct(platNomor string, productID uint, at time.Time) (*entities.ParkingPass, error) {
+	var pass entities.ParkingPass
+	err := r.db.Where("plat_nomor = ? AND product_id = ? AND valid_until > ?", platNomor, productID, at).
+		Order("valid_until DESC").
+		First(&pass).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pass, nil
+}
+
+// GetValidForCheckin returns an active pass covering the plate, vehicle type and area at the given
+// time. Passes sold for the area itself win over passes sold for its region.
+func (r *parkingPassRepository) GetValidForCheckin(platNomor string, vehicleType entities.VehicleType, area entities.ParkingArea, at time.Time) (*entities.ParkingPass, error) {
+	var pass entities.ParkingPass
+	err := r.db.
+		Joins("JOIN pass_products ON pass_products.id = parking_passes.product_id").
+		Where("parking_passes.plat_nomor = ? AND parking_passes.vehicle_type = ? AND parking_passes.status = ?", platNomor, vehicleType, entities.PassStatusActive).
+		Where("parking_passes.valid_from <= ? AND parking_passes.valid_until > ?", at, at).
+		Where("pass_products.area_id = ? OR (pass_products.area_id IS NULL AND pass_products.regional = ?)", area.ID, area.Regional).
+		Order("pass_products.area_id IS NULL, parking_passes.valid_until DESC").
+		First(&pass).Error
+	if err != nil {
+		return nil, err
+	}
+	return &pass, nil
+}
+
+func (r *parkingPassRepository) CreatePurchase(purchase *entities.PassPurchase) error {
+	return r.db.Create(purchase).Error
+}
+
+// GetSalesSummary totals pass purchases and renewals recorded in the date range. When an area is
+// given only products sold for that area or its region are counted.
+func (r *parkingPassRepository) GetSalesSummary(startDate, endDate time.Time, area *entities.ParkingArea) (*entities.PassSalesSummary, error) {
+	var rows []struct {
+		Kind   entities.PassPurchaseKind
+		Count  int64
+		Amount float64
+	}
+
+	query := r.db.Model(&entities.PassPurchase{}).
+		Joins("JOIN pass_products ON pass_products.id = pass_purchases.product_id").
+		Where("pass_purchases.created_at BETWEEN ? AND ?", startDate, endDate)
+	if area != nil {
+		query = query.Where("pass_products.area_id = ? OR (pass_products.area_id IS NULL AND pass_products.regional = ?)", area.ID, area.Regional)
+	}
+
+	err := query.Select("pass_purchases.kind AS kind, COUNT(*) AS count, COALESCE(SUM(pass_purchases.amount), 0) AS amount").
+		Group("pass_purchases.kind").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &entities.PassSalesSummary{}
+	for _, row := range rows {
+		switch row.Kind {
+		case entities.PassPurchaseKindPurchase:
+			summary.Purchases = row.Count
+			summary.PurchaseAmount = row.Amount
+		case entities.PassPurchaseKindRenewal:
+			summary.Renewals = row.Count
+			summary.RenewalAmount = row.Amount
+		}
+		summary.TotalAmount += row.Amount
+	}
+	return summary, nil
+}
@@ -2,11 +2,23 @@ package repository
 
 import (
 	"be-parkir/internal/domain/entities"
+	"errors"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// ErrVersionConflict is returned by compare-and-swap updates when the row was changed
+// (or removed) after it was read
+var ErrVersionConflict = errors.New("record was modified by another request")
+
+// parkingPaymentOnly keeps the has-one Payment relation on the parking fee line, since
+// sessions can also carry extra lines such as a lost ticket penalty
+func parkingPaymentOnly(db *gorm.DB) *gorm.DB {
+	return db.Where("kind = ?", entities.PaymentKindParking)
+}
+
 type ParkingSessionRepository interface {
 	Create(session *entities.ParkingSession) error
 	GetByID(id uint) (*entities.ParkingSession, error)
@@ -14,13 +26,17 @@ type ParkingSessionRepository interface {
 	GetActiveByQRToken(qrToken string) (*entities.ParkingSession, error)
 	Update(session *entities.ParkingSession) error
 	Delete(id uint) error
-	GetHistoryByPlatNomor(platNomor string, limit, offset int) ([]entities.ParkingSession, int64, error)
 	GetJukirActiveSessions(jukirID uint) ([]entities.ParkingSession, error)
 	GetPendingPayments(jukirID uint) ([]entities.ParkingSession, error)
 	GetSessionsByArea(areaID uint, startDate, endDate time.Time) ([]entities.ParkingSession, error)
 	GetSessionsByJukir(jukirID uint, startDate, endDate time.Time) ([]entities.ParkingSession, error)
 	GetAllSessions(limit, offset int, filters map[string]interface{}) ([]entities.ParkingSession, int64, error)
 	GetSessionsForActivityLog(jukirID *uint, areaID *uint, startDate, endDate time.Time) ([]entities.ParkingSession, error)
+	SearchActiveByPlate(areaID uint, plateQuery string, vehicleType entities.VehicleType, limit int) ([]entities.ParkingSession, error)
+	GetDistinctPlatNomors() ([]string, error)
+	RenamePlatNomor(from, to string) (int64, error)
+	AssignUser(sessionID, userID uint) (bool, error)
+	ListByUser(userID uint, filter entities.CustomerSessionFilter, limit, offset int) ([]entities.ParkingSession, int64, error)
 }
 
 type parkingSessionRepository struct {
@@ -37,7 +53,7 @@ func (r *parkingSessionRepository) Create(session *entities.ParkingSession) erro
 
 func (r *parkingSessionRepository) GetByID(id uint) (*entities.ParkingSession, error) {
 	var session entities.ParkingSession
-	err := r.db.Preload("Jukir").Preload("Area").Preload("Payment").First(&session, id).Error
+	err := r.db.Preload("Jukir").Preload("Area.VehicleRates").Preload("Payment", parkingPaymentOnly).First(&session, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -46,7 +62,7 @@ func (r *parkingSessionRepository) GetByID(id uint) (*entities.ParkingSession, e
 
 func (r *parkingSessionRepository) GetActiveByPlatNomor(platNomor string) (*entities.ParkingSession, error) {
 	var session entities.ParkingSession
-	err := r.db.Preload("Jukir").Preload("Area").Preload("Payment").
+	err := r.db.Preload("Jukir").Preload("Area.VehicleRates").Preload("Payment", parkingPaymentOnly).
 		Where("plat_nomor = ? AND session_status = ?", platNomor, entities.SessionStatusActive).
 		First(&session).Error
 	if err != nil {
@@ -55,11 +71,13 @@ func (r *parkingSessionRepository) GetActiveByPlatNomor(platNomor string) (*enti
 	return &session, nil
 }
 
+// GetActiveByQRToken matches the jukir's current token or, during its grace period, the one it replaced
 func (r *parkingSessionRepository) GetActiveByQRToken(qrToken string) (*entities.ParkingSession, error) {
 	var session entities.ParkingSession
-	err := r.db.Preload("Jukir").Preload("Area").Preload("Payment").
+	err := r.db.Preload("Jukir").Preload("Area.VehicleRates").Preload("Payment", parkingPaymentOnly).
 		Joins("JOIN jukirs ON parking_sessions.jukir_id = jukirs.id").
-		Where("jukirs.qr_token = ? AND parking_sessions.session_status = ?", qrToken, entities.SessionStatusActive).
+		Where("jukirs.qr_token = ? OR (jukirs.previous_qr_token = ? AND jukirs.previous_qr_token_expires_at > ?)", qrToken, qrToken, time.Now()).
+		Where("parking_sessions.session_status = ?", entities.SessionStatusActive).
 		First(&session).Error
 	if err != nil {
 		return nil, err
@@ -67,44 +85,42 @@ func (r *parkingSessionRepository) GetActiveByQRToken(qrToken string) (*entities
 	return &session, nil
 }
 
+// Update only applies when the session still has the version it was read with, so two
+// concurrent checkouts cannot both succeed. The loser gets ErrVersionConflict.
 func (r *parkingSessionRepository) Update(session *entities.ParkingSession) error {
 	// Use Where clause with ID to be explicit and avoid updating relations
 	// Only update the fields that are actually changed during checkout
-	return r.db.Model(&entities.ParkingSession{}).
-		Where("id = ?", session.ID).
+	result := r.db.Model(&entities.ParkingSession{}).
+		Where("id = ? AND version = ?", session.ID, session.Version).
 		Updates(map[string]interface{}{
 			"checkout_time":  session.CheckoutTime,
 			"duration":       session.Duration,
 			"total_cost":     session.TotalCost,
 			"session_status": session.SessionStatus,
 			"payment_status": session.PaymentStatus,
-		}).Error
+			"auto_closed":    session.AutoClosed,
+			"lost_ticket":    session.LostTicket,
+			"version":        gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	session.Version++
+	return nil
 }
 
 func (r *parkingSessionRepository) Delete(id uint) error {
 	return r.db.Delete(&entities.ParkingSession{}, id).Error
 }
 
-func (r *parkingSessionRepository) GetHistoryByPlatNomor(platNomor string, limit, offset int) ([]entities.ParkingSession, int64, error) {
-	var sessions []entities.ParkingSession
-	var count int64
-
-	query := r.db.Model(&entities.ParkingSession{}).Where("plat_nomor = ?", platNomor)
-	if err := query.Count(&count).Error; err != nil {
-		return nil, 0, err
-	}
-
-	err := query.Preload("Jukir").Preload("Area").Preload("Payment").
-		Order("created_at DESC").
-		Limit(limit).Offset(offset).Find(&sessions).Error
-	return sessions, count, err
-}
-
 func (r *parkingSessionRepository) GetJukirActiveSessions(jukirID uint) ([]entities.ParkingSession, error) {
 	var sessions []entities.ParkingSession
 	// Get all active sessions for this jukir (includes both manual and QR input)
 	// Filter by jukir_id only, regardless of is_manual_record flag
-	err := r.db.Preload("Jukir").Preload("Area").Preload("Payment").
+	err := r.db.Preload("Jukir").Preload("Area.VehicleRates").Preload("Payment", parkingPaymentOnly).
 		Where("jukir_id = ? AND session_status = ?", jukirID, entities.SessionStatusActive).
 		Find(&sessions).Error
 	return sessions, err
@@ -112,16 +128,18 @@ func (r *parkingSessionRepository) GetJukirActiveSessions(jukirID uint) ([]entit
 
 func (r *parkingSessionRepository) GetPendingPayments(jukirID uint) ([]entities.ParkingSession, error) {
 	var sessions []entities.ParkingSession
-	err := r.db.Preload("Jukir").Preload("Area").Preload("Payment").
+	err := r.db.Preload("Jukir").Preload("Area.VehicleRates").Preload("Payment", parkingPaymentOnly).
 		Where("jukir_id = ? AND session_status = ?", jukirID, entities.SessionStatusPendingPayment).
 		Find(&sessions).Error
 	return sessions, err
 }
 
+// GetSessionsByArea feeds the revenue and activity reports, so voided (cancelled) sessions are left out
 func (r *parkingSessionRepository) GetSessionsByArea(areaID uint, startDate, endDate time.Time) ([]entities.ParkingSession, error) {
 	var sessions []entities.ParkingSession
-	err := r.db.Preload("Jukir").Preload("Area").Preload("Payment").
+	err := r.db.Preload("Jukir").Preload("Area.VehicleRates").Preload("Payment", parkingPaymentOnly).
 		Where("area_id = ? AND checkin_time >= ? AND checkin_time < ?", areaID, startDate, endDate).
+		Where("session_status <> ?", entities.SessionStatusCancelled).
 		Order("checkin_time ASC").
 		Find(&sessions).Error
 	return sessions, err
@@ -132,8 +150,10 @@ func (r *parkingSessionRepository) GetSessionsByJukir(jukirID uint, startDate, e
 	// Get all sessions for this jukir (both manual and QR input)
 	// Filter by jukir_id regardless of is_manual_record flag
 	// Use < endDate (not <=) to exclude the next day's sessions
-	err := r.db.Preload("Jukir").Preload("Area").Preload("Payment").
+	// Voided (cancelled) sessions are left out of the reports
+	err := r.db.Preload("Jukir").Preload("Area.VehicleRates").Preload("Payment", parkingPaymentOnly).
 		Where("jukir_id = ? AND checkin_time >= ? AND checkin_time < ?", jukirID, startDate, endDate).
+		Where("session_status <> ?", entities.SessionStatusCancelled).
 		Order("checkin_time ASC").
 		Find(&sessions).Error
 	return sessions, err
@@ -156,7 +176,7 @@ func (r *parkingSessionRepository) GetAllSessions(limit, offset int, filters map
 		return nil, 0, err
 	}
 
-	err := query.Preload("Jukir").Preload("Area").Preload("Payment").
+	err := query.Preload("Jukir").Preload("Area.VehicleRates").Preload("Payment", parkingPaymentOnly).
 		Order("created_at DESC").
 		Limit(limit).Offset(offset).Find(&sessions).Error
 	return sessions, count, err
@@ -165,7 +185,7 @@ func (r *parkingSessionRepository) GetAllSessions(limit, offset int, filters map
 func (r *parkingSessionRepository) GetSessionsForActivityLog(jukirID *uint, areaID *uint, startDate, endDate time.Time) ([]entities.ParkingSession, error) {
 	var sessions []entities.ParkingSession
 
-	query := r.db.Preload("Jukir").Preload("Jukir.User").Preload("Area").Preload("Payment")
+	query := r.db.Preload("Jukir").Preload("Jukir.User").Preload("Area.VehicleRates").Preload("Payment", parkingPaymentOnly).Preload("Attachments")
 
 	if jukirID != nil {
 		query = query.Where("jukir_id = ?", *jukirID)
@@ -183,3 +203,85 @@ func (r *parkingSessionRepository) GetSessionsForActivityLog(jukirID *uint, area
 	err := query.Order("checkin_time ASC").Find(&sessions).Error
 	return sessions, err
 }
+
+// SearchActiveByPlate finds active sessions in an area whose plate contains plateQuery, ignoring
+// case and spaces. An empty query also matches sessions checked in without a plate.
+func (r *parkingSessionRepository) SearchActiveByPlate(areaID uint, plateQuery string, vehicleType entities.VehicleType, limit int) ([]entities.ParkingSession, error) {
+	var sessions []entities.ParkingSession
+	query := r.db.Where("area_id = ? AND vehicle_type = ? AND session_status = ?", areaID, vehicleType, entities.SessionStatusActive)
+
+	plateQuery = strings.ToUpper(strings.ReplaceAll(plateQuery, " ", ""))
+	if plateQuery != "" {
+		query = query.Where("UPPER(REPLACE(plat_nomor, ' ', '')) LIKE ?", "%"+plateQuery+"%")
+	}
+
+	err := query.Order("checkin_time ASC").Limit(limit).Find(&sessions).Error
+	return sessions, err
+}
+
+// GetDistinctPlatNomors returns every plate value stored on a session, as typed
+func (r *parkingSessionRepository) GetDistinctPlatNomors() ([]string, error) {
+	var plates []string
+	err := r.db.Model(&entities.ParkingSession{}).
+		Where("plat_nomor IS NOT NULL AND plat_nomor <> ''").
+		Distinct().Order("plat_nomor").
+		Pluck("plat_nomor", &plates).Error
+	return plates, err
+}
+
+// RenamePlatNomor rewrites one stored plate value on every session that has it and returns how
+// many sessions changed. The version is bumped so in-flight checkouts re-read the session.
+func (r *parkingSessionRepository) RenamePlatNomor(from, to string) (int64, error) {
+	result := r.db.Model(&entities.ParkingSession{}).
+		Where("plat_nomor = ?", from).
+		Updates(map[string]interface{}{
+			"plat_nomor": to,
+			"version":    gorm.Expr("version + 1"),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// AssignUser links the session to a customer account unless it already belongs to one and
+// reports whether it did. The version is left alone: the link does not touch anything a
+// concurrent checkout writes.
+func (r *parkingSessionRepository) AssignUser(sessionID, userID uint) (bool, error) {
+	result := r.db.Model(&entities.ParkingSession{}).
+		Where("id = ? AND user_id IS NULL", sessionID).
+		Update("user_id", userID)
+	return result.RowsAffected > 0, result.Error
+}
+
+// ListByUser returns a customer's sessions, newest first
+func (r *parkingSessionRepository) ListByUser(userID uint, filter entities.CustomerSessionFilter, limit, offset int) ([]entities.ParkingSession, int64, error) {
+	var sessions []entities.ParkingSession
+	var count int64
+
+	query := r.db.Model(&entities.ParkingSession{}).Where("user_id = ?", userID)
+	if filter.SessionStatus != nil {
+		query = query.Where("session_status = ?", *filter.SessionStatus)
+	}
+	if filter.AreaID != nil {
+		query = query.Where("area_id = ?", *filter.AreaID)
+	}
+	if filter.VehicleType != nil {
+		query = query.Where("vehicle_type = ?", *filter.VehicleType)
+	}
+	if filter.PlatNomor != nil {
+		query = query.Where("plat_nomor = ?", *filter.PlatNomor)
+	}
+	if filter.StartDate != nil {
+		query = query.Where("checkin_time >= ?", *filter.StartDate)
+	}
+	if filter.EndDate != nil {
+		query = query.Where("checkin_time <= ?", *filter.EndDate)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Jukir").Preload("Area.VehicleRates").Preload("Payment", parkingPaymentOnly).
+		Order("checkin_time DESC").
+		Limit(limit).Offset(offset).Find(&sessions).Error
+	return sessions, count, err
+}
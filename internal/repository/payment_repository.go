@@ -11,12 +11,19 @@ type PaymentRepository interface {
 	Create(payment *entities.Payment) error
 	GetByID(id uint) (*entities.Payment, error)
 	GetBySessionID(sessionID uint) (*entities.Payment, error)
+	ListBySessionID(sessionID uint) ([]entities.Payment, error)
+	GetByGatewayChargeID(chargeID string) (*entities.Payment, error)
 	Update(payment *entities.Payment) error
 	Delete(id uint) error
 	GetJukirDailyRevenue(jukirID uint, date time.Time) (float64, error)
 	GetJukirPendingPayments(jukirID uint) ([]entities.Payment, error)
 	GetDailyReport(date time.Time) (*entities.DailyReportResponse, error)
 	GetRevenueByDateRange(startDate, endDate time.Time) (float64, error)
+	GetPaidSessionsWithoutPayment() ([]entities.ParkingSession, error)
+	GetPaymentsWithoutSession() ([]entities.Payment, error)
+	GetCompletedSessionAmountMismatches() ([]entities.Payment, error)
+	GetByKindForSessions(kind entities.PaymentKind, sessionIDs []uint) ([]entities.Payment, error)
+	GetJukirCashPayments(jukirIDs []uint, from, to time.Time) ([]entities.Payment, error)
 }
 
 type paymentRepository struct {
@@ -40,17 +47,56 @@ func (r *paymentRepository) GetByID(id uint) (*entities.Payment, error) {
 	return &payment, nil
 }
 
+func (r *paymentRepository) GetByGatewayChargeID(chargeID string) (*entities.Payment, error) {
+	var payment entities.Payment
+	err := r.db.Where("gateway_charge_id = ?", chargeID).First(&payment).Error
+	if err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
 func (r *paymentRepository) GetBySessionID(sessionID uint) (*entities.Payment, error) {
 	var payment entities.Payment
-	err := r.db.Preload("Session").Preload("Jukir").Where("session_id = ?", sessionID).First(&payment).Error
+	err := r.db.Preload("Session").Preload("Jukir").Where("session_id = ? AND kind = ?", sessionID, entities.PaymentKindParking).First(&payment).Error
 	if err != nil {
 		return nil, err
 	}
 	return &payment, nil
 }
 
+// ListBySessionID returns every payment line of a session, oldest first
+func (r *paymentRepository) ListBySessionID(sessionID uint) ([]entities.Payment, error) {
+	payments := make([]entities.Payment, 0)
+	err := r.db.Where("session_id = ?", sessionID).Order("id ASC").Find(&payments).Error
+	return payments, err
+}
+
+// Update is a compare-and-swap on the version column, like ParkingSessionRepository.Update
 func (r *paymentRepository) Update(payment *entities.Payment) error {
-	return r.db.Save(payment).Error
+	result := r.db.Model(&entities.Payment{}).
+		Where("id = ? AND version = ?", payment.ID, payment.Version).
+		Updates(map[string]interface{}{
+			"amount":            payment.Amount,
+			"payment_method":    payment.PaymentMethod,
+			"confirmed_by":      payment.ConfirmedBy,
+			"confirmed_at":      payment.ConfirmedAt,
+			"status":            payment.Status,
+			"gateway_provider":  payment.GatewayProvider,
+			"gateway_charge_id": payment.GatewayChargeID,
+			"charge_amount":     payment.ChargeAmount,
+			"qr_string":         payment.QRString,
+			"charge_expires_at": payment.ChargeExpiresAt,
+			"version":           gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrVersionConflict
+	}
+	payment.Version++
+	return nil
 }
 
 func (r *paymentRepository) Delete(id uint) error {
@@ -131,3 +177,68 @@ func (r *paymentRepository) GetRevenueByDateRange(startDate, endDate time.Time)
 		Scan(&total).Error
 	return total, err
 }
+
+// GetPaidSessionsWithoutPayment returns sessions marked paid that have no payment row
+func (r *paymentRepository) GetPaidSessionsWithoutPayment() ([]entities.ParkingSession, error) {
+	var sessions []entities.ParkingSession
+	err := r.db.
+		Where("payment_status = ?", entities.PaymentStatusPaid).
+		Where("NOT EXISTS (SELECT 1 FROM payments WHERE payments.session_id = parking_sessions.id AND payments.kind = ? AND payments.deleted_at IS NULL)", entities.PaymentKindParking).
+		Order("id ASC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// GetPaymentsWithoutSession returns payment rows whose session no longer exists
+func (r *paymentRepository) GetPaymentsWithoutSession() ([]entities.Payment, error) {
+	var payments []entities.Payment
+	err := r.db.
+		Where("NOT EXISTS (SELECT 1 FROM parking_sessions WHERE parking_sessions.id = payments.session_id AND parking_sessions.deleted_at IS NULL)").
+		Order("id ASC").
+		Find(&payments).Error
+	return payments, err
+}
+
+// GetCompletedSessionAmountMismatches returns the parking payment of completed sessions whose parking fee
+// lines (the check-in line plus any balance lines) do not add up to the session total
+func (r *paymentRepository) GetCompletedSessionAmountMismatches() ([]entities.Payment, error) {
+	var payments []entities.Payment
+	err := r.db.Preload("Session").
+		Joins("JOIN parking_sessions ON payments.session_id = parking_sessions.id AND parking_sessions.deleted_at IS NULL").
+		Where("parking_sessions.session_status = ? AND parking_sessions.total_cost IS NOT NULL", entities.SessionStatusCompleted).
+		Where("parking_sessions.total_cost <> (SELECT COALESCE(SUM(lines.amount), 0) FROM payments lines WHERE lines.session_id = parking_sessions.id AND lines.kind IN ? AND lines.status <> ? AND lines.deleted_at IS NULL)",
+			[]entities.PaymentKind{entities.PaymentKindParking, entities.PaymentKindParkingBalance}, entities.PaymentStatusFailed).
+		// Lost ticket sessions split their total over a parking line and a penalty line
+		Where("payments.kind = ? AND NOT parking_sessions.lost_ticket", entities.PaymentKindParking).
+		Order("payments.id ASC").
+		Find(&payments).Error
+	return payments, err
+}
+
+// GetByKindForSessions returns the payment lines of one kind, e.g. lost ticket penalties, for the given sessions
+func (r *paymentRepository) GetByKindForSessions(kind entities.PaymentKind, sessionIDs []uint) ([]entities.Payment, error) {
+	var payments []entities.Payment
+	if len(sessionIDs) == 0 {
+		return payments, nil
+	}
+	err := r.db.Where("kind = ? AND session_id IN ?", kind, sessionIDs).
+		Order("id ASC").
+		Find(&payments).Error
+	return payments, err
+}
+
+// GetJukirCashPayments returns the paid cash payments jukirs confirmed from (inclusive) to
+// (exclusive), for the given jukirs or every jukir when jukirIDs is nil. This is the cash the
+// jukirs hold and have to deposit; QRIS payments go to the gateway and are left out, and so is
+// the unpaid balance of sessions closed by the overstay scheduler.
+func (r *paymentRepository) GetJukirCashPayments(jukirIDs []uint, from, to time.Time) ([]entities.Payment, error) {
+	payments := make([]entities.Payment, 0)
+	query := r.db.Select("id", "session_id", "kind", "amount", "payment_method", "confirmed_by", "confirmed_at", "status").
+		Where("payment_method = ? AND status = ? AND confirmed_by IS NOT NULL", entities.PaymentMethodCash, entities.PaymentStatusPaid).
+		Where("confirmed_at >= ? AND confirmed_at < ?", from, to)
+	if jukirIDs != nil {
+		query = query.Where("confirmed_by IN ?", jukirIDs)
+	}
+	err := query.Order("confirmed_at ASC").Find(&payments).Error
+	return payments, err
+}
@@ -29,9 +29,35 @@ func NewPostgresDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
 		&entities.ParkingArea{},
 		&entities.ParkingSession{},
 		&entities.Payment{},
+		&entities.TariffPlan{},
+		&entities.TariffSchedule{},
+		&entities.Holiday{},
+		&entities.VehicleTypeConfig{},
+		&entities.AreaVehicleRate{},
+		&entities.SyncRecord{},
+		&entities.SessionVoidRequest{},
+		&entities.OverstayEvent{},
+		&entities.PassProduct{},
+		&entities.ParkingPass{},
+		&entities.PassPurchase{},
+		&entities.Reservation{},
+		&entities.SessionAttachment{},
+		&entities.SessionReceipt{},
+		&entities.AreaOperatingHours{},
+		&entities.AreaScheduleException{},
+		&entities.CustomerVehicle{},
+		&entities.CashDeposit{},
 	); err != nil {
 		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
+	// Seed the vehicle type registry with the built-in types
+	for _, vehicleType := range entities.DefaultVehicleTypes {
+		vt := vehicleType
+		if err := db.Where("code = ?", vt.Code).FirstOrCreate(&vt).Error; err != nil {
+			return nil, fmt.Errorf("failed to seed vehicle types: %w", err)
+		}
+	}
+
 	return db, nil
 }
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"be-parkir/internal/domain/entities"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrReservationClosed is returned when a reservation left the reserved state before it could be
+// consumed or released, e.g. it expired while the customer was checking in
+var ErrReservationClosed = errors.New("reservation is no longer active")
+
+type ReservationRepository interface {
+	Create(reservation *entities.Reservation) error
+	GetByID(id uint) (*entities.Reservation, error)
+	ListByUser(userID uint, status *entities.ReservationStatus, limit, offset int) ([]entities.Reservation, int64, error)
+	GetReservedByUserAndPlate(userID uint, platNomor string) (*entities.Reservation, error)
+	FindForCheckin(areaID uint, vehicleType entities.VehicleType, platNomor string, at time.Time) (*entities.Reservation, error)
+	GetExpired(at time.Time) ([]entities.Reservation, error)
+	Consume(id, sessionID uint, at time.Time) error
+	Close(id uint, status entities.ReservationStatus, at time.Time) error
+}
+
+type reservationRepository struct {
+	db *gorm.DB
+}
+
+func NewReservationRepository(db *gorm.DB) ReservationRepository {
+	return &reservationRepository{db: db}
+}
+
+func (r *reservationRepository) Create(reservation *entities.Reservation) error {
+	return r.db.Omit("Area", "Session").Create(reservation).Error
+}
+
+func (r *reservationRepository) GetByID(id uint) (*entities.Reservation, error) {
+	var reservation entities.Reservation
+	err := r.db.Preload("Area").Preload("Session").First(&reservation, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+func (r *reservationRepository) ListByUser(userID uint, status *entities.ReservationStatus, limit, offset int) ([]entities.Reservation, int64, error) {
+	var reservations []entities.Reservation
+	var count int64
+
+	query := r.db.Model(&entities.Reservation{}).Where("user_id = ?", userID)
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Area").
+		Order("start_time DESC").
+		Limit(limit).Offset(offset).Find(&reservations).Error
+	return reservations, count, err
+}
+
+// GetReservedByUserAndPlate returns the customer's open reservation for the plate, if any
+func (r *reservationRepository) GetReservedByUserAndPlate(userID uint, platNomor string) (*entities.Reservation, error) {
+	var reservation entities.Reservation
+	err := r.db.Where("user_id = ? AND plat_nomor = ? AND status = ?", userID, platNomor, entities.ReservationStatusReserved).
+		First(&reservation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// FindForCheckin returns the open, unexpired reservation matching a check-in
+func (r *reservationRepository) FindForCheckin(areaID uint, vehicleType entities.VehicleType, platNomor string, at time.Time) (*entities.Reservation, error) {
+	var reservation entities.Reservation
+	err := r.db.
+		Where("area_id = ? AND vehicle_type = ? AND plat_nomor = ?", areaID, vehicleType, platNomor).
+		Where("status = ? AND expires_at > ?", entities.ReservationStatusReserved, at).
+		Order("start_time ASC").
+		First(&reservation).Error
+	if err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// GetExpired returns open reservations whose grace period has passed without a check-in
+func (r *reservationRepository) GetExpired(at time.Time) ([]entities.Reservation, error) {
+	var reservations []entities.Reservation
+	err := r.db.Where("status = ? AND expires_at <= ?", entities.ReservationStatusReserved, at).
+		Order("expires_at ASC").
+		Find(&reservations).Error
+	return reservations, err
+}
+
+// Consume links the reservation to the session that used it. It only succeeds while the
+// reservation is still open, so a reservation cannot be used twice or after it expired.
+func (r *reservationRepository) Consume(id, sessionID uint, at time.Time) error {
+	result := r.db.Model(&entities.Reservation{}).
+		Where("id = ? AND status = ?", id, entities.ReservationStatusReserved).
+		Updates(map[string]interface{}{
+			"status":     entities.ReservationStatusConsumed,
+			"session_id": sessionID,
+			"closed_at":  at,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReservationClosed
+	}
+	return nil
+}
+
+// Close moves an open reservation to expired or cancelled
+func (r *reservationRepository) Close(id uint, status entities.ReservationStatus, at time.Time) error {
+	result := r.db.Model(&entities.Reservation{}).
+		Where("id = ? AND status = ?", id, entities.ReservationStatusReserved).
+		Updates(map[string]interface{}{
+			"status":    status,
+			"closed_at": at,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReservationClosed
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"be-parkir/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+type SessionAttachmentRepository interface {
+	Create(attachment *entities.SessionAttachment) error
+	GetBySessionID(sessionID uint) ([]entities.SessionAttachment, error)
+}
+
+type sessionAttachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionAttachmentRepository(db *gorm.DB) SessionAttachmentRepository {
+	return &sessionAttachmentRepository{db: db}
+}
+
+func (r *sessionAttachmentRepository) Create(attachment *entities.SessionAttachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *sessionAttachmentRepository) GetBySessionID(sessionID uint) ([]entities.SessionAttachment, error) {
+	var attachments []entities.SessionAttachment
+	err := r.db.Where("session_id = ?", sessionID).Order("created_at ASC").Find(&attachments).Error
+	return attachments, err
+}
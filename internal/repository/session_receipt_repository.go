@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"be-parkir/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+type SessionReceiptRepository interface {
+	Create(receipt *entities.SessionReceipt) error
+	Update(receipt *entities.SessionReceipt) error
+	GetBySessionID(sessionID uint) (*entities.SessionReceipt, error)
+	GetByCode(code string) (*entities.SessionReceipt, error)
+}
+
+type sessionReceiptRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionReceiptRepository(db *gorm.DB) SessionReceiptRepository {
+	return &sessionReceiptRepository{db: db}
+}
+
+func (r *sessionReceiptRepository) Create(receipt *entities.SessionReceipt) error {
+	return r.db.Create(receipt).Error
+}
+
+func (r *sessionReceiptRepository) Update(receipt *entities.SessionReceipt) error {
+	return r.db.Save(receipt).Error
+}
+
+func (r *sessionReceiptRepository) GetBySessionID(sessionID uint) (*entities.SessionReceipt, error) {
+	var receipt entities.SessionReceipt
+	err := r.db.Where("session_id = ?", sessionID).First(&receipt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+func (r *sessionReceiptRepository) GetByCode(code string) (*entities.SessionReceipt, error) {
+	var receipt entities.SessionReceipt
+	err := r.db.Where("code = ?", code).First(&receipt).Error
+	if err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"be-parkir/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+type SessionVoidRepository interface {
+	Create(request *entities.SessionVoidRequest) error
+	GetBySessionID(sessionID uint) ([]entities.SessionVoidRequest, error)
+	GetPendingBySessionID(sessionID uint) (*entities.SessionVoidRequest, error)
+	List(status *entities.VoidStatus, limit, offset int) ([]entities.SessionVoidRequest, int64, error)
+	Update(request *entities.SessionVoidRequest) error
+}
+
+type sessionVoidRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionVoidRepository(db *gorm.DB) SessionVoidRepository {
+	return &sessionVoidRepository{db: db}
+}
+
+func (r *sessionVoidRepository) Create(request *entities.SessionVoidRequest) error {
+	return r.db.Omit("Session", "Jukir", "Reviewer").Create(request).Error
+}
+
+func (r *sessionVoidRepository) GetBySessionID(sessionID uint) ([]entities.SessionVoidRequest, error) {
+	var requests []entities.SessionVoidRequest
+	err := r.db.Preload("Jukir.User").Preload("Reviewer").
+		Where("session_id = ?", sessionID).
+		Order("created_at DESC").
+		Find(&requests).Error
+	return requests, err
+}
+
+func (r *sessionVoidRepository) GetPendingBySessionID(sessionID uint) (*entities.SessionVoidRequest, error) {
+	var request entities.SessionVoidRequest
+	err := r.db.Where("session_id = ? AND status = ?", sessionID, entities.VoidStatusPending).
+		First(&request).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *sessionVoidRepository) List(status *entities.VoidStatus, limit, offset int) ([]entities.SessionVoidRequest, int64, error) {
+	var requests []entities.SessionVoidRequest
+	var count int64
+
+	query := r.db.Model(&entities.SessionVoidRequest{})
+	if status != nil {
+		query = query.Where("status = ?", *status)
+	}
+
+	if err := query.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Session").Preload("Jukir.User").Preload("Reviewer").
+		Order("created_at DESC").
+		Limit(limit).Offset(offset).Find(&requests).Error
+	return requests, count, err
+}
+
+func (r *sessionVoidRepository) Update(request *entities.SessionVoidRequest) error {
+	return r.db.Omit("Session", "Jukir", "Reviewer").Save(request).Error
+}
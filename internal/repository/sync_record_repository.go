@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"be-parkir/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+type SyncRecordRepository interface {
+	Create(record *entities.SyncRecord) error
+	GetByClientID(clientID string) (*entities.SyncRecord, error)
+}
+
+type syncRecordRepository struct {
+	db *gorm.DB
+}
+
+func NewSyncRecordRepository(db *gorm.DB) SyncRecordRepository {
+	return &syncRecordRepository{db: db}
+}
+
+func (r *syncRecordRepository) Create(record *entities.SyncRecord) error {
+	return r.db.Create(record).Error
+}
+
+func (r *syncRecordRepository) GetByClientID(clientID string) (*entities.SyncRecord, error) {
+	var record entities.SyncRecord
+	err := r.db.Where("client_id = ?", clientID).First(&record).Error
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
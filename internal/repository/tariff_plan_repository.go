@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"be-parkir/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+type TariffPlanRepository interface {
+	Create(plan *entities.TariffPlan) error
+	GetByAreaAndVehicleType(areaID uint, vehicleType entities.VehicleType) (*entities.TariffPlan, error)
+	GetByAreaID(areaID uint) ([]entities.TariffPlan, error)
+	Update(plan *entities.TariffPlan) error
+	Delete(id uint) error
+	GetSchedulesByAreaID(areaID uint) ([]entities.TariffSchedule, error)
+	ReplaceSchedules(areaID uint, schedules []entities.TariffSchedule) error
+}
+
+type tariffPlanRepository struct {
+	db *gorm.DB
+}
+
+func NewTariffPlanRepository(db *gorm.DB) TariffPlanRepository {
+	return &tariffPlanRepository{db: db}
+}
+
+func (r *tariffPlanRepository) Create(plan *entities.TariffPlan) error {
+	return r.db.Create(plan).Error
+}
+
+func (r *tariffPlanRepository) GetByAreaAndVehicleType(areaID uint, vehicleType entities.VehicleType) (*entities.TariffPlan, error) {
+	var plan entities.TariffPlan
+	err := r.db.Where("area_id = ? AND vehicle_type = ?", areaID, vehicleType).First(&plan).Error
+	if err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *tariffPlanRepository) GetByAreaID(areaID uint) ([]entities.TariffPlan, error) {
+	plans := make([]entities.TariffPlan, 0)
+	err := r.db.Where("area_id = ?", areaID).Order("vehicle_type ASC").Find(&plans).Error
+	return plans, err
+}
+
+func (r *tariffPlanRepository) Update(plan *entities.TariffPlan) error {
+	return r.db.Omit("Area").Save(plan).Error
+}
+
+func (r *tariffPlanRepository) Delete(id uint) error {
+	return r.db.Delete(&entities.TariffPlan{}, id).Error
+}
+
+func (r *tariffPlanRepository) GetSchedulesByAreaID(areaID uint) ([]entities.TariffSchedule, error) {
+	schedules := make([]entities.TariffSchedule, 0)
+	err := r.db.Where("area_id = ?", areaID).Order("schedule_type ASC").Find(&schedules).Error
+	return schedules, err
+}
+
+// ReplaceSchedules swaps the area's schedules for the given set in one transaction
+func (r *tariffPlanRepository) ReplaceSchedules(areaID uint, schedules []entities.TariffSchedule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("area_id = ?", areaID).Delete(&entities.TariffSchedule{}).Error; err != nil {
+			return err
+		}
+		if len(schedules) == 0 {
+			return nil
+		}
+		for i := range schedules {
+			schedules[i].AreaID = areaID
+		}
+		return tx.Create(&schedules).Error
+	})
+}
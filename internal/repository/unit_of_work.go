@@ -0,0 +1,44 @@
+package repository
+
+import "gorm.io/gorm"
+
+// TxRepositories are repositories bound to a single database transaction
+type TxRepositories struct {
+	Sessions     ParkingSessionRepository
+	Payments     PaymentRepository
+	VoidRequests SessionVoidRepository
+	Overstays    OverstayRepository
+	Passes       ParkingPassRepository
+	Reservations ReservationRepository
+	Attachments  SessionAttachmentRepository
+	SyncRecords  SyncRecordRepository
+}
+
+// UnitOfWork runs a group of repository calls in one transaction: everything is committed
+// when fn returns nil and rolled back when it returns an error or panics
+type UnitOfWork interface {
+	Do(fn func(repos TxRepositories) error) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+}
+
+func NewUnitOfWork(db *gorm.DB) UnitOfWork {
+	return &unitOfWork{db: db}
+}
+
+func (u *unitOfWork) Do(fn func(repos TxRepositories) error) error {
+	return u.db.Transaction(func(tx *gorm.DB) error {
+		return fn(TxRepositories{
+			Sessions:     NewParkingSessionRepository(tx),
+			Payments:     NewPaymentRepository(tx),
+			VoidRequests: NewSessionVoidRepository(tx),
+			Overstays:    NewOverstayRepository(tx),
+			Passes:       NewParkingPassRepository(tx),
+			Reservations: NewReservationRepository(tx),
+			Attachments:  NewSessionAttachmentRepository(tx),
+			SyncRecords:  NewSyncRecordRepository(tx),
+		})
+	})
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"be-parkir/internal/domain/entities"
+
+	"gorm.io/gorm"
+)
+
+type VehicleTypeRepository interface {
+	Create(vehicleType *entities.VehicleTypeConfig) error
+	GetByCode(code entities.VehicleType) (*entities.VehicleTypeConfig, error)
+	List(activeOnly bool) ([]entities.VehicleTypeConfig, error)
+	Update(vehicleType *entities.VehicleTypeConfig) error
+}
+
+type vehicleTypeRepository struct {
+	db *gorm.DB
+}
+
+func NewVehicleTypeRepository(db *gorm.DB) VehicleTypeRepository {
+	return &vehicleTypeRepository{db: db}
+}
+
+func (r *vehicleTypeRepository) Create(vehicleType *entities.VehicleTypeConfig) error {
+	return r.db.Create(vehicleType).Error
+}
+
+func (r *vehicleTypeRepository) GetByCode(code entities.VehicleType) (*entities.VehicleTypeConfig, error) {
+	var vehicleType entities.VehicleTypeConfig
+	err := r.db.Where("code = ?", code).First(&vehicleType).Error
+	if err != nil {
+		return nil, err
+	}
+	return &vehicleType, nil
+}
+
+func (r *vehicleTypeRepository) List(activeOnly bool) ([]entities.VehicleTypeConfig, error) {
+	vehicleTypes := make([]entities.VehicleTypeConfig, 0)
+	query := r.db.Model(&entities.VehicleTypeConfig{})
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+	err := query.Order("sort_order ASC, id ASC").Find(&vehicleTypes).Error
+	return vehicleTypes, err
+}
+
+func (r *vehicleTypeRepository) Update(vehicleType *entities.VehicleTypeConfig) error {
+	return r.db.Save(vehicleType).Error
+}
@@ -0,0 +1,61 @@
+package scheduler
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Job is a background task run on a fixed interval
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+// Scheduler runs background jobs, each in its own goroutine
+type Scheduler struct {
+	jobs   []Job
+	logger *logrus.Logger
+}
+
+func New(logger *logrus.Logger) *Scheduler {
+	return &Scheduler{logger: logger}
+}
+
+// Add registers a job. Jobs with a zero or negative interval are disabled.
+func (s *Scheduler) Add(job Job) {
+	if job.Interval <= 0 {
+		s.logger.Infof("Scheduler job %s disabled", job.Name)
+		return
+	}
+	s.jobs = append(s.jobs, job)
+}
+
+// Start launches every registered job. A job never overlaps with itself: the next run
+// starts one interval after the previous one finished.
+func (s *Scheduler) Start() {
+	for _, job := range s.jobs {
+		go s.loop(job)
+	}
+}
+
+func (s *Scheduler) loop(job Job) {
+	s.logger.Infof("Scheduler job %s started, every %s", job.Name, job.Interval)
+	for {
+		time.Sleep(job.Interval)
+		s.run(job)
+	}
+}
+
+func (s *Scheduler) run(job Job) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.logger.Errorf("Scheduler job %s panicked: %v", job.Name, r)
+		}
+	}()
+
+	if err := job.Run(); err != nil {
+		s.logger.Errorf("Scheduler job %s failed: %v", job.Name, err)
+	}
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/qrcard"
 	"be-parkir/internal/repository"
 	"bytes"
 	"encoding/csv"
@@ -58,23 +59,58 @@ type AdminUsecase interface {
 	ExportJukirActivityDetailXLSX(jukirID uint, startTime, endTime *time.Time) (*bytes.Buffer, error)
 	GetActivityLogs(jukirID *uint, areaID *uint, startTime, endTime time.Time, limit, offset int) (*entities.ActivityLogResponse, error)
 	ImportAreasAndJukirsFromCSV(reader io.Reader, regional string) (map[string]interface{}, error)
+	GetAreaTariffs(areaID uint) ([]entities.TariffPlan, error)
+	UpsertAreaTariff(areaID uint, req *entities.UpsertTariffPlanRequest) (*entities.TariffPlan, error)
+	DeleteAreaTariff(areaID uint, vehicleType entities.VehicleType) error
+	GetVehicleTypes() ([]entities.VehicleTypeConfig, error)
+	CreateVehicleType(req *entities.CreateVehicleTypeRequest) (*entities.VehicleTypeConfig, error)
+	UpdateVehicleType(code entities.VehicleType, req *entities.UpdateVehicleTypeRequest) (*entities.VehicleTypeConfig, error)
+	GetHolidays(startDate, endDate *time.Time) ([]entities.Holiday, error)
+	CreateHoliday(req *entities.CreateHolidayRequest) (*entities.Holiday, error)
+	DeleteHoliday(id uint) error
+	GetVoidRequests(status *entities.VoidStatus, limit, offset int) ([]entities.SessionVoidRequest, int64, error)
+	GetSessionVoidRequests(sessionID uint) ([]entities.SessionVoidRequest, error)
+	GetSessionDetail(sessionID uint) (*entities.ParkingSession, error)
+	GetJukirQRImage(jukirID uint, format qrcard.Format) ([]byte, error)
+	RotateJukirQRToken(jukirID uint, req *entities.RotateQRTokenRequest) (*entities.JukirQRResponse, error)
+	GetJukirQRSheet(regional string) ([]byte, error)
+	ApproveSessionVoid(sessionID, adminID uint, req *entities.ReviewVoidRequest) (*entities.SessionVoidRequest, error)
+	RejectSessionVoid(sessionID, adminID uint, req *entities.ReviewVoidRequest) (*entities.SessionVoidRequest, error)
 }
 
 type adminUsecase struct {
-	userRepo    repository.UserRepository
-	jukirRepo   repository.JukirRepository
-	areaRepo    repository.ParkingAreaRepository
-	sessionRepo repository.ParkingSessionRepository
-	paymentRepo repository.PaymentRepository
+	userRepo        repository.UserRepository
+	jukirRepo       repository.JukirRepository
+	areaRepo        repository.ParkingAreaRepository
+	sessionRepo     repository.ParkingSessionRepository
+	paymentRepo     repository.PaymentRepository
+	tariffRepo      repository.TariffPlanRepository
+	holidayRepo     repository.HolidayRepository
+	vehicleTypeRepo repository.VehicleTypeRepository
+	occupancyRepo   repository.OccupancyRepository
+	voidRepo        repository.SessionVoidRepository
+	passRepo        repository.ParkingPassRepository
+	attachmentRepo  repository.SessionAttachmentRepository
+	hoursRepo       repository.OperatingHoursRepository
+	uow             repository.UnitOfWork
 }
 
-func NewAdminUsecase(userRepo repository.UserRepository, jukirRepo repository.JukirRepository, areaRepo repository.ParkingAreaRepository, sessionRepo repository.ParkingSessionRepository, paymentRepo repository.PaymentRepository) AdminUsecase {
+func NewAdminUsecase(userRepo repository.UserRepository, jukirRepo repository.JukirRepository, areaRepo repository.ParkingAreaRepository, sessionRepo repository.ParkingSessionRepository, paymentRepo repository.PaymentRepository, tariffRepo repository.TariffPlanRepository, holidayRepo repository.HolidayRepository, vehicleTypeRepo repository.VehicleTypeRepository, occupancyRepo repository.OccupancyRepository, voidRepo repository.SessionVoidRepository, passRepo repository.ParkingPassRepository, attachmentRepo repository.SessionAttachmentRepository, hoursRepo repository.OperatingHoursRepository, uow repository.UnitOfWork) AdminUsecase {
 	return &adminUsecase{
-		userRepo:    userRepo,
-		jukirRepo:   jukirRepo,
-		areaRepo:    areaRepo,
-		sessionRepo: sessionRepo,
-		paymentRepo: paymentRepo,
+		userRepo:        userRepo,
+		jukirRepo:       jukirRepo,
+		areaRepo:        areaRepo,
+		sessionRepo:     sessionRepo,
+		paymentRepo:     paymentRepo,
+		tariffRepo:      tariffRepo,
+		holidayRepo:     holidayRepo,
+		vehicleTypeRepo: vehicleTypeRepo,
+		occupancyRepo:   occupancyRepo,
+		voidRepo:        voidRepo,
+		passRepo:        passRepo,
+		attachmentRepo:  attachmentRepo,
+		hoursRepo:       hoursRepo,
+		uow:             uow,
 	}
 }
 
@@ -128,7 +164,7 @@ func parseDateRange(startDateStr, endDateStr string) (*time.Time, *time.Time, er
 }
 
 // getPeriods returns array of period data based on date range with actual and estimated revenue
-func getPeriods(dateRange string, now time.Time, sessionRepo repository.ParkingSessionRepository, areaRepo repository.ParkingAreaRepository, regional *string) []map[string]interface{} {
+func getPeriods(dateRange string, now time.Time, sessionRepo repository.ParkingSessionRepository, paymentRepo repository.PaymentRepository, areaRepo repository.ParkingAreaRepository, regional *string) []map[string]interface{} {
 	switch dateRange {
 	case "bulan_ini": // Last 7 months
 		periods := make([]map[string]interface{}, 7)
@@ -139,8 +175,8 @@ func getPeriods(dateRange string, now time.Time, sessionRepo repository.ParkingS
 			start := time.Date(period.Year(), period.Month(), 1, 0, 0, 0, 0, now.Location())
 			end := start.AddDate(0, 1, 0)
 
-			actualRevenue := calculateActualRevenue(sessionRepo, areaRepo, start, end, regional)
-			estimatedRevenue := calculateEstimatedRevenue(sessionRepo, areaRepo, start, end, regional)
+			actualRevenue := calculateActualRevenue(sessionRepo, paymentRepo, areaRepo, start, end, regional)
+			estimatedRevenue := calculateEstimatedRevenue(sessionRepo, paymentRepo, areaRepo, start, end, regional)
 
 			periods[i] = map[string]interface{}{
 				"period":            months[period.Month()-1],
@@ -159,8 +195,8 @@ func getPeriods(dateRange string, now time.Time, sessionRepo repository.ParkingS
 			start := time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day(), 0, 0, 0, 0, now.Location())
 			end := start.AddDate(0, 0, 7)
 
-			actualRevenue := calculateActualRevenue(sessionRepo, areaRepo, start, end, regional)
-			estimatedRevenue := calculateEstimatedRevenue(sessionRepo, areaRepo, start, end, regional)
+			actualRevenue := calculateActualRevenue(sessionRepo, paymentRepo, areaRepo, start, end, regional)
+			estimatedRevenue := calculateEstimatedRevenue(sessionRepo, paymentRepo, areaRepo, start, end, regional)
 
 			periods[i] = map[string]interface{}{
 				"period":            fmt.Sprintf("Minggu %d", weeksAgo+1),
@@ -179,8 +215,8 @@ func getPeriods(dateRange string, now time.Time, sessionRepo repository.ParkingS
 			start := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location())
 			end := start.Add(24 * time.Hour)
 
-			actualRevenue := calculateActualRevenue(sessionRepo, areaRepo, start, end, regional)
-			estimatedRevenue := calculateEstimatedRevenue(sessionRepo, areaRepo, start, end, regional)
+			actualRevenue := calculateActualRevenue(sessionRepo, paymentRepo, areaRepo, start, end, regional)
+			estimatedRevenue := calculateEstimatedRevenue(sessionRepo, paymentRepo, areaRepo, start, end, regional)
 
 			periods[i] = map[string]interface{}{
 				"period":            weekdays[day.Weekday()],
@@ -194,10 +230,10 @@ func getPeriods(dateRange string, now time.Time, sessionRepo repository.ParkingS
 }
 
 // calculateEstimatedRevenue calculates estimated revenue from active sessions
-func calculateEstimatedRevenue(sessionRepo repository.ParkingSessionRepository, areaRepo repository.ParkingAreaRepository, start, end time.Time, regional *string) float64 {
+func calculateEstimatedRevenue(sessionRepo repository.ParkingSessionRepository, paymentRepo repository.PaymentRepository, areaRepo repository.ParkingAreaRepository, start, end time.Time, regional *string) float64 {
 	// For simplicity, estimated revenue now mirrors confirmed (actual) revenue.
 	// Both metrics only increase once a payment has been confirmed.
-	return calculateActualRevenue(sessionRepo, areaRepo, start, end, regional)
+	return calculateActualRevenue(sessionRepo, paymentRepo, areaRepo, start, end, regional)
 }
 
 func roundCurrency(value float64) float64 {
@@ -273,29 +309,12 @@ func (u *adminUsecase) GetOverview(vehicleType *string, startTime, endTime *time
 	// Calculate vehicles in and out
 	vehiclesIn := 0
 	vehiclesOut := 0
-	vehiclesInMobil := 0
-	vehiclesOutMobil := 0
-	vehiclesInMotor := 0
-	vehiclesOutMotor := 0
 
 	// If filtered, these will contain only the filtered type
 	for _, session := range sessions {
 		// Count all check-ins (will be filtered count if vehicleType filter is applied)
 		vehiclesIn++
 
-		// Count by vehicle type
-		if session.VehicleType == entities.VehicleTypeMobil {
-			vehiclesInMobil++
-			if session.CheckoutTime != nil {
-				vehiclesOutMobil++
-			}
-		} else if session.VehicleType == entities.VehicleTypeMotor {
-			vehiclesInMotor++
-			if session.CheckoutTime != nil {
-				vehiclesOutMotor++
-			}
-		}
-
 		// Check if session has checkout time (vehicle left)
 		if session.CheckoutTime != nil {
 			vehiclesOut++
@@ -353,25 +372,16 @@ func (u *adminUsecase) GetOverview(vehicleType *string, startTime, endTime *time
 
 	// Get chart data based on date range - using default minggu_ini for chart
 	now := time.Now()
-	chartData := getPeriods("minggu_ini", now, u.sessionRepo, u.areaRepo, nil)
+	chartData := getPeriods("minggu_ini", now, u.sessionRepo, u.paymentRepo, u.areaRepo, nil)
 
 	return map[string]interface{}{
-		"total_users":    len(totalUsers),
-		"total_jukirs":   len(totalJukirs),
-		"total_areas":    len(totalAreas),
-		"today_sessions": len(sessions),
-		"vehicles_in":    vehiclesIn,
-		"vehicles_out":   vehiclesOut,
-		"vehicles_by_type": map[string]interface{}{
-			"mobil": map[string]interface{}{
-				"in":  vehiclesInMobil,
-				"out": vehiclesOutMobil,
-			},
-			"motor": map[string]interface{}{
-				"in":  vehiclesInMotor,
-				"out": vehiclesOutMotor,
-			},
-		},
+		"total_users":       len(totalUsers),
+		"total_jukirs":      len(totalJukirs),
+		"total_areas":       len(totalAreas),
+		"today_sessions":    len(sessions),
+		"vehicles_in":       vehiclesIn,
+		"vehicles_out":      vehiclesOut,
+		"vehicles_by_type":  vehicleFlowByType(sessions, listVehicleTypes(u.vehicleTypeRepo), "in", "out"),
 		"active_sessions":   activeSessions,
 		"pending_payments":  pendingPayments,
 		"today_revenue":     roundCurrency(totalRevenue),
@@ -540,7 +550,11 @@ func (u *adminUsecase) CreateJukir(req *entities.CreateJukirRequest) (*entities.
 	}
 
 	// Generate QR token
-	qrToken := "QR_" + jukirCode + "_" + time.Now().Format("20060102150405")
+	qrToken, err := newQRToken(jukirCode)
+	if err != nil {
+		u.userRepo.Delete(user.ID)
+		return nil, err
+	}
 
 	// Determine status
 	status := entities.JukirStatusPending
@@ -735,44 +749,19 @@ func (u *adminUsecase) GetVehicleStatistics(startTime, endTime *time.Time, vehic
 
 	vehiclesIn := 0
 	vehiclesOut := 0
-	vehiclesInMobil := 0
-	vehiclesOutMobil := 0
-	vehiclesInMotor := 0
-	vehiclesOutMotor := 0
 
 	for _, session := range sessions {
 		vehiclesIn++
 
-		if session.VehicleType == entities.VehicleTypeMobil {
-			vehiclesInMobil++
-			if session.CheckoutTime != nil {
-				vehiclesOutMobil++
-			}
-		} else if session.VehicleType == entities.VehicleTypeMotor {
-			vehiclesInMotor++
-			if session.CheckoutTime != nil {
-				vehiclesOutMotor++
-			}
-		}
-
 		if session.CheckoutTime != nil {
 			vehiclesOut++
 		}
 	}
 
 	return map[string]interface{}{
-		"total_in":  vehiclesIn,
-		"total_out": vehiclesOut,
-		"vehicles_by_type": map[string]interface{}{
-			"mobil": map[string]interface{}{
-				"in":  vehiclesInMobil,
-				"out": vehiclesOutMobil,
-			},
-			"motor": map[string]interface{}{
-				"in":  vehiclesInMotor,
-				"out": vehiclesOutMotor,
-			},
-		},
+		"total_in":         vehiclesIn,
+		"total_out":        vehiclesOut,
+		"vehicles_by_type": vehicleFlowByType(sessions, listVehicleTypes(u.vehicleTypeRepo), "in", "out"),
 	}, nil
 }
 
@@ -1134,8 +1123,8 @@ func (u *adminUsecase) GetChartDataDetailed(startTime, endTime *time.Time, vehic
 	}
 
 	// Calculate summary for the period
-	actualRevenue := calculateActualRevenue(u.sessionRepo, u.areaRepo, actualStart, actualEnd, regional)
-	estimatedRevenue := calculateEstimatedRevenue(u.sessionRepo, u.areaRepo, actualStart, actualEnd, regional)
+	actualRevenue := calculateActualRevenue(u.sessionRepo, u.paymentRepo, u.areaRepo, actualStart, actualEnd, regional)
+	estimatedRevenue := calculateEstimatedRevenue(u.sessionRepo, u.paymentRepo, u.areaRepo, actualStart, actualEnd, regional)
 
 	// Add summary to first item or create new structure
 	result := []map[string]interface{}{
@@ -1275,20 +1264,26 @@ func (u *adminUsecase) AddManualRevenue(req *entities.JukirRevenueRequest) (*ent
 			SessionStatus:  entities.SessionStatusCompleted,
 		}
 
-		if err := u.sessionRepo.Create(session); err != nil {
-			return nil, errors.New("failed to create manual session")
-		}
-
-		// Create payment record
-		payment := &entities.Payment{
-			SessionID:     session.ID,
-			Amount:        req.Amount,
-			PaymentMethod: entities.PaymentMethodCash,
-			Status:        entities.PaymentStatusPaid,
-		}
+		// Session and payment are created together or not at all
+		err := u.uow.Do(func(repos repository.TxRepositories) error {
+			if err := repos.Sessions.Create(session); err != nil {
+				return errors.New("failed to create manual session")
+			}
 
-		if err := u.paymentRepo.Create(payment); err != nil {
-			return nil, errors.New("failed to create payment record")
+			// Create payment record
+			payment := &entities.Payment{
+				SessionID:     session.ID,
+				Amount:        req.Amount,
+				PaymentMethod: entities.PaymentMethodCash,
+				Status:        entities.PaymentStatusPaid,
+			}
+			if err := repos.Payments.Create(payment); err != nil {
+				return errors.New("failed to create payment record")
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 	} else {
 		// If sessions exist, add manual revenue to existing total
@@ -1302,7 +1297,7 @@ func (u *adminUsecase) AddManualRevenue(req *entities.JukirRevenueRequest) (*ent
 			newTotal := currentTotal + req.Amount
 			session.TotalCost = &newTotal
 			if err := u.sessionRepo.Update(&session); err != nil {
-				return nil, errors.New("failed to update session")
+				return nil, fmt.Errorf("failed to update session: %w", err)
 			}
 		}
 	}
@@ -1440,19 +1435,30 @@ func (u *adminUsecase) GetReports(startDate, endDate time.Time, areaID *uint) (m
 		return nil, errors.New("failed to get sessions")
 	}
 
+	uncollected, err := uncollectedFees(u.paymentRepo, sessions)
+	if err != nil {
+		return nil, errors.New("failed to get session payments")
+	}
+
 	// Calculate metrics
 	totalSessions := len(sessions)
 	var totalRevenue float64
 	var completedSessions int
 	var activeSessions int
 	var pendingPayments int
+	var passSessions int
+	var lostTicketSessions []entities.ParkingSession
 
 	for _, session := range sessions {
+		if session.PassID != nil {
+			passSessions++
+		}
 		switch session.SessionStatus {
 		case entities.SessionStatusCompleted:
 			completedSessions++
-			if session.TotalCost != nil {
-				totalRevenue += *session.TotalCost
+			totalRevenue += collectedCost(session, uncollected)
+			if session.LostTicket {
+				lostTicketSessions = append(lostTicketSessions, session)
 			}
 		case entities.SessionStatusActive:
 			activeSessions++
@@ -1461,17 +1467,80 @@ func (u *adminUsecase) GetReports(startDate, endDate time.Time, areaID *uint) (m
 		}
 	}
 
+	lostTicketEvents, lostTicketPenalties, err := u.lostTicketReport(lostTicketSessions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Penjualan langganan dilaporkan terpisah dari pendapatan per kunjungan
+	var reportArea *entities.ParkingArea
+	if areaID != nil {
+		reportArea, err = u.areaRepo.GetByID(*areaID)
+		if err != nil {
+			return nil, errors.New("parking area not found")
+		}
+	}
+	passSales, err := u.passRepo.GetSalesSummary(startDate, endDate, reportArea)
+	if err != nil {
+		return nil, errors.New("failed to get pass sales")
+	}
+	passSales.PurchaseAmount = roundCurrency(passSales.PurchaseAmount)
+	passSales.RenewalAmount = roundCurrency(passSales.RenewalAmount)
+	passSales.TotalAmount = roundCurrency(passSales.TotalAmount)
+
 	return map[string]interface{}{
-		"total_sessions":     totalSessions,
-		"completed_sessions": completedSessions,
-		"active_sessions":    activeSessions,
-		"pending_payments":   pendingPayments,
-		"total_revenue":      roundCurrency(totalRevenue),
-		"start_date":         startDate.Format("2006-01-02"),
-		"end_date":           endDate.Format("2006-01-02"),
+		"total_sessions":        totalSessions,
+		"completed_sessions":    completedSessions,
+		"active_sessions":       activeSessions,
+		"pending_payments":      pendingPayments,
+		"total_revenue":         roundCurrency(totalRevenue),
+		"pass_sessions":         passSessions,
+		"pass_sales":            passSales,
+		"lost_tickets":          len(lostTicketEvents),
+		"lost_ticket_penalties": roundCurrency(lostTicketPenalties),
+		"lost_ticket_events":    lostTicketEvents,
+		"start_date":            startDate.Format("2006-01-02"),
+		"end_date":              endDate.Format("2006-01-02"),
 	}, nil
 }
 
+// lostTicketReport lists lost-ticket checkouts with the penalty taken from their penalty payment lines.
+// The penalty is already part of the session's total cost, so it is reported separately, not added to revenue.
+func (u *adminUsecase) lostTicketReport(sessions []entities.ParkingSession) ([]entities.LostTicketReportEntry, float64, error) {
+	sessionIDs := make([]uint, 0, len(sessions))
+	for _, session := range sessions {
+		sessionIDs = append(sessionIDs, session.ID)
+	}
+
+	penalties, err := u.paymentRepo.GetByKindForSessions(entities.PaymentKindLostTicketPenalty, sessionIDs)
+	if err != nil {
+		return nil, 0, errors.New("failed to get lost ticket penalties")
+	}
+	penaltyBySession := make(map[uint]float64, len(penalties))
+	for _, payment := range penalties {
+		if payment.Status == entities.PaymentStatusPaid {
+			penaltyBySession[payment.SessionID] += payment.Amount
+		}
+	}
+
+	entries := make([]entities.LostTicketReportEntry, 0, len(sessions))
+	var total float64
+	for _, session := range sessions {
+		penalty := penaltyBySession[session.ID]
+		total += penalty
+		entries = append(entries, entities.LostTicketReportEntry{
+			SessionID:    session.ID,
+			PlatNomor:    session.PlatNomor,
+			VehicleType:  session.VehicleType,
+			AreaID:       session.AreaID,
+			JukirID:      session.JukirID,
+			CheckoutTime: session.CheckoutTime,
+			Penalty:      penalty,
+		})
+	}
+	return entries, total, nil
+}
+
 func (u *adminUsecase) GetAllSessions(limit, offset int, filters map[string]interface{}) ([]entities.ParkingSession, int64, error) {
 	sessions, count, err := u.sessionRepo.GetAllSessions(limit, offset, filters)
 	if err != nil {
@@ -1494,12 +1563,56 @@ func (u *adminUsecase) CreateParkingArea(req *entities.CreateParkingAreaRequest)
 		MaxMotor:          req.MaxMotor,
 		StatusOperasional: req.StatusOperasional,
 		JenisArea:         req.JenisArea,
+		CapacityPolicy:    entities.CapacityPolicyReject,
+		MaxDuration:       req.MaxDuration,
+		ClosingTime:       req.ClosingTime,
+		OverstayPolicy:    entities.OverstayPolicyAlert,
+		LostTicketPenalty: req.LostTicketPenalty,
+	}
+	if req.CapacityPolicy != "" {
+		area.CapacityPolicy = req.CapacityPolicy
+	}
+	if req.OverstayPolicy != "" {
+		area.OverstayPolicy = req.OverstayPolicy
+	}
+	if req.Geofence != nil {
+		if err := req.Geofence.Validate(); err != nil {
+			return nil, err
+		}
+		area.Geofence = req.Geofence
+	}
+	if req.GeofenceRadius != nil && *req.GeofenceRadius > 0 {
+		area.GeofenceRadius = req.GeofenceRadius
+	}
+
+	schedules, err := buildTariffSchedules(req.TariffSchedules)
+	if err != nil {
+		return nil, err
+	}
+
+	vehicleRates, err := applyVehicleRates(u.vehicleTypeRepo, area, req.VehicleRates)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := u.areaRepo.Create(area); err != nil {
 		return nil, fmt.Errorf("failed to create parking area: %w", err)
 	}
 
+	if len(vehicleRates) > 0 {
+		if err := u.areaRepo.ReplaceVehicleRates(area.ID, vehicleRates); err != nil {
+			return nil, errors.New("failed to save vehicle rates")
+		}
+		area.VehicleRates = vehicleRates
+	}
+
+	if len(schedules) > 0 {
+		if err := u.tariffRepo.ReplaceSchedules(area.ID, schedules); err != nil {
+			return nil, errors.New("failed to save tariff schedules")
+		}
+		area.TariffSchedules = schedules
+	}
+
 	return area, nil
 }
 
@@ -1546,11 +1659,77 @@ func (u *adminUsecase) UpdateParkingArea(areaID uint, req *entities.UpdateParkin
 	if req.JenisArea != nil {
 		area.JenisArea = *req.JenisArea
 	}
+	if req.CapacityPolicy != nil {
+		area.CapacityPolicy = *req.CapacityPolicy
+	}
+	if req.MaxDuration != nil {
+		area.MaxDuration = req.MaxDuration
+		if *req.MaxDuration == 0 {
+			area.MaxDuration = nil
+		}
+	}
+	if req.ClosingTime != nil {
+		area.ClosingTime = req.ClosingTime
+		if *req.ClosingTime == "" {
+			area.ClosingTime = nil
+		}
+	}
+	if req.OverstayPolicy != nil {
+		area.OverstayPolicy = *req.OverstayPolicy
+	}
+	if req.LostTicketPenalty != nil {
+		area.LostTicketPenalty = *req.LostTicketPenalty
+	}
+	if req.ClearGeofence {
+		area.Geofence = nil
+	}
+	if req.Geofence != nil {
+		if err := req.Geofence.Validate(); err != nil {
+			return nil, err
+		}
+		area.Geofence = req.Geofence
+	}
+	if req.GeofenceRadius != nil {
+		area.GeofenceRadius = req.GeofenceRadius
+		if *req.GeofenceRadius == 0 {
+			area.GeofenceRadius = nil
+		}
+	}
+
+	var schedules []entities.TariffSchedule
+	if req.TariffSchedules != nil {
+		schedules, err = buildTariffSchedules(*req.TariffSchedules)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if req.VehicleRates != nil {
+		vehicleRates, err := applyVehicleRates(u.vehicleTypeRepo, area, *req.VehicleRates)
+		if err != nil {
+			return nil, err
+		}
+		area.VehicleRates = vehicleRates
+	}
 
 	if err := u.areaRepo.Update(area); err != nil {
 		return nil, errors.New("failed to update parking area")
 	}
 
+	if req.VehicleRates != nil {
+		if err := u.areaRepo.ReplaceVehicleRates(area.ID, area.VehicleRates); err != nil {
+			return nil, errors.New("failed to save vehicle rates")
+		}
+	}
+
+	if req.TariffSchedules != nil {
+		if err := u.tariffRepo.ReplaceSchedules(area.ID, schedules); err != nil {
+			return nil, errors.New("failed to save tariff schedules")
+		}
+	} else {
+		schedules, _ = u.tariffRepo.GetSchedulesByAreaID(area.ID)
+	}
+
 	// Return clean area data without jukirs
 	return &entities.ParkingArea{
 		ID:                area.ID,
@@ -1566,8 +1745,17 @@ func (u *adminUsecase) UpdateParkingArea(areaID uint, req *entities.UpdateParkin
 		MaxMotor:          area.MaxMotor,
 		StatusOperasional: area.StatusOperasional,
 		JenisArea:         area.JenisArea,
+		CapacityPolicy:    area.CapacityPolicy,
+		MaxDuration:       area.MaxDuration,
+		ClosingTime:       area.ClosingTime,
+		OverstayPolicy:    area.OverstayPolicy,
+		LostTicketPenalty: area.LostTicketPenalty,
+		Geofence:          area.Geofence,
+		GeofenceRadius:    area.GeofenceRadius,
 		CreatedAt:         area.CreatedAt,
 		UpdatedAt:         area.UpdatedAt,
+		TariffSchedules:   schedules,
+		VehicleRates:      area.VehicleRates,
 	}, nil
 }
 
@@ -1586,6 +1774,153 @@ func (u *adminUsecase) DeleteParkingArea(areaID uint) error {
 	return nil
 }
 
+func (u *adminUsecase) GetAreaTariffs(areaID uint) ([]entities.TariffPlan, error) {
+	if _, err := u.areaRepo.GetByID(areaID); err != nil {
+		return nil, errors.New("parking area not found")
+	}
+
+	plans, err := u.tariffRepo.GetByAreaID(areaID)
+	if err != nil {
+		return nil, errors.New("failed to get tariff plans")
+	}
+	return plans, nil
+}
+
+func (u *adminUsecase) UpsertAreaTariff(areaID uint, req *entities.UpsertTariffPlanRequest) (*entities.TariffPlan, error) {
+	if _, err := u.areaRepo.GetByID(areaID); err != nil {
+		return nil, errors.New("parking area not found")
+	}
+
+	if req.DailyCap > 0 && req.DailyCap < req.FirstHourRate {
+		return nil, errors.New("daily_cap cannot be lower than first_hour_rate")
+	}
+
+	if _, err := u.vehicleTypeRepo.GetByCode(req.VehicleType); err != nil {
+		return nil, errors.New("unknown vehicle type")
+	}
+
+	plan, err := u.tariffRepo.GetByAreaAndVehicleType(areaID, req.VehicleType)
+	if err != nil {
+		plan = &entities.TariffPlan{
+			AreaID:      areaID,
+			VehicleType: req.VehicleType,
+		}
+	}
+
+	plan.FirstHourRate = req.FirstHourRate
+	plan.NextHourRate = req.NextHourRate
+	plan.DailyCap = req.DailyCap
+	plan.GracePeriodMinutes = req.GracePeriodMinutes
+
+	if plan.ID == 0 {
+		if err := u.tariffRepo.Create(plan); err != nil {
+			return nil, errors.New("failed to create tariff plan")
+		}
+	} else if err := u.tariffRepo.Update(plan); err != nil {
+		return nil, errors.New("failed to update tariff plan")
+	}
+
+	return plan, nil
+}
+
+func (u *adminUsecase) DeleteAreaTariff(areaID uint, vehicleType entities.VehicleType) error {
+	plan, err := u.tariffRepo.GetByAreaAndVehicleType(areaID, vehicleType)
+	if err != nil {
+		return errors.New("tariff plan not found")
+	}
+
+	if err := u.tariffRepo.Delete(plan.ID); err != nil {
+		return errors.New("failed to delete tariff plan")
+	}
+	return nil
+}
+
+func (u *adminUsecase) GetVehicleTypes() ([]entities.VehicleTypeConfig, error) {
+	vehicleTypes, err := u.vehicleTypeRepo.List(false)
+	if err != nil {
+		return nil, errors.New("failed to get vehicle types")
+	}
+	return vehicleTypes, nil
+}
+
+func (u *adminUsecase) CreateVehicleType(req *entities.CreateVehicleTypeRequest) (*entities.VehicleTypeConfig, error) {
+	if _, err := u.vehicleTypeRepo.GetByCode(req.Code); err == nil {
+		return nil, errors.New("vehicle type already exists")
+	}
+
+	vehicleType := &entities.VehicleTypeConfig{
+		Code:      req.Code,
+		Name:      req.Name,
+		IsActive:  true,
+		SortOrder: req.SortOrder,
+	}
+	if err := u.vehicleTypeRepo.Create(vehicleType); err != nil {
+		return nil, errors.New("failed to create vehicle type")
+	}
+	return vehicleType, nil
+}
+
+func (u *adminUsecase) UpdateVehicleType(code entities.VehicleType, req *entities.UpdateVehicleTypeRequest) (*entities.VehicleTypeConfig, error) {
+	vehicleType, err := u.vehicleTypeRepo.GetByCode(code)
+	if err != nil {
+		return nil, errors.New("vehicle type not found")
+	}
+
+	if req.Name != nil {
+		vehicleType.Name = *req.Name
+	}
+	if req.IsActive != nil {
+		vehicleType.IsActive = *req.IsActive
+	}
+	if req.SortOrder != nil {
+		vehicleType.SortOrder = *req.SortOrder
+	}
+
+	if err := u.vehicleTypeRepo.Update(vehicleType); err != nil {
+		return nil, errors.New("failed to update vehicle type")
+	}
+	return vehicleType, nil
+}
+
+func (u *adminUsecase) GetHolidays(startDate, endDate *time.Time) ([]entities.Holiday, error) {
+	holidays, err := u.holidayRepo.List(startDate, endDate)
+	if err != nil {
+		return nil, errors.New("failed to get holidays")
+	}
+	return holidays, nil
+}
+
+func (u *adminUsecase) CreateHoliday(req *entities.CreateHolidayRequest) (*entities.Holiday, error) {
+	date, err := time.ParseInLocation("02-01-2006", req.Date, getGMT7Location())
+	if err != nil {
+		return nil, errors.New("invalid date format. Use DD-MM-YYYY")
+	}
+
+	if exists, err := u.holidayRepo.IsHoliday(date); err == nil && exists {
+		return nil, errors.New("holiday already exists for this date")
+	}
+
+	holiday := &entities.Holiday{
+		Date: date,
+		Name: req.Name,
+	}
+	if err := u.holidayRepo.Create(holiday); err != nil {
+		return nil, errors.New("failed to create holiday")
+	}
+	return holiday, nil
+}
+
+func (u *adminUsecase) DeleteHoliday(id uint) error {
+	if _, err := u.holidayRepo.GetByID(id); err != nil {
+		return errors.New("holiday not found")
+	}
+
+	if err := u.holidayRepo.Delete(id); err != nil {
+		return errors.New("failed to delete holiday")
+	}
+	return nil
+}
+
 func (u *adminUsecase) GetParkingAreas(regional *string) ([]map[string]interface{}, error) {
 	// Get all areas using List without limit/offset to get all areas with status
 	areas, _, err := u.areaRepo.List(1000, 0) // Large limit to get all
@@ -1691,23 +2026,41 @@ func (u *adminUsecase) GetParkingAreaDetail(areaID uint) (map[string]interface{}
 
 	// Format area data (without jukirs nested)
 	areaMap := map[string]interface{}{
-		"id":                 area.ID,
-		"name":               area.Name,
-		"address":            area.Address,
-		"latitude":           area.Latitude,
-		"longitude":          area.Longitude,
-		"regional":           area.Regional,
-		"image":              area.Image,
-		"hourly_rate_mobil":  area.HourlyRateMobil,
-		"hourly_rate_motor":  area.HourlyRateMotor,
-		"status":             area.Status,
-		"max_mobil":          area.MaxMobil,
-		"max_motor":          area.MaxMotor,
-		"status_operasional": area.StatusOperasional,
-		"jenis_area":         area.JenisArea,
-		"created_at":         area.CreatedAt,
-		"updated_at":         area.UpdatedAt,
-	}
+		"id":                  area.ID,
+		"name":                area.Name,
+		"address":             area.Address,
+		"latitude":            area.Latitude,
+		"longitude":           area.Longitude,
+		"regional":            area.Regional,
+		"image":               area.Image,
+		"hourly_rate_mobil":   area.HourlyRateMobil,
+		"hourly_rate_motor":   area.HourlyRateMotor,
+		"status":              area.Status,
+		"max_mobil":           area.MaxMobil,
+		"max_motor":           area.MaxMotor,
+		"status_operasional":  area.StatusOperasional,
+		"jenis_area":          area.JenisArea,
+		"capacity_policy":     area.CapacityPolicy,
+		"max_duration":        area.MaxDuration,
+		"closing_time":        area.ClosingTime,
+		"overstay_policy":     area.OverstayPolicy,
+		"lost_ticket_penalty": area.LostTicketPenalty,
+		"geofence":            area.Geofence,
+		"geofence_radius":     area.GeofenceRadius,
+		"hours_override":      area.HoursOverride,
+		"created_at":          area.CreatedAt,
+		"updated_at":          area.UpdatedAt,
+	}
+
+	if schedules, err := u.tariffRepo.GetSchedulesByAreaID(areaID); err == nil {
+		areaMap["tariff_schedules"] = schedules
+	}
+	if hours, err := u.getOperatingHours(*area); err == nil {
+		areaMap["operating_hours"] = hours.Weekly
+		areaMap["schedule_exceptions"] = hours.Exceptions
+		areaMap["is_open"] = hours.IsOpen
+	}
+	areaMap["vehicle_rates"] = area.VehicleRates
 
 	// Format jukirs data (without nested area, only user info)
 	jukirsData := make([]map[string]interface{}, len(jukirs))
@@ -1756,61 +2109,54 @@ func (u *adminUsecase) GetParkingAreaStatus(areaID uint) (map[string]interface{}
 		return nil, errors.New("failed to get sessions")
 	}
 
-	// Filter active sessions for this area
-	activeMobilCount := 0
-	activeMotorCount := 0
-
+	// Count active sessions for this area by vehicle type
+	activeCounts := make(map[entities.VehicleType]int)
 	for _, session := range allSessions {
 		if session.AreaID == areaID && session.SessionStatus == entities.SessionStatusActive {
-			if session.VehicleType == entities.VehicleTypeMobil {
-				activeMobilCount++
-			} else if session.VehicleType == entities.VehicleTypeMotor {
-				activeMotorCount++
-			}
+			activeCounts[session.VehicleType]++
 		}
 	}
 
-	// Calculate available slots
-	var maxMobil int = 0
-	var maxMotor int = 0
-	if area.MaxMobil != nil {
-		maxMobil = *area.MaxMobil
-	}
-	if area.MaxMotor != nil {
-		maxMotor = *area.MaxMotor
+	result := map[string]interface{}{
+		"area": map[string]interface{}{
+			"id":   area.ID,
+			"name": area.Name,
+		},
 	}
 
-	availableMobil := maxMobil - activeMobilCount
-	availableMotor := maxMotor - activeMotorCount
+	// Calculate available slots for every vehicle type the area accepts
+	totalVehicles := 0
+	totalCapacity := 0
+	for _, vt := range listVehicleTypes(u.vehicleTypeRepo) {
+		if !area.AcceptsVehicleType(vt.Code) {
+			continue
+		}
 
-	// Make sure available is not negative
-	if availableMobil < 0 {
-		availableMobil = 0
-	}
-	if availableMotor < 0 {
-		availableMotor = 0
+		capacity := 0
+		if configured := area.GetCapacityByVehicleType(vt.Code); configured != nil {
+			capacity = *configured
+		}
+		occupied := activeCounts[vt.Code]
+
+		// Make sure available is not negative
+		available := capacity - occupied
+		if available < 0 {
+			available = 0
+		}
+
+		result[string(vt.Code)] = map[string]interface{}{
+			"total":     capacity,
+			"occupied":  occupied,
+			"available": available,
+			"is_full":   available == 0 && capacity > 0,
+		}
+		totalVehicles += occupied
+		totalCapacity += capacity
 	}
 
-	return map[string]interface{}{
-		"area": map[string]interface{}{
-			"id":   area.ID,
-			"name": area.Name,
-		},
-		"mobil": map[string]interface{}{
-			"total":     maxMobil,
-			"occupied":  activeMobilCount,
-			"available": availableMobil,
-			"is_full":   availableMobil == 0 && maxMobil > 0,
-		},
-		"motor": map[string]interface{}{
-			"total":     maxMotor,
-			"occupied":  activeMotorCount,
-			"available": availableMotor,
-			"is_full":   availableMotor == 0 && maxMotor > 0,
-		},
-		"total_vehicles": activeMobilCount + activeMotorCount,
-		"total_capacity": maxMobil + maxMotor,
-	}, nil
+	result["total_vehicles"] = totalVehicles
+	result["total_capacity"] = totalCapacity
+	return result, nil
 }
 
 func (u *adminUsecase) GetAreaTransactions(areaID uint, limit, offset int, startTime, endTime *time.Time) ([]map[string]interface{}, int64, error) {
@@ -2157,8 +2503,8 @@ func (u *adminUsecase) generateDailyChartData(start, end time.Time, regional *st
 			dayEnd = end
 		}
 
-		actualRevenue := calculateActualRevenue(u.sessionRepo, u.areaRepo, dayStart, dayEnd, regional)
-		estimatedRevenue := calculateEstimatedRevenue(u.sessionRepo, u.areaRepo, dayStart, dayEnd, regional)
+		actualRevenue := calculateActualRevenue(u.sessionRepo, u.paymentRepo, u.areaRepo, dayStart, dayEnd, regional)
+		estimatedRevenue := calculateEstimatedRevenue(u.sessionRepo, u.paymentRepo, u.areaRepo, dayStart, dayEnd, regional)
 
 		periods = append(periods, map[string]interface{}{
 			"period":            weekdays[d.Weekday()],
@@ -2197,8 +2543,8 @@ func (u *adminUsecase) generateWeeklyChartData(start, end time.Time, regional *s
 
 		// Only proceed if weekStart is within or before the range
 		if !weekStart.After(end) {
-			actualRevenue := calculateActualRevenue(u.sessionRepo, u.areaRepo, weekStart, weekEnd, regional)
-			estimatedRevenue := calculateEstimatedRevenue(u.sessionRepo, u.areaRepo, weekStart, weekEnd, regional)
+			actualRevenue := calculateActualRevenue(u.sessionRepo, u.paymentRepo, u.areaRepo, weekStart, weekEnd, regional)
+			estimatedRevenue := calculateEstimatedRevenue(u.sessionRepo, u.paymentRepo, u.areaRepo, weekStart, weekEnd, regional)
 
 			periods = append(periods, map[string]interface{}{
 				"period":            fmt.Sprintf("Minggu %d", weekNum),
@@ -2240,8 +2586,8 @@ func (u *adminUsecase) generateMonthlyChartData(start, end time.Time, regional *
 			monthEnd = end
 		}
 
-		actualRevenue := calculateActualRevenue(u.sessionRepo, u.areaRepo, monthStart, monthEnd, regional)
-		estimatedRevenue := calculateEstimatedRevenue(u.sessionRepo, u.areaRepo, monthStart, monthEnd, regional)
+		actualRevenue := calculateActualRevenue(u.sessionRepo, u.paymentRepo, u.areaRepo, monthStart, monthEnd, regional)
+		estimatedRevenue := calculateEstimatedRevenue(u.sessionRepo, u.paymentRepo, u.areaRepo, monthStart, monthEnd, regional)
 
 		periods = append(periods, map[string]interface{}{
 			"period":            monthNames[current.Month()-1],
@@ -2471,6 +2817,7 @@ func (u *adminUsecase) GetAreaActivity(startTime, endTime *time.Time, areaID *ui
 	}
 
 	// Process each area
+	vehicleTypes := listVehicleTypes(u.vehicleTypeRepo)
 	result := make([]map[string]interface{}, 0, len(areas))
 	for _, area := range areas {
 		// Get all sessions for this area in date range
@@ -2480,40 +2827,29 @@ func (u *adminUsecase) GetAreaActivity(startTime, endTime *time.Time, areaID *ui
 		}
 
 		// Count masuk (checkin) and keluar (checkout) by vehicle type
-		mobilMasuk := 0
-		mobilKeluar := 0
-		motorMasuk := 0
-		motorKeluar := 0
-
-		for _, session := range sessions {
-			if session.VehicleType == entities.VehicleTypeMobil {
-				mobilMasuk++
-				if session.CheckoutTime != nil {
-					mobilKeluar++
-				}
-			} else if session.VehicleType == entities.VehicleTypeMotor {
-				motorMasuk++
-				if session.CheckoutTime != nil {
-					motorKeluar++
-				}
+		vehicleCounts := make(map[string]interface{})
+		totalMasuk := 0
+		totalKeluar := 0
+		for code, flow := range countVehicleFlow(sessions, vehicleTypes) {
+			vehicleCounts[string(code)] = map[string]interface{}{
+				"masuk":  flow.In,
+				"keluar": flow.Out,
 			}
+			totalMasuk += flow.In
+			totalKeluar += flow.Out
 		}
 
-		result = append(result, map[string]interface{}{
-			"area_id":   area.ID,
-			"area_name": area.Name,
-			"regional":  area.Regional,
-			"mobil": map[string]interface{}{
-				"masuk":  mobilMasuk,
-				"keluar": mobilKeluar,
-			},
-			"motor": map[string]interface{}{
-				"masuk":  motorMasuk,
-				"keluar": motorKeluar,
-			},
-			"total_masuk":  mobilMasuk + motorMasuk,
-			"total_keluar": mobilKeluar + motorKeluar,
-		})
+		entry := map[string]interface{}{
+			"area_id":      area.ID,
+			"area_name":    area.Name,
+			"regional":     area.Regional,
+			"total_masuk":  totalMasuk,
+			"total_keluar": totalKeluar,
+		}
+		for code, counts := range vehicleCounts {
+			entry[code] = counts
+		}
+		result = append(result, entry)
 	}
 
 	return map[string]interface{}{
@@ -2575,122 +2911,84 @@ func (u *adminUsecase) GetAreaActivityDetail(areaID uint, startTime, endTime *ti
 	}
 
 	// Process sessions by vehicle type and interval
-	mobilData := make([]map[string]interface{}, len(intervals))
-	motorData := make([]map[string]interface{}, len(intervals))
-
-	for j, interval := range intervals {
-		intervalStart, ok1 := interval["start"].(time.Time)
-		intervalEnd, ok2 := interval["end"].(time.Time)
-		if !ok1 || !ok2 {
-			// If interval parsing fails, create empty stats
-			dateStr := ""
-			if date, ok := interval["date"].(string); ok {
-				dateStr = date
-			}
-			mobilData[j] = map[string]interface{}{
-				"periode":       interval["label"],
-				"date":          dateStr, // Add date field for frontend filtering
-				"datang":        0,
-				"berangkat":     0,
-				"akumulasi":     0,
-				"volume":        0,
-				"indeks_parkir": "0%",
-			}
-			motorData[j] = map[string]interface{}{
-				"periode":       interval["label"],
-				"date":          dateStr, // Add date field for frontend filtering
-				"datang":        0,
-				"berangkat":     0,
-				"akumulasi":     0,
-				"volume":        0,
-				"indeks_parkir": "0%",
-			}
-			continue
-		}
-
-		intervalMap := map[string]interface{}{
-			"start": intervalStart,
-			"end":   intervalEnd,
-			"label": interval["label"],
-		}
+	result := map[string]interface{}{
+		"area_id":    area.ID,
+		"area_name":  area.Name,
+		"regional":   area.Regional,
+		"start_date": actualStart.Format("2006-01-02"),
+		"end_date":   actualEnd.Format("2006-01-02"),
+	}
+	for key, value := range u.buildIntervalActivity(sessions, intervals, *area) {
+		result[key] = value
+	}
+	return result, nil
+}
 
-		mobilStats := u.calculateIntervalStats(sessions, intervalMap, entities.VehicleTypeMobil, area.MaxMobil)
-		motorStats := u.calculateIntervalStats(sessions, intervalMap, entities.VehicleTypeMotor, area.MaxMotor)
+// buildIntervalActivity computes the 15-minute interval stats for every vehicle type the
+// area accepts. Each type gets "<code>" (intervals), "kapasitas_<code>" and
+// "total_<code>_datang"; "vehicle_types" lists the types in display order.
+func (u *adminUsecase) buildIntervalActivity(sessions []entities.ParkingSession, intervals []map[string]interface{}, area entities.ParkingArea) map[string]interface{} {
+	result := make(map[string]interface{})
+	vehicleTypes := make([]map[string]interface{}, 0)
 
-		// Get date from interval for adding to stats
-		dateStr := ""
-		if date, ok := interval["date"].(string); ok {
-			dateStr = date
-		} else {
-			// Fallback: extract date from interval start time
-			dateStr = intervalStart.Format("2006-01-02")
+	for _, vt := range listVehicleTypes(u.vehicleTypeRepo) {
+		if !area.AcceptsVehicleType(vt.Code) {
+			continue
 		}
-
-		// Ensure indeks_parkir is always present and add date field
-		if mobilStats == nil {
-			mobilStats = map[string]interface{}{
-				"periode":       interval["label"],
-				"date":          dateStr,
-				"datang":        0,
-				"berangkat":     0,
-				"akumulasi":     0,
-				"volume":        0,
-				"indeks_parkir": "0%",
+		capacity := area.GetCapacityByVehicleType(vt.Code)
+
+		data := make([]map[string]interface{}, len(intervals))
+		totalDatang := 0
+		for j, interval := range intervals {
+			// Get date from interval for adding to stats
+			dateStr, _ := interval["date"].(string)
+
+			intervalStart, ok1 := interval["start"].(time.Time)
+			intervalEnd, ok2 := interval["end"].(time.Time)
+			if !ok1 || !ok2 {
+				// If interval parsing fails, create empty stats
+				data[j] = map[string]interface{}{
+					"periode":       interval["label"],
+					"date":          dateStr, // Add date field for frontend filtering
+					"datang":        0,
+					"berangkat":     0,
+					"akumulasi":     0,
+					"volume":        0,
+					"indeks_parkir": "0%",
+				}
+				continue
 			}
-		} else {
-			// Add date field to existing stats
-			mobilStats["date"] = dateStr
-		}
-		if motorStats == nil {
-			motorStats = map[string]interface{}{
-				"periode":       interval["label"],
-				"date":          dateStr,
-				"datang":        0,
-				"berangkat":     0,
-				"akumulasi":     0,
-				"volume":        0,
-				"indeks_parkir": "0%",
+			if dateStr == "" {
+				// Fallback: extract date from interval start time
+				dateStr = intervalStart.Format("2006-01-02")
 			}
-		} else {
-			// Add date field to existing stats
-			motorStats["date"] = dateStr
-		}
-
-		mobilData[j] = mobilStats
-		motorData[j] = motorStats
-	}
 
-	// Calculate totals
-	totalMobilDatang := 0
-	totalMotorDatang := 0
-	for _, mobil := range mobilData {
-		if mobil != nil {
-			if datang, ok := mobil["datang"].(int); ok {
-				totalMobilDatang += datang
+			intervalMap := map[string]interface{}{
+				"start": intervalStart,
+				"end":   intervalEnd,
+				"label": interval["label"],
 			}
-		}
-	}
-	for _, motor := range motorData {
-		if motor != nil {
-			if datang, ok := motor["datang"].(int); ok {
-				totalMotorDatang += datang
+
+			stats := u.calculateIntervalStats(sessions, intervalMap, vt.Code, capacity)
+			stats["date"] = dateStr
+			if datang, ok := stats["datang"].(int); ok {
+				totalDatang += datang
 			}
+			data[j] = stats
 		}
+
+		code := string(vt.Code)
+		result[code] = data
+		result["kapasitas_"+code] = capacity
+		result["total_"+code+"_datang"] = totalDatang
+		vehicleTypes = append(vehicleTypes, map[string]interface{}{
+			"code": vt.Code,
+			"name": vt.Name,
+		})
 	}
 
-	return map[string]interface{}{
-		"area_id":            area.ID,
-		"area_name":          area.Name,
-		"regional":           area.Regional,
-		"kapasitas_mobil":    area.MaxMobil,
-		"kapasitas_motor":    area.MaxMotor,
-		"start_date":         actualStart.Format("2006-01-02"),
-		"end_date":           actualEnd.Format("2006-01-02"),
-		"mobil":              mobilData,
-		"motor":              motorData,
-		"total_mobil_datang": totalMobilDatang,
-		"total_motor_datang": totalMotorDatang,
-	}, nil
+	result["vehicle_types"] = vehicleTypes
+	return result
 }
 
 // create15MinuteIntervals creates time intervals of 15 minutes
@@ -2936,6 +3234,7 @@ func (u *adminUsecase) GetJukirActivity(startTime, endTime *time.Time, jukirID *
 	}
 
 	// Process each jukir
+	vehicleTypes := listVehicleTypes(u.vehicleTypeRepo)
 	result := make([]map[string]interface{}, 0, len(jukirs))
 	for _, jukir := range jukirs {
 		// Get all sessions for this area in date range
@@ -2953,46 +3252,35 @@ func (u *adminUsecase) GetJukirActivity(startTime, endTime *time.Time, jukirID *
 		}
 
 		// Count masuk (checkin) and keluar (checkout) by vehicle type
-		mobilMasuk := 0
-		mobilKeluar := 0
-		motorMasuk := 0
-		motorKeluar := 0
-
-		for _, session := range sessions {
-			if session.VehicleType == entities.VehicleTypeMobil {
-				mobilMasuk++
-				if session.CheckoutTime != nil {
-					mobilKeluar++
-				}
-			} else if session.VehicleType == entities.VehicleTypeMotor {
-				motorMasuk++
-				if session.CheckoutTime != nil {
-					motorKeluar++
-				}
+		vehicleCounts := make(map[string]interface{})
+		totalMasuk := 0
+		totalKeluar := 0
+		for code, flow := range countVehicleFlow(sessions, vehicleTypes) {
+			vehicleCounts[string(code)] = map[string]interface{}{
+				"masuk":  flow.In,
+				"keluar": flow.Out,
 			}
+			totalMasuk += flow.In
+			totalKeluar += flow.Out
 		}
 
 		// Add date field for frontend filtering (use start date as reference)
 		dateStr := actualStart.Format("2006-01-02")
 
-		result = append(result, map[string]interface{}{
-			"jukir_id":   jukir.ID,
-			"jukir_name": jukir.User.Name,
-			"area_id":    jukir.Area.ID,
-			"area_name":  jukir.Area.Name,
-			"regional":   jukir.Area.Regional,
-			"date":       dateStr, // Add date field for frontend filtering
-			"mobil": map[string]interface{}{
-				"masuk":  mobilMasuk,
-				"keluar": mobilKeluar,
-			},
-			"motor": map[string]interface{}{
-				"masuk":  motorMasuk,
-				"keluar": motorKeluar,
-			},
-			"total_masuk":  mobilMasuk + motorMasuk,
-			"total_keluar": mobilKeluar + motorKeluar,
-		})
+		entry := map[string]interface{}{
+			"jukir_id":     jukir.ID,
+			"jukir_name":   jukir.User.Name,
+			"area_id":      jukir.Area.ID,
+			"area_name":    jukir.Area.Name,
+			"regional":     jukir.Area.Regional,
+			"date":         dateStr, // Add date field for frontend filtering
+			"total_masuk":  totalMasuk,
+			"total_keluar": totalKeluar,
+		}
+		for code, counts := range vehicleCounts {
+			entry[code] = counts
+		}
+		result = append(result, entry)
 	}
 
 	return map[string]interface{}{
@@ -3062,124 +3350,19 @@ func (u *adminUsecase) GetJukirActivityDetail(jukirID uint, startTime, endTime *
 	}
 
 	// Process sessions by vehicle type and interval
-	mobilData := make([]map[string]interface{}, len(intervals))
-	motorData := make([]map[string]interface{}, len(intervals))
-
-	for j, interval := range intervals {
-		intervalStart, ok1 := interval["start"].(time.Time)
-		intervalEnd, ok2 := interval["end"].(time.Time)
-		if !ok1 || !ok2 {
-			// If interval parsing fails, create empty stats
-			dateStr := ""
-			if date, ok := interval["date"].(string); ok {
-				dateStr = date
-			}
-			mobilData[j] = map[string]interface{}{
-				"periode":       interval["label"],
-				"date":          dateStr, // Add date field for frontend filtering
-				"datang":        0,
-				"berangkat":     0,
-				"akumulasi":     0,
-				"volume":        0,
-				"indeks_parkir": "0%",
-			}
-			motorData[j] = map[string]interface{}{
-				"periode":       interval["label"],
-				"date":          dateStr, // Add date field for frontend filtering
-				"datang":        0,
-				"berangkat":     0,
-				"akumulasi":     0,
-				"volume":        0,
-				"indeks_parkir": "0%",
-			}
-			continue
-		}
-
-		intervalMap := map[string]interface{}{
-			"start": intervalStart,
-			"end":   intervalEnd,
-			"label": interval["label"],
-		}
-
-		mobilStats := u.calculateIntervalStats(sessions, intervalMap, entities.VehicleTypeMobil, jukir.Area.MaxMobil)
-		motorStats := u.calculateIntervalStats(sessions, intervalMap, entities.VehicleTypeMotor, jukir.Area.MaxMotor)
-
-		// Get date from interval for adding to stats
-		dateStr := ""
-		if date, ok := interval["date"].(string); ok {
-			dateStr = date
-		} else {
-			// Fallback: extract date from interval start time
-			dateStr = intervalStart.Format("2006-01-02")
-		}
-
-		// Ensure indeks_parkir is always present and add date field
-		if mobilStats == nil {
-			mobilStats = map[string]interface{}{
-				"periode":       interval["label"],
-				"date":          dateStr,
-				"datang":        0,
-				"berangkat":     0,
-				"akumulasi":     0,
-				"volume":        0,
-				"indeks_parkir": "0%",
-			}
-		} else {
-			// Add date field to existing stats
-			mobilStats["date"] = dateStr
-		}
-		if motorStats == nil {
-			motorStats = map[string]interface{}{
-				"periode":       interval["label"],
-				"date":          dateStr,
-				"datang":        0,
-				"berangkat":     0,
-				"akumulasi":     0,
-				"volume":        0,
-				"indeks_parkir": "0%",
-			}
-		} else {
-			// Add date field to existing stats
-			motorStats["date"] = dateStr
-		}
-
-		mobilData[j] = mobilStats
-		motorData[j] = motorStats
-	}
-
-	// Calculate totals
-	totalMobilDatang := 0
-	totalMotorDatang := 0
-	for _, mobil := range mobilData {
-		if mobil != nil {
-			if datang, ok := mobil["datang"].(int); ok {
-				totalMobilDatang += datang
-			}
-		}
+	result := map[string]interface{}{
+		"jukir_id":   jukir.ID,
+		"jukir_name": jukir.User.Name,
+		"area_id":    jukir.Area.ID,
+		"area_name":  jukir.Area.Name,
+		"regional":   jukir.Area.Regional,
+		"start_date": actualStart.Format("2006-01-02"),
+		"end_date":   actualEnd.Format("2006-01-02"),
+	}
+	for key, value := range u.buildIntervalActivity(sessions, intervals, jukir.Area) {
+		result[key] = value
 	}
-	for _, motor := range motorData {
-		if motor != nil {
-			if datang, ok := motor["datang"].(int); ok {
-				totalMotorDatang += datang
-			}
-		}
-	}
-
-	return map[string]interface{}{
-		"jukir_id":           jukir.ID,
-		"jukir_name":         jukir.User.Name,
-		"area_id":            jukir.Area.ID,
-		"area_name":          jukir.Area.Name,
-		"regional":           jukir.Area.Regional,
-		"kapasitas_mobil":    jukir.Area.MaxMobil,
-		"kapasitas_motor":    jukir.Area.MaxMotor,
-		"start_date":         actualStart.Format("2006-01-02"),
-		"end_date":           actualEnd.Format("2006-01-02"),
-		"mobil":              mobilData,
-		"motor":              motorData,
-		"total_mobil_datang": totalMobilDatang,
-		"total_motor_datang": totalMotorDatang,
-	}, nil
+	return result, nil
 }
 
 // ExportAreaActivityCSV exports area activity to CSV format
@@ -3393,82 +3576,8 @@ func (u *adminUsecase) ExportAreaActivityDetailXLSX(areaID uint, startTime, endT
 }
 
 func writeAreaActivitySheet(f *excelize.File, sheetName string, activityData map[string]interface{}) error {
-	row := 1
-
-	f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), "REKAPITULASI DATA PARKIR")
-	row += 2
-
 	areaName, _ := activityData["area_name"].(string)
-	kapasitasMobil := intFromAny(activityData["kapasitas_mobil"])
-	kapasitasMotor := intFromAny(activityData["kapasitas_motor"])
-
-	f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), areaName)
-	f.SetCellValue(sheetName, fmt.Sprintf("E%d", row), "Kapasitas")
-	f.SetCellValue(sheetName, fmt.Sprintf("F%d", row), kapasitasMobil)
-	f.SetCellValue(sheetName, fmt.Sprintf("H%d", row), areaName)
-	f.SetCellValue(sheetName, fmt.Sprintf("L%d", row), "Kapasitas")
-	f.SetCellValue(sheetName, fmt.Sprintf("M%d", row), kapasitasMotor)
-	row++
-
-	f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), "Mobil Penumpang")
-	f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), "Jumlah Kendaraan")
-	f.SetCellValue(sheetName, fmt.Sprintf("E%d", row), "Akumulasi")
-	f.SetCellValue(sheetName, fmt.Sprintf("F%d", row), "Volume")
-	f.SetCellValue(sheetName, fmt.Sprintf("G%d", row), "Indeks Parkir")
-
-	f.SetCellValue(sheetName, fmt.Sprintf("H%d", row), "Motor")
-	f.SetCellValue(sheetName, fmt.Sprintf("I%d", row), "Jumlah Kendaraan")
-	f.SetCellValue(sheetName, fmt.Sprintf("L%d", row), "Akumulasi")
-	f.SetCellValue(sheetName, fmt.Sprintf("M%d", row), "Volume")
-	f.SetCellValue(sheetName, fmt.Sprintf("N%d", row), "Indeks Parkir")
-	row++
-
-	f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), "Periode Pengamatan")
-	f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), "Datang")
-	f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), "Berangkat")
-	f.SetCellValue(sheetName, fmt.Sprintf("H%d", row), "Periode Pengamatan")
-	f.SetCellValue(sheetName, fmt.Sprintf("I%d", row), "Datang")
-	f.SetCellValue(sheetName, fmt.Sprintf("J%d", row), "Berangkat")
-	row++
-
-	mobilIntervals, _ := activityData["mobil"].([]map[string]interface{})
-	motorIntervals, _ := activityData["motor"].([]map[string]interface{})
-
-	maxLen := len(mobilIntervals)
-	if len(motorIntervals) > maxLen {
-		maxLen = len(motorIntervals)
-	}
-
-	for i := 0; i < maxLen; i++ {
-		if i < len(mobilIntervals) {
-			m := mobilIntervals[i]
-			f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), m["periode"])
-			f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), m["datang"])
-			f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), m["berangkat"])
-			f.SetCellValue(sheetName, fmt.Sprintf("E%d", row), m["akumulasi"])
-			f.SetCellValue(sheetName, fmt.Sprintf("F%d", row), m["volume"])
-			f.SetCellValue(sheetName, fmt.Sprintf("G%d", row), m["indeks_parkir"])
-		}
-
-		if i < len(motorIntervals) {
-			m := motorIntervals[i]
-			f.SetCellValue(sheetName, fmt.Sprintf("H%d", row), m["periode"])
-			f.SetCellValue(sheetName, fmt.Sprintf("I%d", row), m["datang"])
-			f.SetCellValue(sheetName, fmt.Sprintf("J%d", row), m["berangkat"])
-			f.SetCellValue(sheetName, fmt.Sprintf("L%d", row), m["akumulasi"])
-			f.SetCellValue(sheetName, fmt.Sprintf("M%d", row), m["volume"])
-			f.SetCellValue(sheetName, fmt.Sprintf("N%d", row), m["indeks_parkir"])
-		}
-
-		row++
-	}
-
-	totalMobil := intFromAny(activityData["total_mobil_datang"])
-	totalMotor := intFromAny(activityData["total_motor_datang"])
-	f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), totalMobil)
-	f.SetCellValue(sheetName, fmt.Sprintf("I%d", row), totalMotor)
-
-	return nil
+	return writeActivitySheet(f, sheetName, "REKAPITULASI DATA PARKIR", areaName, activityData)
 }
 
 // ExportJukirActivityDetailXLSX exports jukir activity detail to XLSX format
@@ -3549,82 +3658,70 @@ func (u *adminUsecase) ExportJukirActivityDetailXLSX(jukirID uint, startTime, en
 }
 
 func writeJukirActivitySheet(f *excelize.File, sheetName string, activityData map[string]interface{}) error {
-	row := 1
-
-	f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), "REKAPITULASI DATA PARKIR - JUKIR")
-	row += 2
-
 	jukirName, _ := activityData["jukir_name"].(string)
 	areaName, _ := activityData["area_name"].(string)
-	kapasitasMobil := intFromAny(activityData["kapasitas_mobil"])
-	kapasitasMotor := intFromAny(activityData["kapasitas_motor"])
 	title := fmt.Sprintf("%s - %s", jukirName, areaName)
+	return writeActivitySheet(f, sheetName, "REKAPITULASI DATA PARKIR - JUKIR", title, activityData)
+}
 
-	f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), title)
-	f.SetCellValue(sheetName, fmt.Sprintf("E%d", row), "Kapasitas")
-	f.SetCellValue(sheetName, fmt.Sprintf("F%d", row), kapasitasMobil)
-	f.SetCellValue(sheetName, fmt.Sprintf("H%d", row), title)
-	f.SetCellValue(sheetName, fmt.Sprintf("L%d", row), "Kapasitas")
-	f.SetCellValue(sheetName, fmt.Sprintf("M%d", row), kapasitasMotor)
-	row++
-
-	f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), "Mobil Penumpang")
-	f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), "Jumlah Kendaraan")
-	f.SetCellValue(sheetName, fmt.Sprintf("E%d", row), "Akumulasi")
-	f.SetCellValue(sheetName, fmt.Sprintf("F%d", row), "Volume")
-	f.SetCellValue(sheetName, fmt.Sprintf("G%d", row), "Indeks Parkir")
-
-	f.SetCellValue(sheetName, fmt.Sprintf("H%d", row), "Motor")
-	f.SetCellValue(sheetName, fmt.Sprintf("I%d", row), "Jumlah Kendaraan")
-	f.SetCellValue(sheetName, fmt.Sprintf("L%d", row), "Akumulasi")
-	f.SetCellValue(sheetName, fmt.Sprintf("M%d", row), "Volume")
-	f.SetCellValue(sheetName, fmt.Sprintf("N%d", row), "Indeks Parkir")
-	row++
-
-	f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), "Periode Pengamatan")
-	f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), "Datang")
-	f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), "Berangkat")
-	f.SetCellValue(sheetName, fmt.Sprintf("H%d", row), "Periode Pengamatan")
-	f.SetCellValue(sheetName, fmt.Sprintf("I%d", row), "Datang")
-	f.SetCellValue(sheetName, fmt.Sprintf("J%d", row), "Berangkat")
-	row++
-
-	mobilIntervals, _ := activityData["mobil"].([]map[string]interface{})
-	motorIntervals, _ := activityData["motor"].([]map[string]interface{})
-
-	maxLen := len(mobilIntervals)
-	if len(motorIntervals) > maxLen {
-		maxLen = len(motorIntervals)
-	}
-
-	for i := 0; i < maxLen; i++ {
-		if i < len(mobilIntervals) {
-			m := mobilIntervals[i]
-			f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), m["periode"])
-			f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), m["datang"])
-			f.SetCellValue(sheetName, fmt.Sprintf("C%d", row), m["berangkat"])
-			f.SetCellValue(sheetName, fmt.Sprintf("E%d", row), m["akumulasi"])
-			f.SetCellValue(sheetName, fmt.Sprintf("F%d", row), m["volume"])
-			f.SetCellValue(sheetName, fmt.Sprintf("G%d", row), m["indeks_parkir"])
-		}
-
-		if i < len(motorIntervals) {
-			m := motorIntervals[i]
-			f.SetCellValue(sheetName, fmt.Sprintf("H%d", row), m["periode"])
-			f.SetCellValue(sheetName, fmt.Sprintf("I%d", row), m["datang"])
-			f.SetCellValue(sheetName, fmt.Sprintf("J%d", row), m["berangkat"])
-			f.SetCellValue(sheetName, fmt.Sprintf("L%d", row), m["akumulasi"])
-			f.SetCellValue(sheetName, fmt.Sprintf("M%d", row), m["volume"])
-			f.SetCellValue(sheetName, fmt.Sprintf("N%d", row), m["indeks_parkir"])
-		}
+// writeActivitySheet lays out one 7-column block per vehicle type side by side
+// (mobil in A-G, motor in H-N, further registry types after that)
+func writeActivitySheet(f *excelize.File, sheetName, heading, title string, activityData map[string]interface{}) error {
+	const blockWidth = 7
 
-		row++
+	cell := func(block, offset, row int) string {
+		name, _ := excelize.CoordinatesToCellName(block*blockWidth+offset+1, row)
+		return name
 	}
 
-	totalMobil := intFromAny(activityData["total_mobil_datang"])
-	totalMotor := intFromAny(activityData["total_motor_datang"])
-	f.SetCellValue(sheetName, fmt.Sprintf("B%d", row), totalMobil)
-	f.SetCellValue(sheetName, fmt.Sprintf("I%d", row), totalMotor)
+	row := 1
+	f.SetCellValue(sheetName, fmt.Sprintf("A%d", row), heading)
+	row += 2
+
+	vehicleTypes, _ := activityData["vehicle_types"].([]map[string]interface{})
+	headerRow := row
+	dataRow := row + 3
+	for block, vt := range vehicleTypes {
+		code := fmt.Sprint(vt["code"])
+
+		f.SetCellValue(sheetName, cell(block, 0, headerRow), title)
+		f.SetCellValue(sheetName, cell(block, 4, headerRow), "Kapasitas")
+		f.SetCellValue(sheetName, cell(block, 5, headerRow), intFromAny(activityData["kapasitas_"+code]))
+
+		f.SetCellValue(sheetName, cell(block, 0, headerRow+1), vt["name"])
+		f.SetCellValue(sheetName, cell(block, 1, headerRow+1), "Jumlah Kendaraan")
+		f.SetCellValue(sheetName, cell(block, 4, headerRow+1), "Akumulasi")
+		f.SetCellValue(sheetName, cell(block, 5, headerRow+1), "Volume")
+		f.SetCellValue(sheetName, cell(block, 6, headerRow+1), "Indeks Parkir")
+
+		f.SetCellValue(sheetName, cell(block, 0, headerRow+2), "Periode Pengamatan")
+		f.SetCellValue(sheetName, cell(block, 1, headerRow+2), "Datang")
+		f.SetCellValue(sheetName, cell(block, 2, headerRow+2), "Berangkat")
+
+		intervals, _ := activityData[code].([]map[string]interface{})
+		r := dataRow
+		for _, m := range intervals {
+			f.SetCellValue(sheetName, cell(block, 0, r), m["periode"])
+			f.SetCellValue(sheetName, cell(block, 1, r), m["datang"])
+			f.SetCellValue(sheetName, cell(block, 2, r), m["berangkat"])
+			f.SetCellValue(sheetName, cell(block, 4, r), m["akumulasi"])
+			f.SetCellValue(sheetName, cell(block, 5, r), m["volume"])
+			f.SetCellValue(sheetName, cell(block, 6, r), m["indeks_parkir"])
+			r++
+		}
+		if r > row {
+			row = r
+		}
+	}
+
+	// Total row under the longest block
+	if row < dataRow {
+		row = dataRow
+	}
+	for block, vt := range vehicleTypes {
+		code := fmt.Sprint(vt["code"])
+		f.SetCellValue(sheetName, cell(block, 1, row), intFromAny(activityData["total_"+code+"_datang"]))
+	}
 
 	return nil
 }
@@ -3843,7 +3940,12 @@ func (u *adminUsecase) ImportAreasAndJukirsFromCSV(reader io.Reader, regional st
 			continue
 		}
 
-		qrToken := "QR_" + jukirCode + "_" + time.Now().Format("20060102150405")
+		qrToken, err := newQRToken(jukirCode)
+		if err != nil {
+			u.userRepo.Delete(user.ID)
+			errorList = append(errorList, fmt.Sprintf("Row %d: failed to generate QR token", rowNum))
+			continue
+		}
 		jukir := &entities.Jukir{
 			UserID:    user.ID,
 			JukirCode: jukirCode,
@@ -3876,7 +3978,7 @@ func getColumnValue(record []string, colMap map[string]int, colName string) stri
 	return ""
 }
 
-func calculateActualRevenue(sessionRepo repository.ParkingSessionRepository, areaRepo repository.ParkingAreaRepository, start, end time.Time, regional *string) float64 {
+func calculateActualRevenue(sessionRepo repository.ParkingSessionRepository, paymentRepo repository.PaymentRepository, areaRepo repository.ParkingAreaRepository, start, end time.Time, regional *string) float64 {
 	areas, _ := areaRepo.GetActiveAreas()
 	actualRevenue := 0.0
 
@@ -3886,13 +3988,15 @@ func calculateActualRevenue(sessionRepo repository.ParkingSessionRepository, are
 		}
 
 		sessions, _ := sessionRepo.GetSessionsByArea(area.ID, start, end)
+		uncollected, _ := uncollectedFees(paymentRepo, sessions)
 		for _, session := range sessions {
-			if session.TotalCost == nil {
+			// Voided sessions were refunded and never count as revenue
+			if session.TotalCost == nil || session.SessionStatus == entities.SessionStatusCancelled {
 				continue
 			}
 
 			if session.PaymentStatus == entities.PaymentStatusPaid || session.SessionStatus == entities.SessionStatusCompleted {
-				actualRevenue += *session.TotalCost
+				actualRevenue += collectedCost(session, uncollected)
 			}
 		}
 	}
@@ -3949,6 +4053,7 @@ func (u *adminUsecase) GetActivityLogs(jukirID *uint, areaID *uint, startTime, e
 		}
 
 		platNomor := session.PlatNomor
+		attachments := withDownloadURLs(session.Attachments)
 
 		if !session.CheckinTime.Before(startTime) && session.CheckinTime.Before(endTime) {
 			events = append(events, entities.ActivityLogItem{
@@ -3960,6 +4065,7 @@ func (u *adminUsecase) GetActivityLogs(jukirID *uint, areaID *uint, startTime, e
 				IsManual:    session.IsManualRecord,
 				Jukir:       jukirInfo,
 				Area:        areaInfo,
+				Photos:      attachmentsOfKind(attachments, entities.AttachmentKindCheckin),
 			})
 		}
 
@@ -3973,6 +4079,7 @@ func (u *adminUsecase) GetActivityLogs(jukirID *uint, areaID *uint, startTime, e
 				IsManual:    session.IsManualRecord,
 				Jukir:       jukirInfo,
 				Area:        areaInfo,
+				Photos:      attachmentsOfKind(attachments, entities.AttachmentKindCheckout),
 			})
 		}
 	}
@@ -0,0 +1,385 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// ErrDepositExists is returned when a deposit is recorded twice for the same jukir and day
+var ErrDepositExists = errors.New("a deposit is already recorded for this jukir and date")
+
+const (
+	// defaultOutstandingDays is the period of the outstanding balance report when no dates are given
+	defaultOutstandingDays = 30
+	// maxOutstandingDays caps the period of the outstanding balance report
+	maxOutstandingDays = 366
+)
+
+// CashDepositUsecase reconciles the cash jukirs collect with what they hand over to the office.
+// Expected cash is always taken from the paid cash payments each jukir confirmed that day.
+type CashDepositUsecase interface {
+	RecordDeposit(adminID uint, req *entities.RecordCashDepositRequest) (*entities.CashDeposit, error)
+	UpdateDeposit(depositID uint, req *entities.UpdateCashDepositRequest) (*entities.CashDeposit, error)
+	GetDeposits(filter entities.CashDepositFilter, limit, offset int) ([]entities.CashDeposit, int64, error)
+	GetExpectedCash(jukirID uint, date time.Time) (*entities.ExpectedCashResponse, error)
+	GetOutstandingBalances(startTime, endTime *time.Time, regional *string, jukirID *uint) (*entities.CashOutstandingResponse, error)
+	ExportOutstandingBalancesXLSX(startTime, endTime *time.Time, regional *string, jukirID *uint) (*bytes.Buffer, error)
+}
+
+type cashDepositUsecase struct {
+	depositRepo repository.CashDepositRepository
+	jukirRepo   repository.JukirRepository
+	paymentRepo repository.PaymentRepository
+}
+
+func NewCashDepositUsecase(depositRepo repository.CashDepositRepository, jukirRepo repository.JukirRepository, paymentRepo repository.PaymentRepository) CashDepositUsecase {
+	return &cashDepositUsecase{
+		depositRepo: depositRepo,
+		jukirRepo:   jukirRepo,
+		paymentRepo: paymentRepo,
+	}
+}
+
+// RecordDeposit records the cash a jukir handed over for one day. The expected amount is
+// snapshotted as it stands now; the outstanding balance report always recomputes it.
+func (u *cashDepositUsecase) RecordDeposit(adminID uint, req *entities.RecordCashDepositRequest) (*entities.CashDeposit, error) {
+	jukir, err := u.jukirRepo.GetByID(req.JukirID)
+	if err != nil {
+		return nil, errors.New("jukir not found")
+	}
+
+	date, err := time.ParseInLocation("02-01-2006", req.Date, getGMT7Location())
+	if err != nil {
+		return nil, errors.New("invalid date format. Use DD-MM-YYYY")
+	}
+	if date.After(nowGMT7()) {
+		return nil, errors.New("deposit date cannot be in the future")
+	}
+
+	if _, err := u.depositRepo.GetByJukirAndDate(jukir.ID, date); err == nil {
+		return nil, ErrDepositExists
+	}
+
+	expected, _, err := u.expectedCash(jukir.ID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	deposit := &entities.CashDeposit{
+		JukirID:         jukir.ID,
+		Date:            date,
+		ExpectedAmount:  expected,
+		DepositedAmount: req.DepositedAmount,
+		Variance:        req.DepositedAmount - expected,
+		ReceivedBy:      adminID,
+		ReceivedAt:      nowGMT7(),
+		Notes:           req.Notes,
+	}
+	if err := u.depositRepo.Create(deposit); err != nil {
+		// Lost a race with another admin recording the same day
+		if _, getErr := u.depositRepo.GetByJukirAndDate(jukir.ID, date); getErr == nil {
+			return nil, ErrDepositExists
+		}
+		return nil, errors.New("failed to record deposit")
+	}
+	return u.depositRepo.GetByID(deposit.ID)
+}
+
+// UpdateDeposit corrects the amount or notes of a recorded deposit and takes the expected
+// amount again, e.g. after payments of that day were confirmed late
+func (u *cashDepositUsecase) UpdateDeposit(depositID uint, req *entities.UpdateCashDepositRequest) (*entities.CashDeposit, error) {
+	deposit, err := u.depositRepo.GetByID(depositID)
+	if err != nil {
+		return nil, errors.New("deposit not found")
+	}
+
+	expected, _, err := u.expectedCash(deposit.JukirID, deposit.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	deposit.ExpectedAmount = expected
+	deposit.DepositedAmount = req.DepositedAmount
+	deposit.Variance = req.DepositedAmount - expected
+	deposit.Notes = req.Notes
+	if err := u.depositRepo.Update(deposit); err != nil {
+		return nil, errors.New("failed to update deposit")
+	}
+	return u.depositRepo.GetByID(deposit.ID)
+}
+
+func (u *cashDepositUsecase) GetDeposits(filter entities.CashDepositFilter, limit, offset int) ([]entities.CashDeposit, int64, error) {
+	deposits, count, err := u.depositRepo.List(filter, limit, offset)
+	if err != nil {
+		return nil, 0, errors.New("failed to get deposits")
+	}
+	return deposits, count, nil
+}
+
+// GetExpectedCash returns what the jukir should hand over for the day, for the admin at the
+// counter before the cash is counted
+func (u *cashDepositUsecase) GetExpectedCash(jukirID uint, date time.Time) (*entities.ExpectedCashResponse, error) {
+	jukir, err := u.jukirRepo.GetByID(jukirID)
+	if err != nil {
+		return nil, errors.New("jukir not found")
+	}
+
+	expected, count, err := u.expectedCash(jukir.ID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &entities.ExpectedCashResponse{
+		JukirID:        jukir.ID,
+		Date:           date.In(getGMT7Location()).Format("2006-01-02"),
+		ExpectedAmount: expected,
+		PaymentCount:   count,
+	}
+	if deposit, err := u.depositRepo.GetByJukirAndDate(jukir.ID, date.In(getGMT7Location())); err == nil {
+		response.Deposit = deposit
+	}
+	return response, nil
+}
+
+// expectedCash sums the paid cash payments the jukir confirmed on the date (WIB)
+func (u *cashDepositUsecase) expectedCash(jukirID uint, date time.Time) (float64, int, error) {
+	loc := getGMT7Location()
+	date = date.In(loc)
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, loc)
+
+	payments, err := u.paymentRepo.GetJukirCashPayments([]uint{jukirID}, start, start.AddDate(0, 0, 1))
+	if err != nil {
+		return 0, 0, errors.New("failed to get cash payments")
+	}
+	total := 0.0
+	for _, payment := range payments {
+		total += payment.Amount
+	}
+	return total, len(payments), nil
+}
+
+// GetOutstandingBalances compares, per jukir, the cash collected over the period with the
+// deposits recorded for it. Days with cash and no deposit count in full.
+func (u *cashDepositUsecase) GetOutstandingBalances(startTime, endTime *time.Time, regional *string, jukirID *uint) (*entities.CashOutstandingResponse, error) {
+	loc := getGMT7Location()
+
+	var start, end time.Time
+	if startTime != nil && endTime != nil {
+		start = startTime.In(loc)
+		end = endTime.In(loc)
+	} else {
+		end = nowGMT7()
+		start = end.AddDate(0, 0, -(defaultOutstandingDays - 1))
+	}
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, loc)
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, loc)
+
+	if end.Before(start) {
+		return nil, errors.New("end date must be after start date")
+	}
+	if end.Sub(start) >= maxOutstandingDays*24*time.Hour {
+		return nil, fmt.Errorf("date range too large (maximum %d days)", maxOutstandingDays)
+	}
+
+	var jukirIDs []uint
+	if jukirID != nil {
+		jukirIDs = []uint{*jukirID}
+	}
+
+	payments, err := u.paymentRepo.GetJukirCashPayments(jukirIDs, start, end.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, errors.New("failed to get cash payments")
+	}
+	deposits, err := u.depositRepo.ListByDateRange(jukirIDs, start, end)
+	if err != nil {
+		return nil, errors.New("failed to get deposits")
+	}
+
+	type jukirDays struct {
+		balance   entities.JukirOutstandingBalance
+		collected map[string]bool
+		deposited map[string]bool
+	}
+	byJukir := make(map[uint]*jukirDays)
+	get := func(id uint) *jukirDays {
+		if byJukir[id] == nil {
+			byJukir[id] = &jukirDays{
+				balance:   entities.JukirOutstandingBalance{JukirID: id},
+				collected: make(map[string]bool),
+				deposited: make(map[string]bool),
+			}
+		}
+		return byJukir[id]
+	}
+
+	for _, payment := range payments {
+		days := get(*payment.ConfirmedBy)
+		days.balance.ExpectedAmount += payment.Amount
+		days.collected[payment.ConfirmedAt.In(loc).Format("2006-01-02")] = true
+	}
+	for _, deposit := range deposits {
+		days := get(deposit.JukirID)
+		date := deposit.Date.Format("2006-01-02")
+		days.balance.DepositedAmount += deposit.DepositedAmount
+		days.deposited[date] = true
+		if days.balance.LastDeposit == nil || *days.balance.LastDeposit < date {
+			days.balance.LastDeposit = &date
+		}
+	}
+
+	ids := make([]uint, 0, len(byJukir))
+	for id := range byJukir {
+		ids = append(ids, id)
+	}
+	jukirs, err := u.jukirRepo.GetByIDs(ids)
+	if err != nil {
+		return nil, errors.New("failed to get jukirs")
+	}
+
+	response := &entities.CashOutstandingResponse{
+		StartDate: start.Format("2006-01-02"),
+		EndDate:   end.Format("2006-01-02"),
+		Jukirs:    make([]entities.JukirOutstandingBalance, 0, len(jukirs)),
+		Regions:   make([]entities.RegionOutstandingBalance, 0),
+	}
+	regions := make(map[string]*entities.RegionOutstandingBalance)
+	for _, jukir := range jukirs {
+		if regional != nil && jukir.Area.Regional != *regional {
+			continue
+		}
+
+		days := byJukir[jukir.ID]
+		balance := days.balance
+		balance.JukirCode = jukir.JukirCode
+		balance.JukirName = jukir.User.Name
+		balance.AreaName = jukir.Area.Name
+		balance.Regional = jukir.Area.Regional
+		balance.Outstanding = balance.ExpectedAmount - balance.DepositedAmount
+		balance.CollectionDays = len(days.collected)
+		for date := range days.collected {
+			if !days.deposited[date] {
+				balance.UndepositedDays++
+			}
+		}
+		response.Jukirs = append(response.Jukirs, balance)
+
+		region := regions[balance.Regional]
+		if region == nil {
+			region = &entities.RegionOutstandingBalance{Regional: balance.Regional}
+			regions[balance.Regional] = region
+		}
+		region.JukirCount++
+		region.ExpectedAmount += balance.ExpectedAmount
+		region.DepositedAmount += balance.DepositedAmount
+		region.Outstanding += balance.Outstanding
+
+		response.ExpectedAmount += balance.ExpectedAmount
+		response.DepositedAmount += balance.DepositedAmount
+		response.Outstanding += balance.Outstanding
+	}
+
+	// Largest shortfall first
+	sort.Slice(response.Jukirs, func(i, j int) bool {
+		if response.Jukirs[i].Outstanding != response.Jukirs[j].Outstanding {
+			return response.Jukirs[i].Outstanding > response.Jukirs[j].Outstanding
+		}
+		return response.Jukirs[i].JukirCode < response.Jukirs[j].JukirCode
+	})
+	for _, region := range regions {
+		response.Regions = append(response.Regions, *region)
+	}
+	sort.Slice(response.Regions, func(i, j int) bool {
+		return response.Regions[i].Regional < response.Regions[j].Regional
+	})
+
+	return response, nil
+}
+
+// ExportOutstandingBalancesXLSX exports the outstanding balance report with one sheet per jukir
+// and one per region
+func (u *cashDepositUsecase) ExportOutstandingBalancesXLSX(startTime, endTime *time.Time, regional *string, jukirID *uint) (*bytes.Buffer, error) {
+	report, err := u.GetOutstandingBalances(startTime, endTime, regional, jukirID)
+	if err != nil {
+		return nil, err
+	}
+
+	f := excelize.NewFile()
+	defer func() {
+		_ = f.Close()
+	}()
+
+	sheet1 := "Outstanding by Jukir"
+	if err := f.SetSheetName("Sheet1", sheet1); err != nil {
+		return nil, errors.New("failed to rename sheet")
+	}
+	period := fmt.Sprintf("Period: %s to %s", report.StartDate, report.EndDate)
+	f.SetCellValue(sheet1, "A1", "OUTSTANDING CASH DEPOSITS")
+	f.SetCellValue(sheet1, "A2", period)
+
+	headersJukir := []string{"No", "Jukir Code", "Jukir Name", "Area", "Regional", "Expected Cash", "Deposited", "Outstanding", "Collection Days", "Undeposited Days", "Last Deposit"}
+	for i, header := range headersJukir {
+		f.SetCellValue(sheet1, fmt.Sprintf("%c4", 'A'+i), header)
+	}
+	row := 5
+	for i, balance := range report.Jukirs {
+		lastDeposit := "-"
+		if balance.LastDeposit != nil {
+			lastDeposit = *balance.LastDeposit
+		}
+		f.SetCellValue(sheet1, fmt.Sprintf("A%d", row), i+1)
+		f.SetCellValue(sheet1, fmt.Sprintf("B%d", row), balance.JukirCode)
+		f.SetCellValue(sheet1, fmt.Sprintf("C%d", row), balance.JukirName)
+		f.SetCellValue(sheet1, fmt.Sprintf("D%d", row), balance.AreaName)
+		f.SetCellValue(sheet1, fmt.Sprintf("E%d", row), balance.Regional)
+		f.SetCellValue(sheet1, fmt.Sprintf("F%d", row), balance.ExpectedAmount)
+		f.SetCellValue(sheet1, fmt.Sprintf("G%d", row), balance.DepositedAmount)
+		f.SetCellValue(sheet1, fmt.Sprintf("H%d", row), balance.Outstanding)
+		f.SetCellValue(sheet1, fmt.Sprintf("I%d", row), balance.CollectionDays)
+		f.SetCellValue(sheet1, fmt.Sprintf("J%d", row), balance.UndepositedDays)
+		f.SetCellValue(sheet1, fmt.Sprintf("K%d", row), lastDeposit)
+		row++
+	}
+	f.SetCellValue(sheet1, fmt.Sprintf("E%d", row), "Total")
+	f.SetCellValue(sheet1, fmt.Sprintf("F%d", row), report.ExpectedAmount)
+	f.SetCellValue(sheet1, fmt.Sprintf("G%d", row), report.DepositedAmount)
+	f.SetCellValue(sheet1, fmt.Sprintf("H%d", row), report.Outstanding)
+
+	sheet2 := "Outstanding by Region"
+	if _, err := f.NewSheet(sheet2); err != nil {
+		return nil, errors.New("failed to create sheet")
+	}
+	f.SetCellValue(sheet2, "A1", "OUTSTANDING CASH DEPOSITS BY REGION")
+	f.SetCellValue(sheet2, "A2", period)
+
+	headersRegion := []string{"No", "Regional", "Jukirs", "Expected Cash", "Deposited", "Outstanding"}
+	for i, header := range headersRegion {
+		f.SetCellValue(sheet2, fmt.Sprintf("%c4", 'A'+i), header)
+	}
+	row = 5
+	for i, region := range report.Regions {
+		f.SetCellValue(sheet2, fmt.Sprintf("A%d", row), i+1)
+		f.SetCellValue(sheet2, fmt.Sprintf("B%d", row), region.Regional)
+		f.SetCellValue(sheet2, fmt.Sprintf("C%d", row), region.JukirCount)
+		f.SetCellValue(sheet2, fmt.Sprintf("D%d", row), region.ExpectedAmount)
+		f.SetCellValue(sheet2, fmt.Sprintf("E%d", row), region.DepositedAmount)
+		f.SetCellValue(sheet2, fmt.Sprintf("F%d", row), region.Outstanding)
+		row++
+	}
+	f.SetCellValue(sheet2, fmt.Sprintf("B%d", row), "Total")
+	f.SetCellValue(sheet2, fmt.Sprintf("D%d", row), report.ExpectedAmount)
+	f.SetCellValue(sheet2, fmt.Sprintf("E%d", row), report.DepositedAmount)
+	f.SetCellValue(sheet2, fmt.Sprintf("F%d", row), report.Outstanding)
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, errors.New("failed to write Excel file")
+	}
+	return &buf, nil
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"errors"
+)
+
+// GetMySessions is the parking history of a logged-in customer: sessions checked in or out with
+// their token and sessions they claimed. A plate or saved vehicle only narrows that list; sessions
+// of other accounts or without one are never shown, whoever saved the plate.
+func (u *parkingUsecase) GetMySessions(userID uint, filter entities.CustomerSessionFilter, limit, offset int) (*entities.SessionHistoryResponse, error) {
+	if filter.VehicleID != nil {
+		vehicle, err := u.getMyVehicle(userID, *filter.VehicleID)
+		if err != nil {
+			return nil, err
+		}
+		filter.PlatNomor = &vehicle.PlatNomor
+	} else if filter.PlatNomor != nil {
+		platNomor, err := entities.NormalizePlatNomor(*filter.PlatNomor)
+		if err != nil {
+			return nil, err
+		}
+		filter.PlatNomor = &platNomor
+	}
+
+	sessions, count, err := u.sessionRepo.ListByUser(userID, filter, limit, offset)
+	if err != nil {
+		return nil, errors.New("failed to get parking history")
+	}
+	if sessions == nil {
+		sessions = []entities.ParkingSession{}
+	}
+
+	return &entities.SessionHistoryResponse{
+		Sessions: sessions,
+		Count:    count,
+	}, nil
+}
+
+// ClaimSessions links the anonymous sessions behind the given tickets to the customer. Tickets
+// that are invalid, expired or for a session another account already owns are skipped; claiming
+// a session the customer already owns again counts as claimed.
+func (u *parkingUsecase) ClaimSessions(userID uint, tickets []string) (*entities.ClaimSessionsResponse, error) {
+	response := &entities.ClaimSessionsResponse{
+		Claimed: []uint{},
+		Skipped: []entities.ClaimSkippedTicket{},
+	}
+
+	for i, ticket := range tickets {
+		session, err := u.getSessionByTicket(0, ticket)
+		if err != nil {
+			response.Skipped = append(response.Skipped, entities.ClaimSkippedTicket{Index: i, Reason: "invalid or expired ticket"})
+			continue
+		}
+
+		if session.UserID == nil {
+			linked, err := u.sessionRepo.AssignUser(session.ID, userID)
+			if err != nil {
+				return nil, errors.New("failed to claim sessions")
+			}
+			if linked {
+				response.Claimed = append(response.Claimed, session.ID)
+				continue
+			}
+			// Linked by someone else since it was read
+			if session, err = u.sessionRepo.GetByID(session.ID); err != nil {
+				return nil, errors.New("failed to claim sessions")
+			}
+		}
+
+		if session.UserID != nil && *session.UserID == userID {
+			response.Claimed = append(response.Claimed, session.ID)
+			continue
+		}
+		response.Skipped = append(response.Skipped, entities.ClaimSkippedTicket{Index: i, Reason: "session belongs to another account"})
+	}
+
+	return response, nil
+}
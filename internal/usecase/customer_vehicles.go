@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"errors"
+)
+
+func (u *parkingUsecase) GetMyVehicles(userID uint) ([]entities.CustomerVehicle, error) {
+	vehicles, err := u.vehicleRepo.ListByUser(userID)
+	if err != nil {
+		return nil, errors.New("failed to get vehicles")
+	}
+	return vehicles, nil
+}
+
+func (u *parkingUsecase) AddVehicle(userID uint, req *entities.CreateVehicleRequest) (*entities.CustomerVehicle, error) {
+	platNomor, err := entities.NormalizePlatNomor(req.PlatNomor)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureVehicleTypeActive(u.vehicleTypeRepo, req.VehicleType); err != nil {
+		return nil, err
+	}
+	if _, err := u.vehicleRepo.GetByUserAndPlate(userID, platNomor); err == nil {
+		return nil, errors.New("vehicle is already saved")
+	}
+
+	vehicle := &entities.CustomerVehicle{
+		UserID:      userID,
+		PlatNomor:   platNomor,
+		VehicleType: req.VehicleType,
+		Nickname:    req.Nickname,
+	}
+	if err := u.vehicleRepo.Create(vehicle); err != nil {
+		return nil, errors.New("failed to save vehicle")
+	}
+	return vehicle, nil
+}
+
+func (u *parkingUsecase) UpdateVehicle(userID, vehicleID uint, req *entities.UpdateVehicleRequest) (*entities.CustomerVehicle, error) {
+	vehicle, err := u.getMyVehicle(userID, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.VehicleType != nil {
+		if err := ensureVehicleTypeActive(u.vehicleTypeRepo, *req.VehicleType); err != nil {
+			return nil, err
+		}
+		vehicle.VehicleType = *req.VehicleType
+	}
+	if req.Nickname != nil {
+		vehicle.Nickname = *req.Nickname
+	}
+	if err := u.vehicleRepo.Update(vehicle); err != nil {
+		return nil, errors.New("failed to update vehicle")
+	}
+	return vehicle, nil
+}
+
+// DeleteVehicle removes the vehicle from the garage; its sessions stay in the history
+func (u *parkingUsecase) DeleteVehicle(userID, vehicleID uint) error {
+	vehicle, err := u.getMyVehicle(userID, vehicleID)
+	if err != nil {
+		return err
+	}
+	if err := u.vehicleRepo.Delete(vehicle.ID); err != nil {
+		return errors.New("failed to delete vehicle")
+	}
+	return nil
+}
+
+func (u *parkingUsecase) getMyVehicle(userID, vehicleID uint) (*entities.CustomerVehicle, error) {
+	vehicle, err := u.vehicleRepo.GetByID(vehicleID)
+	if err != nil || vehicle.UserID != userID {
+		return nil, errors.New("vehicle not found")
+	}
+	return vehicle, nil
+}
+
+// applySavedVehicle fills the check-in plate and vehicle type from the customer's saved vehicle
+func (u *parkingUsecase) applySavedVehicle(req *entities.CheckinRequest) error {
+	if req.VehicleID == nil {
+		return nil
+	}
+	if req.UserID == nil {
+		return errors.New("log in to check in with a saved vehicle")
+	}
+
+	vehicle, err := u.getMyVehicle(*req.UserID, *req.VehicleID)
+	if err != nil {
+		return err
+	}
+	req.PlatNomor = &vehicle.PlatNomor
+	req.VehicleType = vehicle.VehicleType
+	return nil
+}
@@ -12,6 +12,7 @@ const (
 	EventSessionCreated   EventType = "session_created"
 	EventPaymentConfirmed EventType = "payment_confirmed"
 	EventStatsUpdate      EventType = "stats_update"
+	EventOverstayAlert    EventType = "overstay_alert"
 )
 
 // Event represents a server-sent event
@@ -42,6 +43,16 @@ type PaymentConfirmedEvent struct {
 	ConfirmedAt   string  `json:"confirmed_at"`
 }
 
+// OverstayAlertEvent tells a jukir that a session is past its area's time limit
+type OverstayAlertEvent struct {
+	SessionID   uint   `json:"session_id"`
+	PlatNomor   string `json:"plat_nomor,omitempty"`
+	VehicleType string `json:"vehicle_type"`
+	Reason      string `json:"reason"`
+	CheckinTime string `json:"checkin_time"`
+	Deadline    string `json:"deadline"`
+}
+
 // StatsUpdateEvent represents dashboard statistics update
 type StatsUpdateEvent struct {
 	ActiveSessions    int     `json:"active_sessions"`
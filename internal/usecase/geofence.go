@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrOutsideGeofence is returned when a check-in or checkout is made too far from the parking area
+var ErrOutsideGeofence = errors.New("outside the parking area")
+
+const earthRadiusKM = 6371
+
+// GeofenceConfig controls where check-ins and checkouts are accepted
+type GeofenceConfig struct {
+	DefaultRadius  float64 // meters around the area point, for areas without their own polygon or radius
+	AccuracyMargin float64 // meters of GPS error tolerated beyond the polygon or radius
+}
+
+// GeofenceError reports how far outside the geofence a position was measured
+type GeofenceError struct {
+	Distance float64 // meters; outside the polygon boundary, or from the area point for a radius
+	Allowed  float64 // meters; the radius plus margin, or just the margin for a polygon
+	Polygon  bool
+}
+
+func (e *GeofenceError) Error() string {
+	if e.Polygon {
+		return fmt.Sprintf("you are %.0f meters outside the parking area boundary (at most %.0f meters allowed)", e.Distance, e.Allowed)
+	}
+	return fmt.Sprintf("you are %.0f meters from the parking area; you must be within %.0f meters", e.Distance, e.Allowed)
+}
+
+func (e *GeofenceError) Is(target error) bool {
+	return target == ErrOutsideGeofence
+}
+
+// checkGeofence tests a position against the area's polygon, or else against its radius (or the
+// default radius) around the area point
+func checkGeofence(lat, lng float64, area entities.ParkingArea, config GeofenceConfig) error {
+	if area.Geofence != nil && len(area.Geofence.Coordinates) > 0 {
+		distance := distanceOutsidePolygon(lat, lng, area.Geofence)
+		if distance > config.AccuracyMargin {
+			return &GeofenceError{Distance: distance, Allowed: config.AccuracyMargin, Polygon: true}
+		}
+		return nil
+	}
+
+	radius := config.DefaultRadius
+	if area.GeofenceRadius != nil && *area.GeofenceRadius > 0 {
+		radius = *area.GeofenceRadius
+	}
+	distance := haversineKM(lat, lng, area.Latitude, area.Longitude) * 1000
+	if allowed := radius + config.AccuracyMargin; distance > allowed {
+		return &GeofenceError{Distance: distance, Allowed: allowed}
+	}
+	return nil
+}
+
+// haversineKM is the great-circle distance between two coordinates in kilometers
+func haversineKM(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := (lat2 - lat1) * math.Pi / 180
+	dLng := (lng2 - lng1) * math.Pi / 180
+
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*
+			math.Sin(dLng/2)*math.Sin(dLng/2)
+
+	return earthRadiusKM * 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// distanceOutsidePolygon returns 0 for a position inside the polygon and otherwise the distance
+// in meters to its nearest edge. Parking areas are small, so positions are projected onto a flat
+// plane around the point being tested.
+func distanceOutsidePolygon(lat, lng float64, polygon *entities.GeoPolygon) float64 {
+	metersPerDegLat := earthRadiusKM * 1000 * math.Pi / 180
+	metersPerDegLng := metersPerDegLat * math.Cos(lat*math.Pi/180)
+	project := func(position [2]float64) (float64, float64) {
+		return (position[0] - lng) * metersPerDegLng, (position[1] - lat) * metersPerDegLat
+	}
+
+	// Inside the boundary and not inside any hole
+	inside := ringContainsOrigin(polygon.Coordinates[0], project)
+	for _, hole := range polygon.Coordinates[1:] {
+		if inside && ringContainsOrigin(hole, project) {
+			inside = false
+		}
+	}
+	if inside {
+		return 0
+	}
+
+	nearest := math.Inf(1)
+	for _, ring := range polygon.Coordinates {
+		for i := 0; i+1 < len(ring); i++ {
+			ax, ay := project(ring[i])
+			bx, by := project(ring[i+1])
+			nearest = math.Min(nearest, distanceToSegment(ax, ay, bx, by))
+		}
+	}
+	return nearest
+}
+
+// ringContainsOrigin is the even-odd ray casting test for the projected position (0, 0)
+func ringContainsOrigin(ring [][2]float64, project func([2]float64) (float64, float64)) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := project(ring[i])
+		xj, yj := project(ring[j])
+		if (yi > 0) != (yj > 0) && 0 < (xj-xi)*(0-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// distanceToSegment is the distance from the origin to the segment a-b on the plane
+func distanceToSegment(ax, ay, bx, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	t := 0.0
+	if lengthSq := dx*dx + dy*dy; lengthSq > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/lengthSq))
+	}
+	return math.Hypot(ax+t*dx, ay+t*dy)
+}
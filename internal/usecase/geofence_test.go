@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"errors"
+	"math"
+	"testing"
+)
+
+// A square of about 220 m around a point in Palembang, with a square hole of about 110 m in the
+// middle, and an L-shaped (concave) area next to it
+var (
+	squareWithHole = &entities.GeoPolygon{
+		Type: "Polygon",
+		Coordinates: [][][2]float64{
+			{{104.760, -2.991}, {104.762, -2.991}, {104.762, -2.989}, {104.760, -2.989}, {104.760, -2.991}},
+			{{104.7605, -2.9905}, {104.7615, -2.9905}, {104.7615, -2.9895}, {104.7605, -2.9895}, {104.7605, -2.9905}},
+		},
+	}
+	lShape = &entities.GeoPolygon{
+		Type: "Polygon",
+		Coordinates: [][][2]float64{
+			{{104.770, -2.990}, {104.772, -2.990}, {104.772, -2.989}, {104.771, -2.989}, {104.771, -2.988}, {104.770, -2.988}, {104.770, -2.990}},
+		},
+	}
+)
+
+func TestDistanceOutsidePolygon(t *testing.T) {
+	metersPerDeg := earthRadiusKM * 1000 * math.Pi / 180
+
+	tests := []struct {
+		name     string
+		polygon  *entities.GeoPolygon
+		lat, lng float64
+		want     float64 // meters
+	}{
+		{"inside, between boundary and hole", squareWithHole, -2.9900, 104.7602, 0},
+		{"on the boundary", squareWithHole, -2.9900, 104.7600, 0},
+		{"inside the hole", squareWithHole, -2.9900, 104.7610, 0.0005 * metersPerDeg},
+		{"east of the boundary", squareWithHole, -2.9900, 104.7630, 0.001 * metersPerDeg * math.Cos(2.99*math.Pi/180)},
+		{"south of the boundary", squareWithHole, -2.9920, 104.7610, 0.001 * metersPerDeg},
+		{"past the north-east corner", squareWithHole, -2.9880, 104.7630, math.Hypot(0.001*metersPerDeg*math.Cos(2.988*math.Pi/180), 0.001*metersPerDeg)},
+		{"inside the L", lShape, -2.9895, 104.7715, 0},
+		{"inside the upper arm of the L", lShape, -2.9885, 104.7705, 0},
+		{"in the notch of the L", lShape, -2.9885, 104.7715, 0.0005 * metersPerDeg},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := distanceOutsidePolygon(tt.lat, tt.lng, tt.polygon)
+			if math.Abs(got-tt.want) > 0.5 {
+				t.Errorf("distanceOutsidePolygon(%v, %v) = %.2f m, want %.2f m", tt.lat, tt.lng, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckGeofence(t *testing.T) {
+	radius := 50.0
+	config := GeofenceConfig{DefaultRadius: 100, AccuracyMargin: 20}
+	withPolygon := entities.ParkingArea{Latitude: -2.990, Longitude: 104.761, Geofence: squareWithHole}
+	withRadius := entities.ParkingArea{Latitude: -2.990, Longitude: 104.761, GeofenceRadius: &radius}
+	withDefault := entities.ParkingArea{Latitude: -2.990, Longitude: 104.761}
+
+	tests := []struct {
+		name     string
+		area     entities.ParkingArea
+		lat, lng float64
+		wantOut  bool
+	}{
+		{"inside the polygon", withPolygon, -2.9900, 104.7602, false},
+		{"outside the polygon within the margin", withPolygon, -2.9900, 104.7621, false}, // about 11 m east of the boundary
+		{"outside the polygon past the margin", withPolygon, -2.9900, 104.7630, true},
+		{"polygon wins over the default radius", withPolygon, -2.9900, 104.7610, true}, // the area point, inside the hole
+		{"within the area radius", withRadius, -2.9900, 104.7613, false},
+		{"within radius plus margin", withRadius, -2.9900, 104.7616, false},
+		{"past radius plus margin", withRadius, -2.9900, 104.7620, true},
+		{"within the default radius", withDefault, -2.9900, 104.7618, false},
+		{"past the default radius", withDefault, -2.9900, 104.7622, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkGeofence(tt.lat, tt.lng, tt.area, config)
+			if got := errors.Is(err, ErrOutsideGeofence); got != tt.wantOut {
+				t.Errorf("checkGeofence(%v, %v) = %v, want outside=%v", tt.lat, tt.lng, err, tt.wantOut)
+			}
+			var geofenceErr *GeofenceError
+			if tt.wantOut && (!errors.As(err, &geofenceErr) || geofenceErr.Polygon != (tt.area.Geofence != nil)) {
+				t.Errorf("checkGeofence() error = %#v, want a GeofenceError with Polygon=%v", err, tt.area.Geofence != nil)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/qrcard"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// newQRToken returns a fresh check-in token for a jukir. The random part keeps a new token from
+// being guessed from the jukir code and the time it was issued.
+func newQRToken(jukirCode string) (string, error) {
+	raw := make([]byte, 12)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate QR token: %w", err)
+	}
+	return "QR_" + jukirCode + "_" + hex.EncodeToString(raw), nil
+}
+
+// jukirQRResponse reports the jukir's current token and, while it still works, when the
+// replaced one stops being accepted
+func jukirQRResponse(jukir *entities.Jukir) *entities.JukirQRResponse {
+	response := &entities.JukirQRResponse{
+		QRToken: jukir.QRToken,
+		Area:    jukir.Area.Name,
+		Code:    jukir.JukirCode,
+	}
+	if jukir.PreviousQRTokenExpiresAt != nil && jukir.PreviousQRTokenExpiresAt.After(time.Now()) {
+		response.PreviousTokenExpiresAt = jukir.PreviousQRTokenExpiresAt
+	}
+	return response
+}
+
+func jukirQRCard(jukir *entities.Jukir) qrcard.Card {
+	return qrcard.Card{Token: jukir.QRToken, JukirCode: jukir.JukirCode, AreaName: jukir.Area.Name}
+}
+
+// GetQRImage renders the jukir's own QR card
+func (u *jukirUsecase) GetQRImage(jukirID uint, format qrcard.Format) ([]byte, error) {
+	jukir, err := u.jukirRepo.GetByID(jukirID)
+	if err != nil {
+		return nil, errors.New("jukir not found")
+	}
+	return qrcard.Render(jukirQRCard(jukir), format)
+}
+
+// GetJukirQRImage renders a jukir's QR card for printing
+func (u *adminUsecase) GetJukirQRImage(jukirID uint, format qrcard.Format) ([]byte, error) {
+	jukir, err := u.jukirRepo.GetByID(jukirID)
+	if err != nil {
+		return nil, errors.New("jukir not found")
+	}
+	return qrcard.Render(jukirQRCard(jukir), format)
+}
+
+// RotateJukirQRToken gives a jukir a new QR token, e.g. after a card was photographed and
+// misused. The old token keeps working for the grace period so the new card can be handed out.
+func (u *adminUsecase) RotateJukirQRToken(jukirID uint, req *entities.RotateQRTokenRequest) (*entities.JukirQRResponse, error) {
+	jukir, err := u.jukirRepo.GetByID(jukirID)
+	if err != nil {
+		return nil, errors.New("jukir not found")
+	}
+
+	qrToken, err := newQRToken(jukir.JukirCode)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var previousToken *string
+	var previousExpiresAt *time.Time
+	if req.GracePeriodMinutes > 0 {
+		expiresAt := now.Add(time.Duration(req.GracePeriodMinutes) * time.Minute)
+		previousToken = &jukir.QRToken
+		previousExpiresAt = &expiresAt
+	}
+
+	if err := u.jukirRepo.UpdateQRToken(jukir.ID, qrToken, previousToken, previousExpiresAt, now); err != nil {
+		return nil, errors.New("failed to rotate QR token")
+	}
+
+	jukir.QRToken = qrToken
+	jukir.PreviousQRToken = previousToken
+	jukir.PreviousQRTokenExpiresAt = previousExpiresAt
+	jukir.QRRotatedAt = &now
+	return jukirQRResponse(jukir), nil
+}
+
+// GetJukirQRSheet renders the QR cards of every active jukir in a region as a printable PDF
+func (u *adminUsecase) GetJukirQRSheet(regional string) ([]byte, error) {
+	jukirs, err := u.jukirRepo.GetActiveByRegional(regional)
+	if err != nil {
+		return nil, errors.New("failed to get jukirs")
+	}
+
+	cards := make([]qrcard.Card, 0, len(jukirs))
+	for i := range jukirs {
+		cards = append(cards, jukirQRCard(&jukirs[i]))
+	}
+	return qrcard.RenderSheetPDF(fmt.Sprintf("QR Jukir - %s", regional), cards)
+}
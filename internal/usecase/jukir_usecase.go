@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/qrcard"
 	"be-parkir/internal/repository"
 	"errors"
 	"time"
@@ -12,26 +13,36 @@ type JukirUsecase interface {
 	GetPendingPayments(jukirID uint) ([]entities.PendingPaymentResponse, error)
 	GetActiveSessions(jukirID uint, vehicleType *entities.VehicleType) ([]entities.ActiveSessionResponse, error)
 	GetQRCode(jukirID uint) (*entities.JukirQRResponse, error)
+	GetQRImage(jukirID uint, format qrcard.Format) ([]byte, error)
 	GetDailyReport(jukirID uint, date time.Time) (*entities.DailyReportResponse, error)
 	GetJukirByUserID(userID uint) (*entities.Jukir, error)
 	GetVehicleBreakdown(jukirID uint) (*entities.VehicleBreakdownResponse, error)
+	RequestSessionVoid(jukirID, sessionID uint, req *entities.CreateVoidRequest) (*entities.SessionVoidRequest, error)
 }
 
 type jukirUsecase struct {
-	jukirRepo    repository.JukirRepository
-	areaRepo     repository.ParkingAreaRepository
-	sessionRepo  repository.ParkingSessionRepository
-	paymentRepo  repository.PaymentRepository
-	eventManager *EventManager
+	jukirRepo       repository.JukirRepository
+	areaRepo        repository.ParkingAreaRepository
+	sessionRepo     repository.ParkingSessionRepository
+	paymentRepo     repository.PaymentRepository
+	tariffRepo      repository.TariffPlanRepository
+	holidayRepo     repository.HolidayRepository
+	vehicleTypeRepo repository.VehicleTypeRepository
+	voidRepo        repository.SessionVoidRepository
+	eventManager    *EventManager
 }
 
-func NewJukirUsecase(jukirRepo repository.JukirRepository, areaRepo repository.ParkingAreaRepository, sessionRepo repository.ParkingSessionRepository, paymentRepo repository.PaymentRepository, eventManager *EventManager) JukirUsecase {
+func NewJukirUsecase(jukirRepo repository.JukirRepository, areaRepo repository.ParkingAreaRepository, sessionRepo repository.ParkingSessionRepository, paymentRepo repository.PaymentRepository, tariffRepo repository.TariffPlanRepository, holidayRepo repository.HolidayRepository, vehicleTypeRepo repository.VehicleTypeRepository, voidRepo repository.SessionVoidRepository, eventManager *EventManager) JukirUsecase {
 	return &jukirUsecase{
-		jukirRepo:    jukirRepo,
-		areaRepo:     areaRepo,
-		sessionRepo:  sessionRepo,
-		paymentRepo:  paymentRepo,
-		eventManager: eventManager,
+		jukirRepo:       jukirRepo,
+		areaRepo:        areaRepo,
+		sessionRepo:     sessionRepo,
+		paymentRepo:     paymentRepo,
+		tariffRepo:      tariffRepo,
+		holidayRepo:     holidayRepo,
+		vehicleTypeRepo: vehicleTypeRepo,
+		voidRepo:        voidRepo,
+		eventManager:    eventManager,
 	}
 }
 
@@ -121,20 +132,21 @@ func (u *jukirUsecase) GetActiveSessions(jukirID uint, vehicleType *entities.Veh
 			durationMinutes = 0 // If checkin_time is in future, set duration to 0
 		}
 
-		// Biaya parkir adalah FLAT RATE (tidak per jam) - berbeda untuk mobil dan motor
-		currentCost := session.Area.GetRateByVehicleType(session.VehicleType) // Flat rate berdasarkan jenis kendaraan
-		if currentCost < 0 {
-			currentCost = 0
+		// Biaya berjalan dihitung dari tarif progresif area
+		plan, err := resolveSessionTariffPlan(u.tariffRepo, u.holidayRepo, session.Area, &session)
+		if err != nil {
+			return nil, err
 		}
 
 		activeSessions = append(activeSessions, entities.ActiveSessionResponse{
-			SessionID:   session.ID,
-			CheckinTime: session.CheckinTime,
-			Area:        session.Area.Name,
-			PlatNomor:   session.PlatNomor,       // Include plat_nomor in response
-			HourlyRate:  currentCost,              // Return the actual rate used for this vehicle type
-			Duration:    durationMinutes,
-			CurrentCost: currentCost, // Flat rate, tidak per jam
+			SessionID:     session.ID,
+			CheckinTime:   session.CheckinTime,
+			Area:          session.Area.Name,
+			PlatNomor:     session.PlatNomor, // Include plat_nomor in response
+			HourlyRate:    hourlyRate(plan),
+			FirstHourRate: plan.FirstHourRate,
+			Duration:      durationMinutes,
+			CurrentCost:   plan.CalculateCost(durationMinutes),
 		})
 	}
 
@@ -147,11 +159,7 @@ func (u *jukirUsecase) GetQRCode(jukirID uint) (*entities.JukirQRResponse, error
 		return nil, errors.New("jukir not found")
 	}
 
-	return &entities.JukirQRResponse{
-		QRToken: jukir.QRToken,
-		Area:    jukir.Area.Name,
-		Code:    jukir.JukirCode,
-	}, nil
+	return jukirQRResponse(jukir), nil
 }
 
 func (u *jukirUsecase) GetDailyReport(jukirID uint, date time.Time) (*entities.DailyReportResponse, error) {
@@ -164,6 +172,11 @@ func (u *jukirUsecase) GetDailyReport(jukirID uint, date time.Time) (*entities.D
 		return nil, errors.New("failed to get sessions for date")
 	}
 
+	uncollected, err := uncollectedFees(u.paymentRepo, sessions)
+	if err != nil {
+		return nil, errors.New("failed to get session payments")
+	}
+
 	// Calculate metrics
 	var totalRevenue float64
 	var pendingPayments int64
@@ -175,9 +188,7 @@ func (u *jukirUsecase) GetDailyReport(jukirID uint, date time.Time) (*entities.D
 	for _, session := range sessions {
 		if session.SessionStatus == entities.SessionStatusCompleted {
 			completedSessions++
-			if session.TotalCost != nil {
-				totalRevenue += *session.TotalCost
-			}
+			totalRevenue += collectedCost(session, uncollected)
 		}
 
 		if session.SessionStatus == entities.SessionStatusPendingPayment {
@@ -241,46 +252,24 @@ func (u *jukirUsecase) GetVehicleBreakdown(jukirID uint) (*entities.VehicleBreak
 		return nil, errors.New("failed to get sessions")
 	}
 
-	// Calculate breakdown
+	// Calculate breakdown per registered vehicle type
 	vehiclesIn := 0
 	vehiclesOut := 0
-	vehiclesInMobil := 0
-	vehiclesOutMobil := 0
-	vehiclesInMotor := 0
-	vehiclesOutMotor := 0
-
 	for _, session := range sessions {
-		// Count check-ins
 		vehiclesIn++
-
-		// Count by vehicle type
-		if session.VehicleType == entities.VehicleTypeMobil {
-			vehiclesInMobil++
-			if session.CheckoutTime != nil {
-				vehiclesOutMobil++
-			}
-		} else if session.VehicleType == entities.VehicleTypeMotor {
-			vehiclesInMotor++
-			if session.CheckoutTime != nil {
-				vehiclesOutMotor++
-			}
-		}
-
-		// Count check-outs
 		if session.CheckoutTime != nil {
 			vehiclesOut++
 		}
 	}
 
+	vehiclesByType := make(map[string]entities.VehicleCount)
+	for code, flow := range countVehicleFlow(sessions, listVehicleTypes(u.vehicleTypeRepo)) {
+		vehiclesByType[string(code)] = entities.VehicleCount{In: flow.In, Out: flow.Out}
+	}
+
 	return &entities.VehicleBreakdownResponse{
-		VehiclesIn:  vehiclesIn,
-		VehiclesOut: vehiclesOut,
-		VehiclesByType: map[string]struct {
-			In  int `json:"in"`
-			Out int `json:"out"`
-		}{
-			"mobil": {In: vehiclesInMobil, Out: vehiclesOutMobil},
-			"motor": {In: vehiclesInMotor, Out: vehiclesOutMotor},
-		},
+		VehiclesIn:     vehiclesIn,
+		VehiclesOut:    vehiclesOut,
+		VehiclesByType: vehiclesByType,
 	}, nil
 }
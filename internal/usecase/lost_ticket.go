@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const maxLostTicketCandidates = 20
+
+// FindLostTicketCandidates lists active sessions in the jukir's area that may belong to a
+// customer who lost their ticket, matched by partial plate and vehicle type
+func (u *parkingUsecase) FindLostTicketCandidates(jukirID uint, plateQuery string, vehicleType entities.VehicleType) ([]entities.LostTicketCandidate, error) {
+	jukir, err := u.jukirRepo.GetByID(jukirID)
+	if err != nil {
+		return nil, errors.New("jukir not found")
+	}
+
+	area, err := u.areaRepo.GetByID(jukir.AreaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load parking area: %w", err)
+	}
+
+	sessions, err := u.sessionRepo.SearchActiveByPlate(jukir.AreaID, plateQuery, vehicleType, maxLostTicketCandidates)
+	if err != nil {
+		return nil, errors.New("failed to search active sessions")
+	}
+
+	now := nowGMT7()
+	candidates := make([]entities.LostTicketCandidate, 0, len(sessions))
+	for _, session := range sessions {
+		duration := int(now.Sub(session.CheckinTime).Minutes())
+		if duration < 0 {
+			duration = 0
+		}
+		plan, err := resolveSessionTariffPlan(u.tariffRepo, u.holidayRepo, *area, &session)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates = append(candidates, entities.LostTicketCandidate{
+			SessionID:     session.ID,
+			PlatNomor:     session.PlatNomor,
+			VehicleType:   session.VehicleType,
+			CheckinTime:   session.CheckinTime,
+			Duration:      duration,
+			EstimatedCost: plan.CalculateCost(duration),
+			Penalty:       area.LostTicketPenalty,
+		})
+	}
+	return candidates, nil
+}
+
+// LostTicketCheckout closes a session the customer can no longer prove with a ticket. The jukir
+// picks the session from FindLostTicketCandidates; the area's penalty is charged on its own
+// payment line next to the parking fee, and the session total covers both. Whatever the customer
+// paid at check-in counts toward the fee; the jukir collects the rest in cash.
+func (u *parkingUsecase) LostTicketCheckout(jukirID uint, req *entities.LostTicketCheckoutRequest) (*entities.LostTicketCheckoutResponse, error) {
+	jukir, err := u.jukirRepo.GetByID(jukirID)
+	if err != nil {
+		return nil, errors.New("jukir not found")
+	}
+
+	session, err := u.sessionRepo.GetByID(req.SessionID)
+	if err != nil {
+		return nil, errors.New("session not found")
+	}
+	if session.AreaID != jukir.AreaID {
+		return nil, errors.New("session belongs to a different parking area")
+	}
+	if session.SessionStatus != entities.SessionStatusActive {
+		return nil, errors.New("session is not active")
+	}
+
+	area, err := u.areaRepo.GetByID(session.AreaID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load parking area: %w", err)
+	}
+	if err := u.ensureWithinArea(*req.Latitude, *req.Longitude, *area); err != nil {
+		return nil, err
+	}
+
+	// A QRIS code still open from check-in is expired first so the customer can't pay it on top
+	// of the cash the jukir collects now
+	if session.Payment != nil && session.Payment.PaymentMethod == entities.PaymentMethodQRIS &&
+		session.Payment.Status == entities.PaymentStatusPending {
+		if _, err := u.expirePendingCharges(session.ID); err != nil {
+			return nil, err
+		}
+		// Settling a charge may have updated the session
+		if session, err = u.sessionRepo.GetByID(session.ID); err != nil {
+			return nil, errors.New("session not found")
+		}
+	}
+
+	checkoutTime := nowGMT7()
+	duration := int(checkoutTime.Sub(session.CheckinTime).Minutes())
+	if duration < 0 {
+		duration = 0
+	}
+	plan, err := resolveSessionTariffPlan(u.tariffRepo, u.holidayRepo, *area, session)
+	if err != nil {
+		return nil, err
+	}
+	parkingCost := plan.CalculateCost(duration)
+	penalty := area.LostTicketPenalty
+	totalCost := parkingCost + penalty
+
+	session.CheckoutTime = &checkoutTime
+	session.Duration = &duration
+	session.TotalCost = &totalCost
+	session.SessionStatus = entities.SessionStatusCompleted
+	session.PaymentStatus = entities.PaymentStatusPaid
+	session.LostTicket = true
+
+	err = u.uow.Do(func(repos repository.TxRepositories) error {
+		if err := repos.Sessions.Update(session); err != nil {
+			return fmt.Errorf("failed to update parking session: %w", err)
+		}
+
+		// The parking fee still owed is collected as a line of its own; the check-in line keeps
+		// its amount and collection time
+		if _, err := collectCashBalance(repos, session.ID, jukirID, parkingCost, checkoutTime); err != nil {
+			return err
+		}
+
+		if penalty <= 0 {
+			return nil
+		}
+		if err := repos.Payments.Create(&entities.Payment{
+			SessionID:     session.ID,
+			Kind:          entities.PaymentKindLostTicketPenalty,
+			Amount:        penalty,
+			PaymentMethod: entities.PaymentMethodCash,
+			Status:        entities.PaymentStatusPaid,
+			ConfirmedBy:   &jukirID,
+			ConfirmedAt:   &checkoutTime,
+		}); err != nil {
+			return errors.New("failed to create penalty payment record")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	releaseSlot(u.occupancyRepo, session.AreaID, session.VehicleType)
+
+	u.eventManager.NotifyJukir(jukirID, EventSessionUpdate, SessionUpdateEvent{
+		SessionID:    session.ID,
+		PlatNomor:    platNomorOf(session),
+		VehicleType:  string(session.VehicleType),
+		OldStatus:    string(entities.SessionStatusActive),
+		NewStatus:    string(entities.SessionStatusCompleted),
+		TotalCost:    totalCost,
+		CheckoutTime: checkoutTime.Format(time.RFC3339),
+		CheckinTime:  session.CheckinTime.Format(time.RFC3339),
+	})
+
+	return &entities.LostTicketCheckoutResponse{
+		SessionID:     session.ID,
+		PlatNomor:     platNomorOf(session),
+		VehicleType:   string(session.VehicleType),
+		CheckinTime:   session.CheckinTime,
+		CheckoutTime:  checkoutTime,
+		Duration:      duration,
+		ParkingCost:   parkingCost,
+		Penalty:       penalty,
+		TotalCost:     totalCost,
+		PaymentStatus: string(entities.PaymentStatusPaid),
+	}, nil
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+	"errors"
+	"fmt"
+)
+
+// ErrAreaFull is returned by check-in when an area with the reject policy has no slot left
+var ErrAreaFull = errors.New("area full")
+
+// claimSlot counts a new check-in against the area's occupancy. Over capacity it either rejects
+// with ErrAreaFull or, under the overflow policy, accepts and returns a warning for the jukir.
+func claimSlot(occupancyRepo repository.OccupancyRepository, area entities.ParkingArea, vehicleType entities.VehicleType) (string, error) {
+	counted := true
+	occupied, err := occupancyRepo.Increment(area.ID, vehicleType)
+	if err != nil {
+		// Redis unavailable: fall back to counting active sessions
+		counted = false
+		active, countErr := occupancyRepo.CountActive(area.ID, vehicleType)
+		if countErr != nil {
+			// Don't block check-ins when occupancy can't be read at all
+			return "", nil
+		}
+		occupied = active + 1
+	}
+
+	if !area.HasCapacityLimit(vehicleType) {
+		return "", nil
+	}
+	capacity := int64(*area.GetCapacityByVehicleType(vehicleType))
+	if occupied <= capacity {
+		return "", nil
+	}
+
+	if area.CapacityPolicy == entities.CapacityPolicyOverflow {
+		return fmt.Sprintf("area is over capacity for %s (%d/%d)", vehicleType, occupied, capacity), nil
+	}
+
+	if counted {
+		releaseSlot(occupancyRepo, area.ID, vehicleType)
+	}
+	return "", fmt.Errorf("%w: no %s slots available in %s", ErrAreaFull, vehicleType, area.Name)
+}
+
+// releaseSlot frees a slot after a session leaves the active state. Errors are ignored:
+// the counter is re-seeded from parking_sessions when it expires.
+func releaseSlot(occupancyRepo repository.OccupancyRepository, areaID uint, vehicleType entities.VehicleType) {
+	_ = occupancyRepo.Decrement(areaID, vehicleType)
+}
+
+// areaAvailability reports the remaining slots for every vehicle type the area accepts
+func areaAvailability(occupancyRepo repository.OccupancyRepository, vehicleTypes []entities.VehicleTypeConfig, area entities.ParkingArea) []entities.VehicleAvailability {
+	availability := make([]entities.VehicleAvailability, 0, len(vehicleTypes))
+	for _, vt := range vehicleTypes {
+		if !vt.IsActive || !area.AcceptsVehicleType(vt.Code) {
+			continue
+		}
+
+		occupied, err := occupancyRepo.Get(area.ID, vt.Code)
+		if err != nil {
+			occupied, _ = occupancyRepo.CountActive(area.ID, vt.Code)
+		}
+
+		entry := entities.VehicleAvailability{
+			VehicleType: vt.Code,
+			Occupied:    occupied,
+		}
+		if area.HasCapacityLimit(vt.Code) {
+			capacity := *area.GetCapacityByVehicleType(vt.Code)
+			remaining := int64(capacity) - occupied
+			if remaining < 0 {
+				remaining = 0
+			}
+			entry.Capacity = &capacity
+			entry.Remaining = &remaining
+			entry.IsFull = remaining == 0
+		}
+		availability = append(availability, entry)
+	}
+	return availability
+}
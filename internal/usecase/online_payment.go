@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/paygate"
+	"be-parkir/internal/repository"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+)
+
+var (
+	// ErrPaymentsUnavailable is returned for online payments when no gateway is configured
+	ErrPaymentsUnavailable = errors.New("online payments are not available")
+	// ErrUnknownCharge is returned for callbacks about charges no payment is waiting on
+	ErrUnknownCharge = errors.New("no payment found for this charge")
+)
+
+// PaymentConfig wires the online payment provider into the parking flows
+type PaymentConfig struct {
+	Gateway      paygate.Gateway // nil disables online payments
+	ChargeExpiry time.Duration   // how long a QRIS code can be paid
+}
+
+// createQRISCharge asks the gateway for a QRIS code for the amount. It is called before the
+// database transaction; a charge left behind by a failed transaction just expires unpaid.
+func (u *parkingUsecase) createQRISCharge(amount float64, description string) (*paygate.Charge, error) {
+	if u.paymentConfig.Gateway == nil {
+		return nil, ErrPaymentsUnavailable
+	}
+
+	orderID, err := newOrderID()
+	if err != nil {
+		return nil, errors.New("failed to create payment charge")
+	}
+	charge, err := u.paymentConfig.Gateway.CreateCharge(paygate.ChargeRequest{
+		OrderID:     orderID,
+		Amount:      amount,
+		Method:      paygate.MethodQRIS,
+		Description: description,
+		ExpiresAt:   nowGMT7().Add(u.paymentConfig.ChargeExpiry),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create payment charge: %w", err)
+	}
+	return charge, nil
+}
+
+// attachCharge leaves the payment pending on the charge
+func (u *parkingUsecase) attachCharge(payment *entities.Payment, charge *paygate.Charge) {
+	provider := u.paymentConfig.Gateway.Name()
+	expiresAt := charge.ExpiresAt.In(getGMT7Location())
+	payment.PaymentMethod = entities.PaymentMethodQRIS
+	payment.Status = entities.PaymentStatusPending
+	payment.ConfirmedBy = nil
+	payment.ConfirmedAt = nil
+	payment.GatewayProvider = &provider
+	payment.GatewayChargeID = &charge.ID
+	payment.ChargeAmount = &charge.Amount
+	payment.QRString = &charge.QRString
+	payment.ChargeExpiresAt = &expiresAt
+}
+
+func chargeResponse(payment *entities.Payment) *entities.PaymentChargeResponse {
+	response := &entities.PaymentChargeResponse{
+		PaymentID:     payment.ID,
+		PaymentMethod: payment.PaymentMethod,
+		Status:        payment.Status,
+		Amount:        payment.Amount,
+		ExpiresAt:     payment.ChargeExpiresAt,
+	}
+	if payment.ChargeAmount != nil {
+		response.Amount = *payment.ChargeAmount
+	}
+	if payment.QRString != nil {
+		response.QRString = *payment.QRString
+	}
+	if payment.GatewayProvider != nil {
+		response.Provider = *payment.GatewayProvider
+	}
+	return response
+}
+
+// HandlePaymentCallback applies a signed status callback from the gateway. Callbacks for
+// payments already settled are accepted and ignored, so the provider can retry safely.
+func (u *parkingUsecase) HandlePaymentCallback(body []byte, header http.Header) error {
+	if u.paymentConfig.Gateway == nil {
+		return ErrPaymentsUnavailable
+	}
+
+	charge, err := u.paymentConfig.Gateway.ParseCallback(body, header)
+	if err != nil {
+		return err
+	}
+	payment, err := u.paymentRepo.GetByGatewayChargeID(charge.ID)
+	if err != nil {
+		return ErrUnknownCharge
+	}
+	return u.settleCharge(payment, charge)
+}
+
+// GetPaymentStatus returns the session's latest QRIS charge. A pending charge is checked with
+// the gateway first, in case its callback was lost.
+func (u *parkingUsecase) GetPaymentStatus(ticket string) (*entities.PaymentChargeResponse, error) {
+	session, err := u.getSessionByTicket(0, ticket)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := u.paymentRepo.ListBySessionID(session.ID)
+	if err != nil {
+		return nil, errors.New("payment not found")
+	}
+	var payment *entities.Payment
+	for i := range lines {
+		if !isParkingFeeLine(lines[i]) {
+			continue
+		}
+		if payment == nil || lines[i].GatewayChargeID != nil {
+			payment = &lines[i]
+		}
+	}
+	if payment == nil {
+		return nil, errors.New("payment not found")
+	}
+
+	if payment.Status == entities.PaymentStatusPending && payment.GatewayChargeID != nil && u.paymentConfig.Gateway != nil {
+		charge, err := u.paymentConfig.Gateway.GetCharge(*payment.GatewayChargeID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check payment status: %w", err)
+		}
+		if err := u.settleCharge(payment, charge); err != nil {
+			return nil, err
+		}
+	}
+	return chargeResponse(payment), nil
+}
+
+// settleCharge marks the payment paid once its charge is and tells the jukir. The session is
+// paid, and completed if it was waiting at checkout, once none of its lines is still pending.
+// Charges that expired or failed leave the payment pending; the next checkout replaces them.
+// A charge paid just as it was replaced is still recorded, so no money goes missing.
+func (u *parkingUsecase) settleCharge(payment *entities.Payment, charge *paygate.Charge) error {
+	if charge.Status != paygate.ChargeStatusPaid || payment.Status == entities.PaymentStatusPaid {
+		return nil
+	}
+	if payment.GatewayChargeID == nil || *payment.GatewayChargeID != charge.ID {
+		return ErrUnknownCharge
+	}
+	if payment.ChargeAmount != nil && math.Abs(*payment.ChargeAmount-charge.Amount) > 0.005 {
+		return fmt.Errorf("charge amount %.0f does not match payment amount %.0f", charge.Amount, *payment.ChargeAmount)
+	}
+
+	confirmedAt := nowGMT7()
+	if charge.PaidAt != nil {
+		confirmedAt = charge.PaidAt.In(getGMT7Location())
+	}
+
+	var session *entities.ParkingSession
+	err := u.uow.Do(func(repos repository.TxRepositories) error {
+		payment.Status = entities.PaymentStatusPaid
+		payment.ConfirmedAt = &confirmedAt
+		if err := repos.Payments.Update(payment); err != nil {
+			return fmt.Errorf("failed to update payment record: %w", err)
+		}
+
+		var err error
+		session, err = repos.Sessions.GetByID(payment.SessionID)
+		if err != nil {
+			return errors.New("session not found")
+		}
+		if session.SessionStatus == entities.SessionStatusCancelled {
+			return nil
+		}
+		lines, err := repos.Payments.ListBySessionID(session.ID)
+		if err != nil {
+			return errors.New("failed to get session payments")
+		}
+		for _, line := range lines {
+			if isParkingFeeLine(line) && line.Status == entities.PaymentStatusPending {
+				return nil
+			}
+		}
+		session.PaymentStatus = entities.PaymentStatusPaid
+		if session.SessionStatus == entities.SessionStatusPendingPayment {
+			session.SessionStatus = entities.SessionStatusCompleted
+		}
+		if err := repos.Sessions.Update(session); err != nil {
+			return fmt.Errorf("failed to update parking session: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if session.JukirID != nil {
+		u.eventManager.NotifyJukir(*session.JukirID, EventPaymentConfirmed, PaymentConfirmedEvent{
+			SessionID:     session.ID,
+			PaymentID:     payment.ID,
+			PaymentMethod: string(payment.PaymentMethod),
+			Amount:        charge.Amount,
+			ConfirmedBy:   u.paymentConfig.Gateway.Name(),
+			ConfirmedAt:   confirmedAt.Format(time.RFC3339),
+		})
+	}
+	return nil
+}
+
+// expirePendingCharges ends every charge of the session still waiting for payment, so one new
+// code can replace them at checkout. A charge the customer paid in the meantime is settled
+// instead of expired. Returns the session's payment lines as they stand afterwards.
+func (u *parkingUsecase) expirePendingCharges(sessionID uint) ([]entities.Payment, error) {
+	if u.paymentConfig.Gateway == nil {
+		return nil, ErrPaymentsUnavailable
+	}
+	lines, err := u.paymentRepo.ListBySessionID(sessionID)
+	if err != nil {
+		return nil, errors.New("failed to get session payments")
+	}
+
+	for i := range lines {
+		line := &lines[i]
+		if line.Status != entities.PaymentStatusPending || line.GatewayChargeID == nil {
+			continue
+		}
+		charge, err := u.paymentConfig.Gateway.ExpireCharge(*line.GatewayChargeID)
+		if err != nil && !errors.Is(err, paygate.ErrChargeNotFound) {
+			return nil, fmt.Errorf("failed to expire payment charge: %w", err)
+		}
+		if err == nil && charge.Status == paygate.ChargeStatusPaid {
+			if err := u.settleCharge(line, charge); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		line.Status = entities.PaymentStatusFailed
+		if err := u.paymentRepo.Update(line); err != nil {
+			return nil, fmt.Errorf("failed to update payment record: %w", err)
+		}
+	}
+	return lines, nil
+}
+
+// paidParkingFee sums the parking fee lines of a session that were actually paid
+func paidParkingFee(lines []entities.Payment) float64 {
+	paid := 0.0
+	for _, line := range lines {
+		if isParkingFeeLine(line) && line.Status == entities.PaymentStatusPaid {
+			paid += line.Amount
+		}
+	}
+	return paid
+}
+
+// isParkingFeeLine reports whether the line pays for the parking itself rather than a penalty
+func isParkingFeeLine(payment entities.Payment) bool {
+	return payment.Kind == entities.PaymentKindParking || payment.Kind == entities.PaymentKindParkingBalance
+}
+
+func newOrderID() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("PKR-%s-%s", nowGMT7().Format("20060102150405"), hex.EncodeToString(b)), nil
+}
+
+// SimulatePayment pays a charge on the mock gateway and feeds its callback through the same path
+// as the webhook. Only the mock gateway supports it.
+func (u *parkingUsecase) SimulatePayment(chargeID string) error {
+	mock, ok := u.paymentConfig.Gateway.(*paygate.MockGateway)
+	if !ok {
+		return errors.New("payment simulation is only available with the mock gateway")
+	}
+	body, header, err := mock.Pay(chargeID)
+	if err != nil {
+		return err
+	}
+	return u.HandlePaymentCallback(body, header)
+}
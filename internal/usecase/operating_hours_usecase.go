@@ -0,0 +1,319 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrAreaClosed is returned by check-in when the area is not taking vehicles
+var ErrAreaClosed = errors.New("parking area is closed")
+
+// upcomingExceptionDays is how far ahead schedule exceptions are shown with an area
+const upcomingExceptionDays = 7
+
+type OperatingHoursUsecase interface {
+	GetOperatingHours(areaID uint) (*entities.OperatingHoursResponse, error)
+	SetOperatingHours(areaID uint, req *entities.SetOperatingHoursRequest) (*entities.OperatingHoursResponse, error)
+	CreateScheduleException(areaID uint, req *entities.CreateScheduleExceptionRequest) (*entities.AreaScheduleException, error)
+	DeleteScheduleException(areaID, exceptionID uint) error
+	SyncOperationalStatus() (int, error)
+}
+
+type operatingHoursUsecase struct {
+	areaRepo  repository.ParkingAreaRepository
+	hoursRepo repository.OperatingHoursRepository
+}
+
+func NewOperatingHoursUsecase(areaRepo repository.ParkingAreaRepository, hoursRepo repository.OperatingHoursRepository) OperatingHoursUsecase {
+	return &operatingHoursUsecase{
+		areaRepo:  areaRepo,
+		hoursRepo: hoursRepo,
+	}
+}
+
+func (u *operatingHoursUsecase) GetOperatingHours(areaID uint) (*entities.OperatingHoursResponse, error) {
+	area, err := u.areaRepo.GetByID(areaID)
+	if err != nil {
+		return nil, errors.New("parking area not found")
+	}
+
+	now := nowGMT7()
+	schedules, err := loadSchedules(u.hoursRepo, []uint{area.ID}, now)
+	if err != nil {
+		return nil, errors.New("failed to get operating hours")
+	}
+	return operatingHoursResponse(*area, schedules[area.ID], now), nil
+}
+
+// SetOperatingHours replaces the area's weekly hours and, when given, its override flag, then
+// brings status_operasional in line with the new hours straight away
+func (u *operatingHoursUsecase) SetOperatingHours(areaID uint, req *entities.SetOperatingHoursRequest) (*entities.OperatingHoursResponse, error) {
+	area, err := u.areaRepo.GetByID(areaID)
+	if err != nil {
+		return nil, errors.New("parking area not found")
+	}
+
+	hours := make([]entities.AreaOperatingHours, 0, len(req.Hours))
+	seen := make(map[int]bool, len(req.Hours))
+	for _, h := range req.Hours {
+		if seen[h.DayOfWeek] {
+			return nil, fmt.Errorf("duplicate operating hours for day %d", h.DayOfWeek)
+		}
+		seen[h.DayOfWeek] = true
+		hours = append(hours, entities.AreaOperatingHours{
+			DayOfWeek: h.DayOfWeek,
+			OpenTime:  h.OpenTime,
+			CloseTime: h.CloseTime,
+		})
+	}
+
+	if err := u.hoursRepo.ReplaceWeekly(area.ID, hours); err != nil {
+		return nil, errors.New("failed to save operating hours")
+	}
+	if req.HoursOverride != nil && *req.HoursOverride != area.HoursOverride {
+		area.HoursOverride = *req.HoursOverride
+		if err := u.areaRepo.Update(area); err != nil {
+			return nil, errors.New("failed to update parking area")
+		}
+	}
+
+	return u.syncArea(area)
+}
+
+func (u *operatingHoursUsecase) CreateScheduleException(areaID uint, req *entities.CreateScheduleExceptionRequest) (*entities.AreaScheduleException, error) {
+	area, err := u.areaRepo.GetByID(areaID)
+	if err != nil {
+		return nil, errors.New("parking area not found")
+	}
+
+	date, err := time.ParseInLocation("02-01-2006", req.Date, getGMT7Location())
+	if err != nil {
+		return nil, errors.New("invalid date format. Use DD-MM-YYYY")
+	}
+	if existing, err := u.hoursRepo.ListExceptions([]uint{area.ID}, date, date); err == nil && len(existing) > 0 {
+		return nil, errors.New("schedule exception already exists for this date")
+	}
+
+	exception := &entities.AreaScheduleException{
+		AreaID:   area.ID,
+		Date:     date,
+		IsClosed: req.IsClosed,
+		Reason:   req.Reason,
+	}
+	if !req.IsClosed {
+		exception.OpenTime = req.OpenTime
+		exception.CloseTime = req.CloseTime
+	}
+	if err := u.hoursRepo.CreateException(exception); err != nil {
+		return nil, errors.New("failed to create schedule exception")
+	}
+
+	if _, err := u.syncArea(area); err != nil {
+		return nil, err
+	}
+	return exception, nil
+}
+
+func (u *operatingHoursUsecase) DeleteScheduleException(areaID, exceptionID uint) error {
+	exception, err := u.hoursRepo.GetExceptionByID(exceptionID)
+	if err != nil || exception.AreaID != areaID {
+		return errors.New("schedule exception not found")
+	}
+	if err := u.hoursRepo.DeleteException(exception.ID); err != nil {
+		return errors.New("failed to delete schedule exception")
+	}
+
+	if area, err := u.areaRepo.GetByID(areaID); err == nil {
+		if _, err := u.syncArea(area); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SyncOperationalStatus flips status_operasional between buka and tutup for every active area
+// that follows its operating hours. It returns how many areas changed.
+func (u *operatingHoursUsecase) SyncOperationalStatus() (int, error) {
+	areas, err := u.areaRepo.GetActiveAreas()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get parking areas: %w", err)
+	}
+
+	now := nowGMT7()
+	schedules, err := loadSchedules(u.hoursRepo, nil, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get operating hours: %w", err)
+	}
+
+	changed := 0
+	for _, area := range areas {
+		updated, err := u.syncStatus(area, schedules[area.ID], now)
+		if err != nil {
+			return changed, fmt.Errorf("failed to update status of area %d: %w", area.ID, err)
+		}
+		if updated {
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// syncArea applies the area's schedule to its status right away and returns the result
+func (u *operatingHoursUsecase) syncArea(area *entities.ParkingArea) (*entities.OperatingHoursResponse, error) {
+	now := nowGMT7()
+	schedules, err := loadSchedules(u.hoursRepo, []uint{area.ID}, now)
+	if err != nil {
+		return nil, errors.New("failed to get operating hours")
+	}
+	schedule := schedules[area.ID]
+	if _, err := u.syncStatus(*area, schedule, now); err != nil {
+		return nil, errors.New("failed to update operational status")
+	}
+	return operatingHoursResponse(*area, schedule, now), nil
+}
+
+// syncStatus sets status_operasional to what the schedule says at the given time
+func (u *operatingHoursUsecase) syncStatus(area entities.ParkingArea, schedule entities.OperatingSchedule, at time.Time) (bool, error) {
+	status, ok := scheduledStatus(area, schedule, at)
+	if !ok || area.StatusOperasional == status {
+		return false, nil
+	}
+	return true, u.areaRepo.UpdateStatusOperasional(area.ID, status)
+}
+
+// scheduledStatus is the status_operasional the schedule calls for at the given time. ok is
+// false for areas under maintenance or with hours_override, and for moments the schedule has
+// no say in; those keep the status set by hand.
+func scheduledStatus(area entities.ParkingArea, schedule entities.OperatingSchedule, at time.Time) (status string, ok bool) {
+	if !followsSchedule(area) {
+		return "", false
+	}
+	open, scheduled := schedule.OpenAt(at)
+	if !scheduled {
+		return "", false
+	}
+	if open {
+		return entities.StatusOperasionalBuka, true
+	}
+	return entities.StatusOperasionalTutup, true
+}
+
+// followsSchedule reports whether the area's operating hours apply; areas under maintenance or
+// with hours_override are opened and closed by hand
+func followsSchedule(area entities.ParkingArea) bool {
+	return !area.HoursOverride && area.StatusOperasional != entities.StatusOperasionalMaintenance
+}
+
+// operatingHoursResponse reports the status the area has once the schedule is applied at the
+// given time
+func operatingHoursResponse(area entities.ParkingArea, schedule entities.OperatingSchedule, at time.Time) *entities.OperatingHoursResponse {
+	weekly := schedule.Weekly
+	if weekly == nil {
+		weekly = []entities.AreaOperatingHours{}
+	}
+	status := area.StatusOperasional
+	if scheduled, ok := scheduledStatus(area, schedule, at); ok {
+		status = scheduled
+	}
+
+	return &entities.OperatingHoursResponse{
+		AreaID:            area.ID,
+		StatusOperasional: status,
+		HoursOverride:     area.HoursOverride,
+		IsOpen:            areaOpenAt(area, schedule, at),
+		Weekly:            weekly,
+		Exceptions:        upcomingExceptions(schedule, at),
+	}
+}
+
+// loadSchedules reads the weekly hours and the exceptions from yesterday (for windows running
+// past midnight) to upcomingExceptionDays ahead, per area. A nil areaIDs loads every area.
+func loadSchedules(hoursRepo repository.OperatingHoursRepository, areaIDs []uint, at time.Time) (map[uint]entities.OperatingSchedule, error) {
+	return loadSchedulesBetween(hoursRepo, areaIDs, at.AddDate(0, 0, -1), at.AddDate(0, 0, upcomingExceptionDays))
+}
+
+// loadSchedulesBetween is loadSchedules with the exceptions dated from..to (inclusive)
+func loadSchedulesBetween(hoursRepo repository.OperatingHoursRepository, areaIDs []uint, from, to time.Time) (map[uint]entities.OperatingSchedule, error) {
+	weekly, err := hoursRepo.ListWeekly(areaIDs)
+	if err != nil {
+		return nil, err
+	}
+	exceptions, err := hoursRepo.ListExceptions(areaIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	schedules := make(map[uint]entities.OperatingSchedule)
+	for _, hours := range weekly {
+		schedule := schedules[hours.AreaID]
+		schedule.Weekly = append(schedule.Weekly, hours)
+		schedules[hours.AreaID] = schedule
+	}
+	for _, exception := range exceptions {
+		schedule := schedules[exception.AreaID]
+		schedule.Exceptions = append(schedule.Exceptions, exception)
+		schedules[exception.AreaID] = schedule
+	}
+	return schedules, nil
+}
+
+// upcomingExceptions leaves out the exceptions dated before at, which are only loaded for
+// checking overnight windows
+func upcomingExceptions(schedule entities.OperatingSchedule, at time.Time) []entities.AreaScheduleException {
+	today := at.Format("2006-01-02")
+	upcoming := make([]entities.AreaScheduleException, 0, len(schedule.Exceptions))
+	for _, exception := range schedule.Exceptions {
+		if exception.Date.Format("2006-01-02") >= today {
+			upcoming = append(upcoming, exception)
+		}
+	}
+	return upcoming
+}
+
+// areaOpenAt decides whether the area takes vehicles at the given time: by its schedule, or by
+// status_operasional when the schedule does not apply
+func areaOpenAt(area entities.ParkingArea, schedule entities.OperatingSchedule, at time.Time) bool {
+	if area.Status != entities.AreaStatusActive {
+		return false
+	}
+	if status, ok := scheduledStatus(area, schedule, at); ok {
+		return status == entities.StatusOperasionalBuka
+	}
+	return area.StatusOperasional == entities.StatusOperasionalBuka
+}
+
+// ensureAreaOpen rejects check-ins at an area that is closed at the given time
+func ensureAreaOpen(hoursRepo repository.OperatingHoursRepository, area entities.ParkingArea, at time.Time) error {
+	schedules, err := loadSchedules(hoursRepo, []uint{area.ID}, at)
+	if err != nil {
+		return errors.New("failed to get operating hours")
+	}
+	schedule := schedules[area.ID]
+	if areaOpenAt(area, schedule, at) {
+		return nil
+	}
+
+	switch {
+	case area.Status != entities.AreaStatusActive:
+		return fmt.Errorf("%w: %s is not active", ErrAreaClosed, area.Name)
+	case area.StatusOperasional == entities.StatusOperasionalMaintenance:
+		return fmt.Errorf("%w: %s is under maintenance", ErrAreaClosed, area.Name)
+	}
+	if _, ok := scheduledStatus(area, schedule, at); ok {
+		return fmt.Errorf("%w: %s is outside its operating hours", ErrAreaClosed, area.Name)
+	}
+	return fmt.Errorf("%w: %s is closed", ErrAreaClosed, area.Name)
+}
+
+// getOperatingHours is the area's schedule as shown on the admin area detail
+func (u *adminUsecase) getOperatingHours(area entities.ParkingArea) (*entities.OperatingHoursResponse, error) {
+	now := nowGMT7()
+	schedules, err := loadSchedules(u.hoursRepo, []uint{area.ID}, now)
+	if err != nil {
+		return nil, err
+	}
+	return operatingHoursResponse(area, schedules[area.ID], now), nil
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+	"testing"
+	"time"
+)
+
+type fakePaymentRepo struct {
+	repository.PaymentRepository
+	lines []entities.Payment
+}
+
+func (r *fakePaymentRepo) GetByKindForSessions(kind entities.PaymentKind, sessionIDs []uint) ([]entities.Payment, error) {
+	wanted := make(map[uint]bool, len(sessionIDs))
+	for _, id := range sessionIDs {
+		wanted[id] = true
+	}
+	var lines []entities.Payment
+	for _, line := range r.lines {
+		if line.Kind == kind && wanted[line.SessionID] {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+func TestCollectedCost(t *testing.T) {
+	cost := func(v float64) *float64 { return &v }
+	payments := &fakePaymentRepo{lines: []entities.Payment{
+		{SessionID: 1, Kind: entities.PaymentKindParking, Amount: 3000, Status: entities.PaymentStatusPaid},
+		{SessionID: 1, Kind: entities.PaymentKindParkingBalance, Amount: 6000, Status: entities.PaymentStatusPending},
+		{SessionID: 2, Kind: entities.PaymentKindParking, Amount: 5000, Status: entities.PaymentStatusPending},
+		{SessionID: 3, Kind: entities.PaymentKindParking, Amount: 3000, Status: entities.PaymentStatusPaid},
+		{SessionID: 3, Kind: entities.PaymentKindParkingBalance, Amount: 2000, Status: entities.PaymentStatusPending},
+		{SessionID: 4, Kind: entities.PaymentKindParking, Amount: 4000, Status: entities.PaymentStatusPaid},
+		{SessionID: 4, Kind: entities.PaymentKindLostTicketPenalty, Amount: 10000, Status: entities.PaymentStatusPending},
+	}}
+	sessions := []entities.ParkingSession{
+		{ID: 1, TotalCost: cost(9000), AutoClosed: true, PaymentStatus: entities.PaymentStatusPending},
+		{ID: 2, TotalCost: cost(5000), AutoClosed: true, PaymentStatus: entities.PaymentStatusPending},
+		// checked out normally; a pending balance here is a QRIS charge still open, not written off
+		{ID: 3, TotalCost: cost(5000), PaymentStatus: entities.PaymentStatusPending},
+		{ID: 4, TotalCost: cost(4000), AutoClosed: true, PaymentStatus: entities.PaymentStatusPending},
+		{ID: 5, AutoClosed: true, PaymentStatus: entities.PaymentStatusPending},
+	}
+	want := map[uint]float64{1: 3000, 2: 0, 3: 5000, 4: 4000, 5: 0}
+
+	uncollected, err := uncollectedFees(payments, sessions)
+	if err != nil {
+		t.Fatalf("uncollectedFees: %v", err)
+	}
+	for _, session := range sessions {
+		if got := collectedCost(session, uncollected); got != want[session.ID] {
+			t.Errorf("session %d: collectedCost = %v, want %v", session.ID, got, want[session.ID])
+		}
+	}
+}
+
+func TestOverstayDeadline(t *testing.T) {
+	wib := getGMT7Location()
+	clock := func(day int, hour, minute int) time.Time { return time.Date(2026, 10, day, hour, minute, 0, 0, wib) }
+	minutes := func(v int) *int { return &v }
+	closing := func(v string) *string { return &v }
+	// 2026-10-16 is a Friday
+	evenings := entities.OperatingSchedule{Weekly: []entities.AreaOperatingHours{
+		{DayOfWeek: 5, OpenTime: "18:00", CloseTime: "02:00"},
+	}}
+
+	tests := []struct {
+		name       string
+		area       entities.ParkingArea
+		schedule   entities.OperatingSchedule
+		checkin    time.Time
+		want       time.Time // zero = no limit
+		wantReason entities.OverstayReason
+	}{
+		{"no limit", entities.ParkingArea{}, entities.OperatingSchedule{}, clock(16, 10, 0), time.Time{}, ""},
+		{"max duration", entities.ParkingArea{MaxDuration: minutes(120)}, entities.OperatingSchedule{}, clock(16, 10, 0), clock(16, 12, 0), entities.OverstayReasonMaxDuration},
+		{"closing time later today", entities.ParkingArea{ClosingTime: closing("22:00")}, entities.OperatingSchedule{}, clock(16, 10, 0), clock(16, 22, 0), entities.OverstayReasonClosingTime},
+		{"closing time already passed today", entities.ParkingArea{ClosingTime: closing("22:00")}, entities.OperatingSchedule{}, clock(16, 23, 0), clock(17, 22, 0), entities.OverstayReasonClosingTime},
+		{"earlier of max duration and closing time", entities.ParkingArea{MaxDuration: minutes(600), ClosingTime: closing("17:00")}, entities.OperatingSchedule{}, clock(16, 10, 0), clock(16, 17, 0), entities.OverstayReasonClosingTime},
+		{"operating hours close overnight", entities.ParkingArea{}, evenings, clock(16, 20, 0), clock(17, 2, 0), entities.OverstayReasonClosingTime},
+		{"closing time before the hours close", entities.ParkingArea{ClosingTime: closing("23:00")}, evenings, clock(16, 20, 0), clock(16, 23, 0), entities.OverstayReasonClosingTime},
+		{"hours ignored with override", entities.ParkingArea{HoursOverride: true}, evenings, clock(16, 20, 0), time.Time{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deadline, reason := overstayDeadline(tt.area, tt.schedule, tt.checkin)
+			if !deadline.Equal(tt.want) || reason != tt.wantReason {
+				t.Errorf("overstayDeadline = (%s, %q), want (%s, %q)", deadline, reason, tt.want, tt.wantReason)
+			}
+		})
+	}
+}
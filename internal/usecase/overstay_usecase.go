@@ -0,0 +1,324 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type OverstayUsecase interface {
+	CheckOverstays() (*entities.OverstayRunResult, error)
+	GetOverstayEvents(areaID *uint, action *entities.OverstayAction, limit, offset int) ([]entities.OverstayEvent, int64, error)
+}
+
+type overstayUsecase struct {
+	areaRepo      repository.ParkingAreaRepository
+	overstayRepo  repository.OverstayRepository
+	tariffRepo    repository.TariffPlanRepository
+	holidayRepo   repository.HolidayRepository
+	occupancyRepo repository.OccupancyRepository
+	hoursRepo     repository.OperatingHoursRepository
+	uow           repository.UnitOfWork
+	eventManager  *EventManager
+}
+
+func NewOverstayUsecase(areaRepo repository.ParkingAreaRepository, overstayRepo repository.OverstayRepository, tariffRepo repository.TariffPlanRepository, holidayRepo repository.HolidayRepository, occupancyRepo repository.OccupancyRepository, hoursRepo repository.OperatingHoursRepository, uow repository.UnitOfWork, eventManager *EventManager) OverstayUsecase {
+	return &overstayUsecase{
+		areaRepo:      areaRepo,
+		overstayRepo:  overstayRepo,
+		tariffRepo:    tariffRepo,
+		holidayRepo:   holidayRepo,
+		occupancyRepo: occupancyRepo,
+		hoursRepo:     hoursRepo,
+		uow:           uow,
+		eventManager:  eventManager,
+	}
+}
+
+// CheckOverstays looks for active sessions past their area's maximum duration, its closing time
+// or its closing by the operating hours. Depending on the area's overstay policy each one is
+// either closed by the system or reported to the jukir; both are recorded as an OverstayEvent so
+// a session is only handled once.
+func (u *overstayUsecase) CheckOverstays() (*entities.OverstayRunResult, error) {
+	areas, err := u.areaRepo.GetActiveAreas()
+	if err != nil {
+		return nil, errors.New("failed to get parking areas")
+	}
+
+	result := &entities.OverstayRunResult{}
+	now := nowGMT7()
+	schedules, err := loadSchedules(u.hoursRepo, nil, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get operating hours: %w", err)
+	}
+	for _, area := range areas {
+		schedule := schedules[area.ID]
+		if !hasOverstayLimit(area, schedule) {
+			continue
+		}
+
+		sessions, err := u.overstayRepo.GetUnflaggedActiveSessions(area.ID)
+		if err != nil {
+			return result, fmt.Errorf("failed to get active sessions for area %d: %w", area.ID, err)
+		}
+		if len(sessions) == 0 {
+			continue
+		}
+
+		// Sessions older than the exceptions loaded above need the ones since their check-in
+		earliest := sessions[0].CheckinTime
+		for _, session := range sessions {
+			if session.CheckinTime.Before(earliest) {
+				earliest = session.CheckinTime
+			}
+		}
+		if earliest.Before(now.AddDate(0, 0, -1)) {
+			reloaded, err := loadSchedulesBetween(u.hoursRepo, []uint{area.ID}, earliest.AddDate(0, 0, -1), now.AddDate(0, 0, upcomingExceptionDays))
+			if err != nil {
+				return result, fmt.Errorf("failed to get operating hours for area %d: %w", area.ID, err)
+			}
+			schedule = reloaded[area.ID]
+		}
+
+		for i := range sessions {
+			session := &sessions[i]
+			result.Checked++
+
+			deadline, reason := overstayDeadline(area, schedule, session.CheckinTime)
+			if deadline.IsZero() || now.Before(deadline) {
+				continue
+			}
+
+			if area.OverstayPolicy == entities.OverstayPolicyAutoComplete {
+				if err := u.autoComplete(area, session, deadline, reason, now); err != nil {
+					result.Failed++
+					continue
+				}
+				result.AutoCompleted++
+			} else {
+				if err := u.alert(session, deadline, reason, now); err != nil {
+					result.Failed++
+					continue
+				}
+				result.Alerted++
+			}
+		}
+	}
+	return result, nil
+}
+
+func (u *overstayUsecase) GetOverstayEvents(areaID *uint, action *entities.OverstayAction, limit, offset int) ([]entities.OverstayEvent, int64, error) {
+	events, count, err := u.overstayRepo.List(areaID, action, limit, offset)
+	if err != nil {
+		return nil, 0, errors.New("failed to get overstay events")
+	}
+	return events, count, nil
+}
+
+// autoComplete checks the session out at its deadline, so the customer is not charged for the
+// time after the limit, and marks it as closed by the system. Nobody collected anything, so
+// the fee not yet paid or charged is left as an unconfirmed pending balance line; existing
+// lines, including open QRIS charges, are not touched. Checkout does not take completed
+// sessions, so a balance line left pending is written off (see uncollectedFees).
+func (u *overstayUsecase) autoComplete(area entities.ParkingArea, session *entities.ParkingSession, deadline time.Time, reason entities.OverstayReason, now time.Time) error {
+	duration := int(deadline.Sub(session.CheckinTime).Minutes())
+	if duration < 0 {
+		duration = 0
+	}
+	plan, err := resolveSessionTariffPlan(u.tariffRepo, u.holidayRepo, area, session)
+	if err != nil {
+		return err
+	}
+	totalCost := plan.CalculateCost(duration)
+
+	session.CheckoutTime = &deadline
+	session.Duration = &duration
+	session.TotalCost = &totalCost
+	session.SessionStatus = entities.SessionStatusCompleted
+	session.AutoClosed = true
+
+	err = u.uow.Do(func(repos repository.TxRepositories) error {
+		lines, err := repos.Payments.ListBySessionID(session.ID)
+		if err != nil {
+			return errors.New("failed to get session payments")
+		}
+		owed := 0.0
+		pending := false
+		for _, line := range lines {
+			if !isParkingFeeLine(line) {
+				continue
+			}
+			switch line.Status {
+			case entities.PaymentStatusPaid:
+				owed += line.Amount
+			case entities.PaymentStatusPending:
+				owed += line.Amount
+				pending = true
+			}
+		}
+
+		if balance := totalCost - owed; balance > 0.005 {
+			if err := repos.Payments.Create(&entities.Payment{
+				SessionID:     session.ID,
+				Kind:          entities.PaymentKindParkingBalance,
+				Amount:        balance,
+				PaymentMethod: entities.PaymentMethodCash,
+				Status:        entities.PaymentStatusPending,
+			}); err != nil {
+				return errors.New("failed to create payment record")
+			}
+			pending = true
+		}
+		session.PaymentStatus = entities.PaymentStatusPaid
+		if pending {
+			session.PaymentStatus = entities.PaymentStatusPending
+		}
+
+		if err := repos.Sessions.Update(session); err != nil {
+			return fmt.Errorf("failed to close parking session: %w", err)
+		}
+
+		return repos.Overstays.Create(newOverstayEvent(session, deadline, reason, entities.OverstayActionAutoCompleted, now))
+	})
+	if err != nil {
+		return err
+	}
+	releaseSlot(u.occupancyRepo, session.AreaID, session.VehicleType)
+
+	if session.JukirID != nil {
+		u.eventManager.NotifyJukir(*session.JukirID, EventSessionUpdate, SessionUpdateEvent{
+			SessionID:    session.ID,
+			PlatNomor:    platNomorOf(session),
+			VehicleType:  string(session.VehicleType),
+			OldStatus:    string(entities.SessionStatusActive),
+			NewStatus:    string(entities.SessionStatusCompleted),
+			TotalCost:    totalCost,
+			CheckoutTime: deadline.Format(time.RFC3339),
+			CheckinTime:  session.CheckinTime.Format(time.RFC3339),
+		})
+	}
+	return nil
+}
+
+// alert records the overstay and tells the session's jukir, leaving the session active
+func (u *overstayUsecase) alert(session *entities.ParkingSession, deadline time.Time, reason entities.OverstayReason, now time.Time) error {
+	if err := u.overstayRepo.Create(newOverstayEvent(session, deadline, reason, entities.OverstayActionAlert, now)); err != nil {
+		return err
+	}
+
+	if session.JukirID != nil {
+		u.eventManager.NotifyJukir(*session.JukirID, EventOverstayAlert, OverstayAlertEvent{
+			SessionID:   session.ID,
+			PlatNomor:   platNomorOf(session),
+			VehicleType: string(session.VehicleType),
+			Reason:      string(reason),
+			CheckinTime: session.CheckinTime.Format(time.RFC3339),
+			Deadline:    deadline.Format(time.RFC3339),
+		})
+	}
+	return nil
+}
+
+func newOverstayEvent(session *entities.ParkingSession, deadline time.Time, reason entities.OverstayReason, action entities.OverstayAction, now time.Time) *entities.OverstayEvent {
+	return &entities.OverstayEvent{
+		SessionID:  session.ID,
+		AreaID:     session.AreaID,
+		JukirID:    session.JukirID,
+		Reason:     reason,
+		Action:     action,
+		Deadline:   deadline,
+		DetectedAt: now,
+	}
+}
+
+// hasOverstayLimit reports whether the area sets a maximum duration or closing time, or follows
+// operating hours
+func hasOverstayLimit(area entities.ParkingArea, schedule entities.OperatingSchedule) bool {
+	if (area.MaxDuration != nil && *area.MaxDuration > 0) || (area.ClosingTime != nil && *area.ClosingTime != "") {
+		return true
+	}
+	return followsSchedule(area) && (len(schedule.Weekly) > 0 || len(schedule.Exceptions) > 0)
+}
+
+// overstayDeadline returns the earliest of checkin + max duration, the first closing time after
+// check-in and the first time after check-in the area closes by its operating hours (weekly
+// hours and exceptions, as check-in sees them). The closing time is only an overstay cutoff; it
+// does not close the area for check-in. A zero time means the area sets no limit.
+func overstayDeadline(area entities.ParkingArea, schedule entities.OperatingSchedule, checkinTime time.Time) (time.Time, entities.OverstayReason) {
+	var deadline time.Time
+	var reason entities.OverstayReason
+
+	if area.MaxDuration != nil && *area.MaxDuration > 0 {
+		deadline = checkinTime.Add(time.Duration(*area.MaxDuration) * time.Minute)
+		reason = entities.OverstayReasonMaxDuration
+	}
+
+	checkin := checkinTime.In(getGMT7Location())
+	if area.ClosingTime != nil && *area.ClosingTime != "" {
+		if closing, err := time.Parse("15:04", *area.ClosingTime); err == nil {
+			closesAt := dateGMT7(checkin.Year(), checkin.Month(), checkin.Day(), closing.Hour(), closing.Minute(), 0, 0)
+			if !closesAt.After(checkin) {
+				closesAt = closesAt.AddDate(0, 0, 1)
+			}
+			if deadline.IsZero() || closesAt.Before(deadline) {
+				deadline = closesAt
+				reason = entities.OverstayReasonClosingTime
+			}
+		}
+	}
+
+	if followsSchedule(area) {
+		if closesAt, ok := schedule.NextClosing(checkin); ok {
+			if deadline.IsZero() || closesAt.Before(deadline) {
+				deadline = closesAt
+				reason = entities.OverstayReasonClosingTime
+			}
+		}
+	}
+
+	return deadline, reason
+}
+
+func platNomorOf(session *entities.ParkingSession) string {
+	if session.PlatNomor != nil {
+		return *session.PlatNomor
+	}
+	return ""
+}
+
+// uncollectedFees returns, by session ID, the part of the fee of auto-closed sessions that was
+// never collected: their parking fee lines still pending. Nothing settles these lines later,
+// so the amount is written off and left out of revenue.
+func uncollectedFees(paymentRepo repository.PaymentRepository, sessions []entities.ParkingSession) (map[uint]float64, error) {
+	sessionIDs := make([]uint, 0)
+	for _, session := range sessions {
+		if session.AutoClosed && session.PaymentStatus != entities.PaymentStatusPaid {
+			sessionIDs = append(sessionIDs, session.ID)
+		}
+	}
+
+	uncollected := make(map[uint]float64, len(sessionIDs))
+	for _, kind := range []entities.PaymentKind{entities.PaymentKindParking, entities.PaymentKindParkingBalance} {
+		lines, err := paymentRepo.GetByKindForSessions(kind, sessionIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range lines {
+			if line.Status == entities.PaymentStatusPending {
+				uncollected[line.SessionID] += line.Amount
+			}
+		}
+	}
+	return uncollected, nil
+}
+
+// collectedCost is the total cost of the session without the fee written off when it was
+// auto-closed
+func collectedCost(session entities.ParkingSession, uncollected map[uint]float64) float64 {
+	if session.TotalCost == nil {
+		return 0
+	}
+	return *session.TotalCost - uncollected[session.ID]
+}
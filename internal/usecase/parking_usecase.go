@@ -2,10 +2,13 @@ package usecase
 
 import (
 	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/paygate"
 	"be-parkir/internal/repository"
 	"errors"
 	"fmt"
 	"math"
+	"net/http"
+	"sort"
 	"time"
 )
 
@@ -14,37 +17,80 @@ type ParkingUsecase interface {
 	Checkin(req *entities.CheckinRequest) (*entities.CheckinResponse, error)
 	Checkout(req *entities.CheckoutRequest) (*entities.CheckoutResponse, error)
 	GetActiveSession(qrToken string) (*entities.ActiveSessionResponse, error)
-	GetActiveSessionByID(sessionID uint) (*entities.ActiveSessionResponse, error)
+	GetActiveSessionByID(sessionID uint, ticket string) (*entities.ActiveSessionResponse, error)
 	GetSessionByID(sessionID uint) (*entities.ParkingSession, error)
-	GetHistoryByPlatNomor(platNomor string, limit, offset int) (*entities.SessionHistoryResponse, error)
-	GetHistoryBySession(sessionID uint) (*entities.ParkingSession, error)
+	GetHistoryBySession(sessionID uint, ticket string) (*entities.ParkingSession, error)
 	GetHistoryBySessionIDs(sessionIDs []uint) ([]entities.ParkingSession, error)
+	GetHistoryByTickets(tickets []string) ([]entities.ParkingSession, error)
 	ManualCheckin(jukirID uint, req *entities.ManualCheckinRequest) (*entities.ManualCheckinResponse, error)
 	ManualCheckout(jukirID uint, req *entities.ManualCheckoutRequest) (*entities.ManualCheckoutResponse, error)
+	GetVehicleTypes() ([]entities.VehicleTypeConfig, error)
+	SyncManualRecords(jukirID uint, req *entities.SyncRequest) (*entities.SyncResponse, error)
+	FindLostTicketCandidates(jukirID uint, plateQuery string, vehicleType entities.VehicleType) ([]entities.LostTicketCandidate, error)
+	LostTicketCheckout(jukirID uint, req *entities.LostTicketCheckoutRequest) (*entities.LostTicketCheckoutResponse, error)
+	GetMySessions(userID uint, filter entities.CustomerSessionFilter, limit, offset int) (*entities.SessionHistoryResponse, error)
+	ClaimSessions(userID uint, tickets []string) (*entities.ClaimSessionsResponse, error)
+	GetMyVehicles(userID uint) ([]entities.CustomerVehicle, error)
+	AddVehicle(userID uint, req *entities.CreateVehicleRequest) (*entities.CustomerVehicle, error)
+	UpdateVehicle(userID, vehicleID uint, req *entities.UpdateVehicleRequest) (*entities.CustomerVehicle, error)
+	DeleteVehicle(userID, vehicleID uint) error
+	HandlePaymentCallback(body []byte, header http.Header) error
+	GetPaymentStatus(ticket string) (*entities.PaymentChargeResponse, error)
+	SimulatePayment(chargeID string) error
 }
 
 type parkingUsecase struct {
-	sessionRepo  repository.ParkingSessionRepository
-	areaRepo     repository.ParkingAreaRepository
-	userRepo     repository.UserRepository
-	jukirRepo    repository.JukirRepository
-	paymentRepo  repository.PaymentRepository
-	eventManager *EventManager
+	sessionRepo     repository.ParkingSessionRepository
+	areaRepo        repository.ParkingAreaRepository
+	userRepo        repository.UserRepository
+	jukirRepo       repository.JukirRepository
+	paymentRepo     repository.PaymentRepository
+	tariffRepo      repository.TariffPlanRepository
+	holidayRepo     repository.HolidayRepository
+	vehicleTypeRepo repository.VehicleTypeRepository
+	occupancyRepo   repository.OccupancyRepository
+	syncRepo        repository.SyncRecordRepository
+	passRepo        repository.ParkingPassRepository
+	reservationRepo repository.ReservationRepository
+	hoursRepo       repository.OperatingHoursRepository
+	vehicleRepo     repository.CustomerVehicleRepository
+	uow             repository.UnitOfWork
+	eventManager    *EventManager
+	ticketConfig    TicketConfig
+	geofenceConfig  GeofenceConfig
+	paymentConfig   PaymentConfig
 }
 
-const defaultLocationToleranceKM = 0.3
+// ErrConcurrentUpdate is returned when a session or payment was changed by another request
+// between being read and written, e.g. the customer and the jukir checking out at once
+var ErrConcurrentUpdate = repository.ErrVersionConflict
 
-func NewParkingUsecase(sessionRepo repository.ParkingSessionRepository, areaRepo repository.ParkingAreaRepository, userRepo repository.UserRepository, jukirRepo repository.JukirRepository, paymentRepo repository.PaymentRepository, eventManager *EventManager) ParkingUsecase {
+func NewParkingUsecase(sessionRepo repository.ParkingSessionRepository, areaRepo repository.ParkingAreaRepository, userRepo repository.UserRepository, jukirRepo repository.JukirRepository, paymentRepo repository.PaymentRepository, tariffRepo repository.TariffPlanRepository, holidayRepo repository.HolidayRepository, vehicleTypeRepo repository.VehicleTypeRepository, occupancyRepo repository.OccupancyRepository, syncRepo repository.SyncRecordRepository, passRepo repository.ParkingPassRepository, reservationRepo repository.ReservationRepository, hoursRepo repository.OperatingHoursRepository, vehicleRepo repository.CustomerVehicleRepository, uow repository.UnitOfWork, eventManager *EventManager, ticketConfig TicketConfig, geofenceConfig GeofenceConfig, paymentConfig PaymentConfig) ParkingUsecase {
 	return &parkingUsecase{
-		sessionRepo:  sessionRepo,
-		areaRepo:     areaRepo,
-		userRepo:     userRepo,
-		jukirRepo:    jukirRepo,
-		paymentRepo:  paymentRepo,
-		eventManager: eventManager,
+		sessionRepo:     sessionRepo,
+		areaRepo:        areaRepo,
+		userRepo:        userRepo,
+		jukirRepo:       jukirRepo,
+		paymentRepo:     paymentRepo,
+		tariffRepo:      tariffRepo,
+		holidayRepo:     holidayRepo,
+		vehicleTypeRepo: vehicleTypeRepo,
+		occupancyRepo:   occupancyRepo,
+		syncRepo:        syncRepo,
+		passRepo:        passRepo,
+		reservationRepo: reservationRepo,
+		hoursRepo:       hoursRepo,
+		vehicleRepo:     vehicleRepo,
+		uow:             uow,
+		eventManager:    eventManager,
+		ticketConfig:    ticketConfig,
+		geofenceConfig:  geofenceConfig,
+		paymentConfig:   paymentConfig,
 	}
 }
 
+// GetNearbyAreas lists active areas, nearest first when the caller's position is given, each with
+// its distance, whether it is open and the availability and current rate per vehicle type
 func (u *parkingUsecase) GetNearbyAreas(req *entities.NearbyAreasRequest) (*entities.NearbyAreasResponse, error) {
 	// If latitude and longitude are not provided, return all active areas
 	if req.Latitude == nil || req.Longitude == nil {
@@ -52,6 +98,13 @@ func (u *parkingUsecase) GetNearbyAreas(req *entities.NearbyAreasRequest) (*enti
 		if err != nil {
 			return nil, errors.New("failed to get parking areas")
 		}
+		areas, err = u.filterNearbyAreas(areas, req)
+		if err != nil {
+			return nil, err
+		}
+		if err := u.attachAvailability(areas); err != nil {
+			return nil, err
+		}
 
 		return &entities.NearbyAreasResponse{
 			Areas: areas,
@@ -70,22 +123,79 @@ func (u *parkingUsecase) GetNearbyAreas(req *entities.NearbyAreasRequest) (*enti
 		return nil, errors.New("failed to get nearby areas")
 	}
 
-	// Filter areas by actual distance (more accurate than bounding box)
-	var filteredAreas []entities.ParkingArea
+	// Filter areas by actual distance (more accurate than the geohash cells)
+	var withinRadius []entities.ParkingArea
 	for _, area := range areas {
 		distance := u.calculateDistance(*req.Latitude, *req.Longitude, area.Latitude, area.Longitude)
 		if distance <= radius {
-			filteredAreas = append(filteredAreas, area)
+			distanceM := math.Round(distance * 1000)
+			area.DistanceM = &distanceM
+			withinRadius = append(withinRadius, area)
 		}
 	}
 
+	filteredAreas, err := u.filterNearbyAreas(withinRadius, req)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(filteredAreas, func(i, j int) bool {
+		return *filteredAreas[i].DistanceM < *filteredAreas[j].DistanceM
+	})
+	if err := u.attachAvailability(filteredAreas); err != nil {
+		return nil, err
+	}
+
 	return &entities.NearbyAreasResponse{
 		Areas: filteredAreas,
 		Count: int64(len(filteredAreas)),
 	}, nil
 }
 
+// filterNearbyAreas applies the optional jenis_area, vehicle_type and open_now filters and adds
+// each remaining area's operating hours and whether it is open now
+func (u *parkingUsecase) filterNearbyAreas(areas []entities.ParkingArea, req *entities.NearbyAreasRequest) ([]entities.ParkingArea, error) {
+	areaIDs := make([]uint, len(areas))
+	for i, area := range areas {
+		areaIDs[i] = area.ID
+	}
+	now := nowGMT7()
+	schedules, err := loadSchedules(u.hoursRepo, areaIDs, now)
+	if err != nil {
+		return nil, errors.New("failed to get operating hours")
+	}
+
+	filtered := make([]entities.ParkingArea, 0, len(areas))
+	for _, area := range areas {
+		if req.JenisArea != nil && area.JenisArea != *req.JenisArea {
+			continue
+		}
+		if req.VehicleType != nil && !area.AcceptsVehicleType(*req.VehicleType) {
+			continue
+		}
+		schedule := schedules[area.ID]
+		isOpen := areaOpenAt(area, schedule, now)
+		if req.OpenNow && !isOpen {
+			continue
+		}
+		area.IsOpen = &isOpen
+		area.OperatingHours = schedule.Weekly
+		area.ScheduleExceptions = upcomingExceptions(schedule, now)
+		filtered = append(filtered, area)
+	}
+	return filtered, nil
+}
+
 func (u *parkingUsecase) Checkin(req *entities.CheckinRequest) (*entities.CheckinResponse, error) {
+	if err := u.applySavedVehicle(req); err != nil {
+		return nil, err
+	}
+
+	platNomor, err := entities.NormalizeOptionalPlatNomor(req.PlatNomor)
+	if err != nil {
+		return nil, err
+	}
+	req.PlatNomor = platNomor
+
 	// Get jukir by QR token
 	jukir, err := u.jukirRepo.GetByQRToken(req.QRToken)
 	if err != nil {
@@ -97,6 +207,17 @@ func (u *parkingUsecase) Checkin(req *entities.CheckinRequest) (*entities.Checki
 		return nil, errors.New("jukir is not active")
 	}
 
+	if err := ensureVehicleTypeAccepted(u.vehicleTypeRepo, jukir.Area, req.VehicleType); err != nil {
+		return nil, err
+	}
+
+	// Get current time for check-in
+	checkinTime := nowGMT7()
+
+	if err := ensureAreaOpen(u.hoursRepo, jukir.Area, checkinTime); err != nil {
+		return nil, err
+	}
+
 	// Optional GPS verification (ignored if not provided)
 	if req.Latitude != nil && req.Longitude != nil {
 		// We no longer block QR check-in when coordinates are missing, but if the client still
@@ -106,64 +227,163 @@ func (u *parkingUsecase) Checkin(req *entities.CheckinRequest) (*entities.Checki
 		}
 	}
 
-	// Get current time for check-in
-	checkinTime := nowGMT7()
+	// A reservation already holds a slot for this vehicle; the session takes it over
+	reservation, err := findReservation(u.reservationRepo, req.ReservationID, req.UserID, req.PlatNomor, jukir.AreaID, req.VehicleType, checkinTime)
+	if err != nil {
+		return nil, err
+	}
+	var reservationID *uint
+	var warning string
+	if reservation != nil {
+		reservationID = &reservation.ID
+		if platNomor == nil {
+			// Only the customer who made it gets here without a plate. Reservations keep the
+			// compact form; the session gets the TNKB form
+			reserved, err := entities.NormalizePlatNomor(reservation.PlatNomor)
+			if err == nil {
+				platNomor = &reserved
+			}
+		}
+	} else {
+		warning, err = claimSlot(u.occupancyRepo, jukir.Area, req.VehicleType)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	// Biaya parkir adalah FLAT RATE (bukan per jam) - berbeda untuk mobil dan motor
-	totalCost := jukir.Area.GetRateByVehicleType(req.VehicleType) // Flat rate berdasarkan jenis kendaraan
+	// Biaya minimum (jam pertama) dibayar saat checkin, sisanya dihitung saat checkout.
+	// Plat dengan langganan aktif tidak dikenakan biaya.
+	passID := findValidPass(u.passRepo, platNomor, req.VehicleType, jukir.Area, checkinTime)
+	plan, err := resolveTariffPlan(u.tariffRepo, u.holidayRepo, jukir.Area, req.VehicleType, checkinTime)
+	if err != nil {
+		if reservation == nil {
+			releaseSlot(u.occupancyRepo, jukir.AreaID, req.VehicleType)
+		}
+		return nil, err
+	}
+	if passID != nil {
+		plan = passTariffPlan(jukir.Area, req.VehicleType)
+	}
+	totalCost := plan.CalculateCost(0)
+
+	// Cash is handed to the jukir on the spot; QRIS stays pending until the gateway confirms it
+	paymentMethod := req.PaymentMethod
+	if paymentMethod == "" {
+		paymentMethod = entities.PaymentMethodCash
+	}
+	var charge *paygate.Charge
+	if paymentMethod == entities.PaymentMethodQRIS && totalCost > 0 {
+		charge, err = u.createQRISCharge(totalCost, fmt.Sprintf("Parkir %s", jukir.Area.Name))
+		if err != nil {
+			if reservation == nil {
+				releaseSlot(u.occupancyRepo, jukir.AreaID, req.VehicleType)
+			}
+			return nil, err
+		}
+	}
 
 	// Create parking session - payment is recorded at checkin
 	session := &entities.ParkingSession{
 		JukirID:        &jukir.ID,
+		UserID:         req.UserID, // set when a logged-in customer checks in
 		AreaID:         jukir.AreaID,
 		VehicleType:    req.VehicleType,
-		PlatNomor:      req.PlatNomor, // Optional for QR-based sessions
+		PlatNomor:      platNomor, // Optional for QR-based sessions
 		IsManualRecord: false,
 		CheckinTime:    checkinTime,                // Use GMT+7 timezone
-		TotalCost:      &totalCost,                 // Set cost at checkin (flat rate)
+		TotalCost:      &totalCost,                 // Minimum charge, updated at checkout
 		PaymentStatus:  entities.PaymentStatusPaid, // Payment recorded at checkin
 		SessionStatus:  entities.SessionStatusActive,
+		PassID:         passID,
 	}
 
-	if err := u.sessionRepo.Create(session); err != nil {
-		return nil, errors.New("failed to create parking session")
+	if charge != nil {
+		session.PaymentStatus = entities.PaymentStatusPending
 	}
 
-	// Create payment record - payment is recorded at checkin
-	confirmedAt := nowGMT7()
-	payment := &entities.Payment{
-		SessionID:     session.ID,
-		Amount:        totalCost,
-		PaymentMethod: entities.PaymentMethodCash,
-		Status:        entities.PaymentStatusPaid,
-		ConfirmedBy:   &jukir.ID,
-		ConfirmedAt:   &confirmedAt,
-	}
+	// Session and payment are created together or not at all
+	var payment *entities.Payment
+	err = u.uow.Do(func(repos repository.TxRepositories) error {
+		if err := repos.Sessions.Create(session); err != nil {
+			return errors.New("failed to create parking session")
+		}
 
-	if err := u.paymentRepo.Create(payment); err != nil {
-		return nil, errors.New("failed to create payment record")
+		// Create payment record - payment is recorded at checkin
+		confirmedAt := nowGMT7()
+		payment = &entities.Payment{
+			SessionID:     session.ID,
+			Amount:        totalCost,
+			PaymentMethod: paymentMethod,
+			Status:        entities.PaymentStatusPaid,
+			ConfirmedBy:   &jukir.ID,
+			ConfirmedAt:   &confirmedAt,
+		}
+		if charge != nil {
+			u.attachCharge(payment, charge)
+		}
+		if err := repos.Payments.Create(payment); err != nil {
+			return errors.New("failed to create payment record")
+		}
+		if reservation != nil {
+			return repos.Reservations.Consume(reservation.ID, session.ID, checkinTime)
+		}
+		return nil
+	})
+	if err != nil {
+		if reservation == nil {
+			releaseSlot(u.occupancyRepo, jukir.AreaID, req.VehicleType)
+		}
+		return nil, err
 	}
 
-	return &entities.CheckinResponse{
-		SessionID:   session.ID,
-		CheckinTime: session.CheckinTime,
-		Area:        jukir.Area.Name,
-		HourlyRate:  totalCost, // Return the actual rate used for this vehicle type
-	}, nil
+	// Tiket ditandatangani; wajib dikirim saat checkout dan cek sesi aktif
+	ticket, ticketExpiresAt := u.ticketConfig.issueTicket(session)
+
+	response := &entities.CheckinResponse{
+		SessionID:       session.ID,
+		CheckinTime:     session.CheckinTime,
+		Area:            jukir.Area.Name,
+		HourlyRate:      hourlyRate(plan),
+		FirstHourRate:   plan.FirstHourRate,
+		Warning:         warning,
+		Ticket:          ticket,
+		TicketExpiresAt: ticketExpiresAt,
+		PassID:          passID,
+		ReservationID:   reservationID,
+	}
+	if charge != nil {
+		response.Payment = chargeResponse(payment)
+	}
+	return response, nil
 }
 
 func (u *parkingUsecase) Checkout(req *entities.CheckoutRequest) (*entities.CheckoutResponse, error) {
 	var session *entities.ParkingSession
 	var err error
 
-	// Priority: session_id > plat_nomor > qr_token
-	if req.SessionID != nil && *req.SessionID != 0 {
+	if req.PlatNomor, err = entities.NormalizeOptionalPlatNomor(req.PlatNomor); err != nil {
+		return nil, err
+	}
+
+	// Priority: ticket > session_id > plat_nomor > qr_token. Anything but a ticket is only
+	// accepted during the transition window for clients that predate signed tickets.
+	if req.Ticket != nil && *req.Ticket != "" {
+		session, err = u.getSessionByTicket(0, *req.Ticket)
+		if err != nil {
+			return nil, err
+		}
+		if err := ensureCheckoutAllowed(session); err != nil {
+			return nil, err
+		}
+	} else if !u.ticketConfig.allowsLegacyAccess() {
+		return nil, ErrTicketRequired
+	} else if req.SessionID != nil && *req.SessionID != 0 {
 		session, err = u.sessionRepo.GetByID(*req.SessionID)
 		if err != nil {
 			return nil, errors.New("session not found")
 		}
-		if session.SessionStatus == entities.SessionStatusCompleted {
-			return nil, errors.New("session already completed")
+		if err := ensureCheckoutAllowed(session); err != nil {
+			return nil, err
 		}
 	} else if req.PlatNomor != nil && *req.PlatNomor != "" {
 		session, err = u.sessionRepo.GetActiveByPlatNomor(*req.PlatNomor)
@@ -193,7 +413,7 @@ func (u *parkingUsecase) Checkout(req *entities.CheckoutRequest) (*entities.Chec
 		return nil, errors.New("QR code does not match the check-in location")
 	}
 
-	// Reload area to ensure HourlyRateMobil and HourlyRateMotor are loaded
+	// Reload area to ensure rates for every vehicle type are loaded
 	area, err := u.areaRepo.GetByID(session.AreaID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load parking area: %w", err)
@@ -206,14 +426,53 @@ func (u *parkingUsecase) Checkout(req *entities.CheckoutRequest) (*entities.Chec
 		}
 	}
 
-	// Calculate duration and cost (FLAT RATE, not per hour)
+	// A session already waiting on its QRIS charge keeps the checkout it got; asking again only
+	// replaces the code
+	retry := session.SessionStatus == entities.SessionStatusPendingPayment && session.CheckoutTime != nil &&
+		session.Duration != nil && session.TotalCost != nil
+
+	// Calculate duration and cost based on the area's tariff plan
 	checkoutTime := nowGMT7()
 	duration := int(checkoutTime.Sub(session.CheckinTime).Minutes())
 	if duration < 0 {
 		duration = 0 // Handle edge case
 	}
-	// Biaya parkir adalah FLAT RATE (bukan per jam) - berbeda untuk mobil dan motor
-	totalCost := area.GetRateByVehicleType(session.VehicleType) // Flat rate berdasarkan jenis kendaraan
+	var totalCost float64
+	if retry {
+		checkoutTime = *session.CheckoutTime
+		duration = *session.Duration
+		totalCost = *session.TotalCost
+	} else {
+		plan, err := resolveSessionTariffPlan(u.tariffRepo, u.holidayRepo, *area, session)
+		if err != nil {
+			return nil, err
+		}
+		totalCost = plan.CalculateCost(duration)
+	}
+
+	// Sessions paid by QRIS at check-in are charged the rest by QRIS too and wait for the gateway.
+	// Each charge gets its own payment line; codes still open are expired first so only one is
+	// ever payable, and only what was actually paid counts against the total.
+	qris := session.Payment != nil && session.Payment.PaymentMethod == entities.PaymentMethodQRIS
+	var charge *paygate.Charge
+	var outstanding float64
+	if qris {
+		lines, err := u.expirePendingCharges(session.ID)
+		if err != nil {
+			return nil, err
+		}
+		// Settling a charge may have updated the session
+		if session, err = u.sessionRepo.GetByID(session.ID); err != nil {
+			return nil, errors.New("session not found")
+		}
+		outstanding = totalCost - paidParkingFee(lines)
+		if outstanding > 0.005 {
+			charge, err = u.createQRISCharge(outstanding, fmt.Sprintf("Parkir %s", area.Name))
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	// For QR checkout, payment is automatically confirmed (no pending payment step)
 	confirmedAt := nowGMT7()
@@ -224,38 +483,56 @@ func (u *parkingUsecase) Checkout(req *entities.CheckoutRequest) (*entities.Chec
 	session.TotalCost = &totalCost
 	session.SessionStatus = entities.SessionStatusCompleted
 	session.PaymentStatus = entities.PaymentStatusPaid
-
-	if err := u.sessionRepo.Update(session); err != nil {
-		return nil, fmt.Errorf("failed to update parking session: %w", err)
+	if charge != nil {
+		session.SessionStatus = entities.SessionStatusPendingPayment
+		session.PaymentStatus = entities.PaymentStatusPending
 	}
 
-	// Update existing payment record (payment was already created at checkin)
-	// Get existing payment for this session
-	payment, err := u.paymentRepo.GetBySessionID(session.ID)
-	if err != nil {
-		// If payment doesn't exist (shouldn't happen for QR records, but handle gracefully)
-		// Create new payment record
-		payment = &entities.Payment{
-			SessionID:     session.ID,
-			Amount:        totalCost,
-			PaymentMethod: entities.PaymentMethodCash,
-			Status:        entities.PaymentStatusPaid,
-			ConfirmedBy:   &jukir.ID,
-			ConfirmedAt:   &confirmedAt,
+	// Session and payment are updated together or not at all
+	var payment *entities.Payment
+	err = u.uow.Do(func(repos repository.TxRepositories) error {
+		if err := repos.Sessions.Update(session); err != nil {
+			return fmt.Errorf("failed to update parking session: %w", err)
+		}
+
+		// A customer who checked in anonymously and checks out logged in gets the session
+		if req.UserID != nil && session.UserID == nil {
+			if _, err := repos.Sessions.AssignUser(session.ID, *req.UserID); err != nil {
+				return errors.New("failed to link session to account")
+			}
 		}
-		if err := u.paymentRepo.Create(payment); err != nil {
-			return nil, errors.New("failed to create payment record")
+
+		// QRIS lines are never rewritten; the rest gets a line of its own
+		if qris {
+			if charge == nil {
+				return nil
+			}
+			payment = &entities.Payment{
+				SessionID: session.ID,
+				Kind:      entities.PaymentKindParkingBalance,
+				Amount:    outstanding,
+			}
+			u.attachCharge(payment, charge)
+			if err := repos.Payments.Create(payment); err != nil {
+				return errors.New("failed to create payment record")
+			}
+			return nil
 		}
-	} else {
-		// Update existing payment with checkout information
-		payment.Amount = totalCost
-		payment.Status = entities.PaymentStatusPaid
-		payment.ConfirmedBy = &jukir.ID
-		payment.ConfirmedAt = &confirmedAt
-		if err := u.paymentRepo.Update(payment); err != nil {
-			return nil, errors.New("failed to update payment record")
+
+		// The jukir collects the rest in cash as a line of its own
+		if _, err := collectCashBalance(repos, session.ID, jukir.ID, totalCost, confirmedAt); err != nil {
+			return err
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if retry {
+		// The slot was released by the first checkout
+		return u.checkoutResponse(session, payment, charge), nil
 	}
+	releaseSlot(u.occupancyRepo, session.AreaID, session.VehicleType)
 
 	// Notify jukir about checkout via SSE
 	if session.JukirID != nil {
@@ -269,7 +546,7 @@ func (u *parkingUsecase) Checkout(req *entities.CheckoutRequest) (*entities.Chec
 			PlatNomor:    platNomor,
 			VehicleType:  string(session.VehicleType),
 			OldStatus:    string(entities.SessionStatusActive),
-			NewStatus:    string(entities.SessionStatusCompleted),
+			NewStatus:    string(session.SessionStatus),
 			TotalCost:    totalCost,
 			CheckoutTime: checkoutTime.Format(time.RFC3339),
 			CheckinTime:  session.CheckinTime.Format(time.RFC3339),
@@ -278,13 +555,76 @@ func (u *parkingUsecase) Checkout(req *entities.CheckoutRequest) (*entities.Chec
 		u.eventManager.NotifyJukir(*session.JukirID, EventSessionUpdate, eventData)
 	}
 
-	return &entities.CheckoutResponse{
+	return u.checkoutResponse(session, payment, charge), nil
+}
+
+// ensureCheckoutAllowed accepts active sessions, and sessions paid by QRIS that are waiting on
+// their checkout charge so the customer can ask for a new code. Completed and voided sessions
+// can't be checked out again.
+func ensureCheckoutAllowed(session *entities.ParkingSession) error {
+	switch session.SessionStatus {
+	case entities.SessionStatusActive:
+		return nil
+	case entities.SessionStatusPendingPayment:
+		if session.Payment != nil && session.Payment.PaymentMethod == entities.PaymentMethodQRIS {
+			return nil
+		}
+	case entities.SessionStatusCompleted:
+		return errors.New("session already completed")
+	}
+	return errors.New("session is not active")
+}
+
+// collectCashBalance records the part of the parking fee not yet paid as a cash line confirmed by
+// the jukir at checkout. Earlier lines are left alone, so every collection keeps the time it was
+// handed over and cash deposits are reconciled against the right day. Sessions without any
+// parking line (older records) get one for the whole fee.
+func collectCashBalance(repos repository.TxRepositories, sessionID, jukirID uint, totalCost float64, at time.Time) (*entities.Payment, error) {
+	lines, err := repos.Payments.ListBySessionID(sessionID)
+	if err != nil {
+		return nil, errors.New("failed to get session payments")
+	}
+
+	kind := entities.PaymentKindParking
+	hasParkingLine := false
+	for _, line := range lines {
+		if line.Kind == entities.PaymentKindParking {
+			kind = entities.PaymentKindParkingBalance
+			hasParkingLine = true
+		}
+	}
+
+	balance := totalCost - paidParkingFee(lines)
+	if hasParkingLine && balance <= 0.005 {
+		return nil, nil
+	}
+	payment := &entities.Payment{
+		SessionID:     sessionID,
+		Kind:          kind,
+		Amount:        balance,
+		PaymentMethod: entities.PaymentMethodCash,
+		Status:        entities.PaymentStatusPaid,
+		ConfirmedBy:   &jukirID,
+		ConfirmedAt:   &at,
+	}
+	if err := repos.Payments.Create(payment); err != nil {
+		return nil, errors.New("failed to create payment record")
+	}
+	return payment, nil
+}
+
+func (u *parkingUsecase) checkoutResponse(session *entities.ParkingSession, payment *entities.Payment, charge *paygate.Charge) *entities.CheckoutResponse {
+	response := &entities.CheckoutResponse{
 		SessionID:     session.ID,
-		CheckoutTime:  checkoutTime,
-		Duration:      duration,
-		TotalCost:     totalCost,
-		PaymentStatus: string(entities.PaymentStatusPaid),
-	}, nil
+		CheckoutTime:  *session.CheckoutTime,
+		Duration:      *session.Duration,
+		TotalCost:     *session.TotalCost,
+		PaymentStatus: string(session.PaymentStatus),
+	}
+	if charge != nil {
+		response.Payment = chargeResponse(payment)
+	}
+	return response
 }
 
 func (u *parkingUsecase) GetActiveSession(qrToken string) (*entities.ActiveSessionResponse, error) {
@@ -293,73 +633,67 @@ func (u *parkingUsecase) GetActiveSession(qrToken string) (*entities.ActiveSessi
 		return nil, errors.New("no active parking session found for this QR code")
 	}
 
-	// Calculate duration (handle negative if checkin_time is in future)
-	durationMinutes := int(nowGMT7().Sub(session.CheckinTime).Minutes())
-	if durationMinutes < 0 {
-		durationMinutes = 0
-	}
-	// Biaya parkir adalah FLAT RATE (bukan per jam) - berbeda untuk mobil dan motor
-	currentCost := session.Area.GetRateByVehicleType(session.VehicleType) // Flat rate berdasarkan jenis kendaraan
-
-	return &entities.ActiveSessionResponse{
-		SessionID:   session.ID,
-		CheckinTime: session.CheckinTime,
-		Area:        session.Area.Name,
-		HourlyRate:  currentCost, // Return the actual rate used for this vehicle type
-		Duration:    durationMinutes,
-		CurrentCost: currentCost, // Flat rate
-	}, nil
+	return u.buildActiveSessionResponse(session)
 }
 
-func (u *parkingUsecase) GetActiveSessionByID(sessionID uint) (*entities.ActiveSessionResponse, error) {
-	session, err := u.sessionRepo.GetByID(sessionID)
+func (u *parkingUsecase) GetActiveSessionByID(sessionID uint, ticket string) (*entities.ActiveSessionResponse, error) {
+	session, err := u.getSessionByTicket(sessionID, ticket)
 	if err != nil {
-		return nil, errors.New("session not found")
+		return nil, err
 	}
 
 	if session.SessionStatus != entities.SessionStatusActive {
 		return nil, errors.New("no active parking session found for this session ID")
 	}
 
+	response, err := u.buildActiveSessionResponse(session)
+	if err != nil {
+		return nil, err
+	}
+	if ticket == "" {
+		// Old clients get a ticket to send on their next calls. It expires with the transition
+		// window, so it grants nothing the bare ID doesn't.
+		issued, ticketExpiresAt := u.ticketConfig.issueLegacyTicket(session)
+		response.Ticket = issued
+		response.TicketExpiresAt = &ticketExpiresAt
+	}
+	return response, nil
+}
+
+// buildActiveSessionResponse prices an active session as if it were checked out now
+func (u *parkingUsecase) buildActiveSessionResponse(session *entities.ParkingSession) (*entities.ActiveSessionResponse, error) {
+	// Calculate duration (handle negative if checkin_time is in future)
 	durationMinutes := int(nowGMT7().Sub(session.CheckinTime).Minutes())
 	if durationMinutes < 0 {
 		durationMinutes = 0
 	}
 
-	// Biaya parkir adalah FLAT RATE (bukan per jam) - berbeda untuk mobil dan motor
-	currentCost := session.Area.GetRateByVehicleType(session.VehicleType) // Flat rate berdasarkan jenis kendaraan
-
-	return &entities.ActiveSessionResponse{
-		SessionID:   session.ID,
-		CheckinTime: session.CheckinTime,
-		Area:        session.Area.Name,
-		HourlyRate:  currentCost, // Return the actual rate used for this vehicle type
-		Duration:    durationMinutes,
-		CurrentCost: currentCost,
-	}, nil
-}
-
-func (u *parkingUsecase) GetHistoryByPlatNomor(platNomor string, limit, offset int) (*entities.SessionHistoryResponse, error) {
-	sessions, count, err := u.sessionRepo.GetHistoryByPlatNomor(platNomor, limit, offset)
+	plan, err := resolveSessionTariffPlan(u.tariffRepo, u.holidayRepo, session.Area, session)
 	if err != nil {
-		return nil, errors.New("failed to get parking history")
+		return nil, err
 	}
 
-	return &entities.SessionHistoryResponse{
-		Sessions: sessions,
-		Count:    count,
+	return &entities.ActiveSessionResponse{
+		SessionID:     session.ID,
+		CheckinTime:   session.CheckinTime,
+		Area:          session.Area.Name,
+		PlatNomor:     session.PlatNomor,
+		HourlyRate:    hourlyRate(plan),
+		FirstHourRate: plan.FirstHourRate,
+		Duration:      durationMinutes,
+		CurrentCost:   plan.CalculateCost(durationMinutes),
 	}, nil
 }
 
-func (u *parkingUsecase) GetHistoryBySession(sessionID uint) (*entities.ParkingSession, error) {
-	session, err := u.sessionRepo.GetByID(sessionID)
-	if err != nil {
-		return nil, errors.New("session not found")
-	}
-	return session, nil
+func (u *parkingUsecase) GetHistoryBySession(sessionID uint, ticket string) (*entities.ParkingSession, error) {
+	return u.getSessionByTicket(sessionID, ticket)
 }
 
+// GetHistoryBySessionIDs looks sessions up by bare ID, only during the ticket transition window
 func (u *parkingUsecase) GetHistoryBySessionIDs(sessionIDs []uint) ([]entities.ParkingSession, error) {
+	if !u.ticketConfig.allowsLegacyAccess() {
+		return nil, ErrTicketRequired
+	}
 	if len(sessionIDs) == 0 {
 		return []entities.ParkingSession{}, nil
 	}
@@ -377,36 +711,95 @@ func (u *parkingUsecase) GetHistoryBySessionIDs(sessionIDs []uint) ([]entities.P
 	return sessions, nil
 }
 
-// calculateDistance calculates the distance between two coordinates using Haversine formula
-func (u *parkingUsecase) calculateDistance(lat1, lng1, lat2, lng2 float64) float64 {
-	const R = 6371 // Earth's radius in kilometers
+func (u *parkingUsecase) GetHistoryByTickets(tickets []string) ([]entities.ParkingSession, error) {
+	sessions := []entities.ParkingSession{}
+	for _, ticket := range tickets {
+		session, err := u.getSessionByTicket(0, ticket)
+		if err != nil {
+			// Skip invalid or expired tickets, continue with others
+			continue
+		}
+		sessions = append(sessions, *session)
+	}
 
-	dLat := (lat2 - lat1) * math.Pi / 180
-	dLng := (lng2 - lng1) * math.Pi / 180
+	return sessions, nil
+}
 
-	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
-		math.Cos(lat1*math.Pi/180)*math.Cos(lat2*math.Pi/180)*
-			math.Sin(dLng/2)*math.Sin(dLng/2)
+// getSessionByTicket loads the session a ticket was issued for. A sessionID of 0 takes the ID from
+// the ticket; an empty ticket falls back to the bare session ID during the transition window.
+func (u *parkingUsecase) getSessionByTicket(sessionID uint, ticket string) (*entities.ParkingSession, error) {
+	if ticket == "" {
+		if sessionID == 0 || !u.ticketConfig.allowsLegacyAccess() {
+			return nil, ErrTicketRequired
+		}
+		session, err := u.sessionRepo.GetByID(sessionID)
+		if err != nil {
+			return nil, errors.New("session not found")
+		}
+		return session, nil
+	}
 
-	c := 2 * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
-	distance := R * c
+	parsed, err := u.ticketConfig.parseTicket(ticket)
+	if err != nil {
+		return nil, err
+	}
+	if sessionID != 0 && parsed.SessionID != sessionID {
+		return nil, ErrInvalidTicket
+	}
 
-	return distance
+	session, err := u.sessionRepo.GetByID(parsed.SessionID)
+	if err != nil || !parsed.matches(session) {
+		return nil, ErrInvalidTicket
+	}
+	return session, nil
 }
 
-func (u *parkingUsecase) ensureWithinArea(lat, lng float64, area entities.ParkingArea) error {
-	distance := u.calculateDistance(lat, lng, area.Latitude, area.Longitude)
-	if distance > defaultLocationToleranceKM {
-		return fmt.Errorf("you must be within %.0f meters of the parking area", defaultLocationToleranceKM*1000)
+// attachAvailability fills in the remaining slots and the rate in effect now per vehicle type
+// for each area
+func (u *parkingUsecase) attachAvailability(areas []entities.ParkingArea) error {
+	vehicleTypes := listVehicleTypes(u.vehicleTypeRepo)
+	now := nowGMT7()
+	for i := range areas {
+		availability := areaAvailability(u.occupancyRepo, vehicleTypes, areas[i])
+		for j := range availability {
+			plan, err := resolveTariffPlan(u.tariffRepo, u.holidayRepo, areas[i], availability[j].VehicleType, now)
+			if err != nil {
+				return err
+			}
+			availability[j].FirstHourRate = plan.FirstHourRate
+			availability[j].HourlyRate = hourlyRate(plan)
+		}
+		areas[i].Availability = availability
 	}
 	return nil
 }
 
+// calculateDistance calculates the distance between two coordinates using Haversine formula
+func (u *parkingUsecase) calculateDistance(lat1, lng1, lat2, lng2 float64) float64 {
+	return haversineKM(lat1, lng1, lat2, lng2)
+}
+
+// ensureWithinArea rejects positions outside the area's polygon or radius, see checkGeofence
+func (u *parkingUsecase) ensureWithinArea(lat, lng float64, area entities.ParkingArea) error {
+	return checkGeofence(lat, lng, area, u.geofenceConfig)
+}
+
 func (u *parkingUsecase) GetSessionByID(sessionID uint) (*entities.ParkingSession, error) {
 	return u.sessionRepo.GetByID(sessionID)
 }
 
 func (u *parkingUsecase) ManualCheckin(jukirID uint, req *entities.ManualCheckinRequest) (*entities.ManualCheckinResponse, error) {
+	return u.manualCheckin(jukirID, req, nil)
+}
+
+// manualCheckin records a manual check-in. A sync record, when given, is written in the same
+// transaction as the session, so an offline item is applied exactly once.
+func (u *parkingUsecase) manualCheckin(jukirID uint, req *entities.ManualCheckinRequest, record *entities.SyncRecord) (*entities.ManualCheckinResponse, error) {
+	var err error
+	if req.PlatNomor, err = entities.NormalizePlatNomor(req.PlatNomor); err != nil {
+		return nil, err
+	}
+
 	// Get jukir info
 	jukir, err := u.jukirRepo.GetByID(jukirID)
 	if err != nil {
@@ -418,10 +811,18 @@ func (u *parkingUsecase) ManualCheckin(jukirID uint, req *entities.ManualCheckin
 		return nil, errors.New("jukir is not active")
 	}
 
+	if err := ensureVehicleTypeAccepted(u.vehicleTypeRepo, jukir.Area, req.VehicleType); err != nil {
+		return nil, err
+	}
+
 	// Ensure waktu_masuk is in GMT+7 timezone
 	gmt7Loc := getGMT7Location()
 	checkinTime := req.WaktuMasuk.In(gmt7Loc)
 
+	if err := ensureAreaOpen(u.hoursRepo, jukir.Area, checkinTime); err != nil {
+		return nil, err
+	}
+
 	// Validate GPS coordinates (manual check-in requires location confirmation)
 	if req.Latitude == nil || req.Longitude == nil {
 		return nil, errors.New("latitude and longitude are required for manual check-in")
@@ -431,8 +832,38 @@ func (u *parkingUsecase) ManualCheckin(jukirID uint, req *entities.ManualCheckin
 		return nil, err
 	}
 
-	// Biaya parkir adalah FLAT RATE (bukan per jam) - berbeda untuk mobil dan motor
-	totalCost := jukir.Area.GetRateByVehicleType(req.VehicleType) // Flat rate berdasarkan jenis kendaraan
+	// Waktu masuk may be backdated, but the reservation has to still be open now
+	reservation, err := findReservation(u.reservationRepo, nil, nil, &req.PlatNomor, jukir.AreaID, req.VehicleType, nowGMT7())
+	if err != nil {
+		return nil, err
+	}
+	var reservationID *uint
+	var warning string
+	if reservation != nil {
+		reservationID = &reservation.ID
+	} else {
+		warning, err = claimSlot(u.occupancyRepo, jukir.Area, req.VehicleType)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Biaya minimum (jam pertama) dibayar saat checkin, sisanya dihitung saat checkout.
+	// Plat dengan langganan aktif tidak dikenakan biaya.
+	passID := findValidPass(u.passRepo, &req.PlatNomor, req.VehicleType, jukir.Area, checkinTime)
+	plan, err := resolveTariffPlan(u.tariffRepo, u.holidayRepo, jukir.Area, req.VehicleType, checkinTime)
+	if err != nil {
+		if reservation == nil {
+			releaseSlot(u.occupancyRepo, jukir.AreaID, req.VehicleType)
+		}
+		return nil, err
+	}
+	if passID != nil {
+		plan = passTariffPlan(jukir.Area, req.VehicleType)
+	}
+	totalCost := plan.CalculateCost(0)
+
+	photo := sessionPhoto(req.Photo, entities.AttachmentKindCheckin, jukirID)
 
 	// Create manual parking session - payment is recorded at checkin
 	session := &entities.ParkingSession{
@@ -442,28 +873,50 @@ func (u *parkingUsecase) ManualCheckin(jukirID uint, req *entities.ManualCheckin
 		PlatNomor:      &req.PlatNomor,
 		IsManualRecord: true,
 		CheckinTime:    checkinTime,
-		TotalCost:      &totalCost,                 // Set cost at checkin (flat rate)
+		TotalCost:      &totalCost,                 // Minimum charge, updated at checkout
 		PaymentStatus:  entities.PaymentStatusPaid, // Payment recorded at checkin
 		SessionStatus:  entities.SessionStatusActive,
+		PassID:         passID,
 	}
 
-	if err := u.sessionRepo.Create(session); err != nil {
-		return nil, errors.New("failed to create manual parking session")
-	}
-
-	// Create payment record - payment is recorded at checkin
-	confirmedAt := nowGMT7()
-	payment := &entities.Payment{
-		SessionID:     session.ID,
-		Amount:        totalCost,
-		PaymentMethod: entities.PaymentMethodCash,
-		Status:        entities.PaymentStatusPaid,
-		ConfirmedBy:   &jukirID,
-		ConfirmedAt:   &confirmedAt,
-	}
+	// Session and payment are created together or not at all
+	err = u.uow.Do(func(repos repository.TxRepositories) error {
+		if err := repos.Sessions.Create(session); err != nil {
+			return errors.New("failed to create manual parking session")
+		}
 
-	if err := u.paymentRepo.Create(payment); err != nil {
-		return nil, errors.New("failed to create payment record")
+		// Create payment record - payment is recorded at checkin
+		confirmedAt := nowGMT7()
+		payment := &entities.Payment{
+			SessionID:     session.ID,
+			Amount:        totalCost,
+			PaymentMethod: entities.PaymentMethodCash,
+			Status:        entities.PaymentStatusPaid,
+			ConfirmedBy:   &jukirID,
+			ConfirmedAt:   &confirmedAt,
+		}
+		if err := repos.Payments.Create(payment); err != nil {
+			return errors.New("failed to create payment record")
+		}
+		if photo != nil {
+			photo.SessionID = session.ID
+			if err := repos.Attachments.Create(photo); err != nil {
+				return errors.New("failed to save vehicle photo")
+			}
+		}
+		if err := createSyncRecord(repos, record, session.ID); err != nil {
+			return err
+		}
+		if reservation != nil {
+			return repos.Reservations.Consume(reservation.ID, session.ID, nowGMT7())
+		}
+		return nil
+	})
+	if err != nil {
+		if reservation == nil {
+			releaseSlot(u.occupancyRepo, jukir.AreaID, req.VehicleType)
+		}
+		return nil, err
 	}
 
 	platNomor := ""
@@ -472,16 +925,26 @@ func (u *parkingUsecase) ManualCheckin(jukirID uint, req *entities.ManualCheckin
 	}
 
 	return &entities.ManualCheckinResponse{
-		SessionID:   session.ID,
-		PlatNomor:   platNomor,
-		VehicleType: string(session.VehicleType),
-		WaktuMasuk:  session.CheckinTime,
-		Area:        jukir.Area.Name,
-		ParkingCost: totalCost, // Use the actual rate for this vehicle type
+		SessionID:     session.ID,
+		PlatNomor:     platNomor,
+		VehicleType:   string(session.VehicleType),
+		WaktuMasuk:    session.CheckinTime,
+		Area:          jukir.Area.Name,
+		ParkingCost:   totalCost, // Minimum charge for this vehicle type
+		Warning:       warning,
+		PassID:        passID,
+		ReservationID: reservationID,
+		Photo:         photo,
 	}, nil
 }
 
 func (u *parkingUsecase) ManualCheckout(jukirID uint, req *entities.ManualCheckoutRequest) (*entities.ManualCheckoutResponse, error) {
+	return u.manualCheckout(jukirID, req, nil)
+}
+
+// manualCheckout records a manual checkout, writing the sync record, when given, in the same
+// transaction as the session
+func (u *parkingUsecase) manualCheckout(jukirID uint, req *entities.ManualCheckoutRequest, record *entities.SyncRecord) (*entities.ManualCheckoutResponse, error) {
 	// Get session
 	session, err := u.sessionRepo.GetByID(req.SessionID)
 	if err != nil {
@@ -513,7 +976,7 @@ func (u *parkingUsecase) ManualCheckout(jukirID uint, req *entities.ManualChecko
 		return nil, errors.New("parking area information not found for this session")
 	}
 
-	// Reload area to ensure HourlyRateMobil and HourlyRateMotor are loaded
+	// Reload area to ensure rates for every vehicle type are loaded
 	area, err := u.areaRepo.GetByID(session.AreaID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load parking area: %w", err)
@@ -526,13 +989,16 @@ func (u *parkingUsecase) ManualCheckout(jukirID uint, req *entities.ManualChecko
 	gmt7Loc := getGMT7Location()
 	checkoutTime := req.WaktuKeluar.In(gmt7Loc)
 
-	// Calculate duration and cost (FLAT RATE, not per hour)
+	// Calculate duration and cost based on the area's tariff plan
 	duration := int(checkoutTime.Sub(session.CheckinTime).Minutes())
 	if duration < 0 {
 		duration = 0 // Handle edge case
 	}
-	// Biaya parkir adalah FLAT RATE (bukan per jam) - berbeda untuk mobil dan motor
-	totalCost := area.GetRateByVehicleType(session.VehicleType) // Flat rate berdasarkan jenis kendaraan
+	plan, err := resolveSessionTariffPlan(u.tariffRepo, u.holidayRepo, *area, session)
+	if err != nil {
+		return nil, err
+	}
+	totalCost := plan.CalculateCost(duration)
 
 	// For manual checkout, payment is automatically confirmed (no pending payment step)
 	confirmedAt := nowGMT7()
@@ -544,37 +1010,30 @@ func (u *parkingUsecase) ManualCheckout(jukirID uint, req *entities.ManualChecko
 	session.SessionStatus = entities.SessionStatusCompleted
 	session.PaymentStatus = entities.PaymentStatusPaid
 
-	if err := u.sessionRepo.Update(session); err != nil {
-		return nil, fmt.Errorf("failed to update manual parking session: %w", err)
-	}
+	photo := sessionPhoto(req.Photo, entities.AttachmentKindCheckout, jukirID)
 
-	// Update existing payment record (payment was already created at checkin)
-	// Get existing payment for this session
-	payment, err := u.paymentRepo.GetBySessionID(session.ID)
-	if err != nil {
-		// If payment doesn't exist (shouldn't happen for manual records, but handle gracefully)
-		// Create new payment record
-		payment = &entities.Payment{
-			SessionID:     session.ID,
-			Amount:        totalCost,
-			PaymentMethod: entities.PaymentMethodCash,
-			Status:        entities.PaymentStatusPaid,
-			ConfirmedBy:   &jukirID,
-			ConfirmedAt:   &confirmedAt,
+	// Session and payment are updated together or not at all
+	err = u.uow.Do(func(repos repository.TxRepositories) error {
+		if err := repos.Sessions.Update(session); err != nil {
+			return fmt.Errorf("failed to update manual parking session: %w", err)
 		}
-		if err := u.paymentRepo.Create(payment); err != nil {
-			return nil, errors.New("failed to create payment record")
+		if photo != nil {
+			photo.SessionID = session.ID
+			if err := repos.Attachments.Create(photo); err != nil {
+				return errors.New("failed to save vehicle photo")
+			}
 		}
-	} else {
-		// Update existing payment with checkout information
-		payment.Amount = totalCost
-		payment.Status = entities.PaymentStatusPaid
-		payment.ConfirmedBy = &jukirID
-		payment.ConfirmedAt = &confirmedAt
-		if err := u.paymentRepo.Update(payment); err != nil {
-			return nil, errors.New("failed to update payment record")
+
+		// The jukir collects the rest in cash as a line of its own
+		if _, err := collectCashBalance(repos, session.ID, jukirID, totalCost, confirmedAt); err != nil {
+			return err
 		}
+		return createSyncRecord(repos, record, session.ID)
+	})
+	if err != nil {
+		return nil, err
 	}
+	releaseSlot(u.occupancyRepo, session.AreaID, session.VehicleType)
 
 	platNomor := ""
 	if session.PlatNomor != nil {
@@ -604,5 +1063,15 @@ func (u *parkingUsecase) ManualCheckout(jukirID uint, req *entities.ManualChecko
 		Duration:      duration,
 		TotalCost:     totalCost,
 		PaymentStatus: string(entities.PaymentStatusPaid),
+		Photo:         photo,
 	}, nil
 }
+
+// GetVehicleTypes returns the vehicle types that can currently be checked in
+func (u *parkingUsecase) GetVehicleTypes() ([]entities.VehicleTypeConfig, error) {
+	vehicleTypes, err := u.vehicleTypeRepo.List(true)
+	if err != nil {
+		return nil, errors.New("failed to get vehicle types")
+	}
+	return vehicleTypes, nil
+}
@@ -0,0 +1,284 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type PassUsecase interface {
+	CreatePassProduct(req *entities.CreatePassProductRequest) (*entities.PassProduct, error)
+	UpdatePassProduct(productID uint, req *entities.UpdatePassProductRequest) (*entities.PassProduct, error)
+	GetPassProducts(areaID *uint, regional *string, limit, offset int) ([]entities.PassProduct, int64, error)
+	IssuePass(adminID uint, req *entities.IssuePassRequest) (*entities.ParkingPass, error)
+	RenewPass(passID, adminID uint, req *entities.RenewPassRequest) (*entities.ParkingPass, error)
+	SuspendPass(passID uint, req *entities.SuspendPassRequest) (*entities.ParkingPass, error)
+	GetPasses(status *entities.PassStatus, platNomor string, productID *uint, limit, offset int) ([]entities.ParkingPass, int64, error)
+	GetPassByID(passID uint) (*entities.ParkingPass, error)
+}
+
+type passUsecase struct {
+	passRepo        repository.ParkingPassRepository
+	areaRepo        repository.ParkingAreaRepository
+	vehicleTypeRepo repository.VehicleTypeRepository
+	uow             repository.UnitOfWork
+}
+
+func NewPassUsecase(passRepo repository.ParkingPassRepository, areaRepo repository.ParkingAreaRepository, vehicleTypeRepo repository.VehicleTypeRepository, uow repository.UnitOfWork) PassUsecase {
+	return &passUsecase{
+		passRepo:        passRepo,
+		areaRepo:        areaRepo,
+		vehicleTypeRepo: vehicleTypeRepo,
+		uow:             uow,
+	}
+}
+
+func (u *passUsecase) CreatePassProduct(req *entities.CreatePassProductRequest) (*entities.PassProduct, error) {
+	if req.AreaID != nil && req.Regional != nil {
+		return nil, errors.New("a pass product is sold for either an area or a region, not both")
+	}
+	if config, err := u.vehicleTypeRepo.GetByCode(req.VehicleType); err != nil || !config.IsActive {
+		return nil, errors.New("unsupported vehicle type")
+	}
+	if req.AreaID != nil {
+		area, err := u.areaRepo.GetByID(*req.AreaID)
+		if err != nil {
+			return nil, errors.New("parking area not found")
+		}
+		if !area.AcceptsVehicleType(req.VehicleType) {
+			return nil, errors.New("vehicle type is not available in this area")
+		}
+	}
+
+	product := &entities.PassProduct{
+		Name:         req.Name,
+		AreaID:       req.AreaID,
+		Regional:     req.Regional,
+		VehicleType:  req.VehicleType,
+		DurationDays: req.DurationDays,
+		Price:        req.Price,
+		IsActive:     true,
+	}
+	if err := u.passRepo.CreateProduct(product); err != nil {
+		return nil, fmt.Errorf("failed to create pass product: %w", err)
+	}
+	return product, nil
+}
+
+// UpdatePassProduct changes the product for future sales only; passes already issued keep
+// the period they were sold with
+func (u *passUsecase) UpdatePassProduct(productID uint, req *entities.UpdatePassProductRequest) (*entities.PassProduct, error) {
+	product, err := u.passRepo.GetProductByID(productID)
+	if err != nil {
+		return nil, errors.New("pass product not found")
+	}
+
+	if req.Name != nil {
+		product.Name = *req.Name
+	}
+	if req.DurationDays != nil {
+		product.DurationDays = *req.DurationDays
+	}
+	if req.Price != nil {
+		product.Price = *req.Price
+	}
+	if req.IsActive != nil {
+		product.IsActive = *req.IsActive
+	}
+
+	if err := u.passRepo.UpdateProduct(product); err != nil {
+		return nil, fmt.Errorf("failed to update pass product: %w", err)
+	}
+	return product, nil
+}
+
+func (u *passUsecase) GetPassProducts(areaID *uint, regional *string, limit, offset int) ([]entities.PassProduct, int64, error) {
+	products, count, err := u.passRepo.ListProducts(areaID, regional, limit, offset)
+	if err != nil {
+		return nil, 0, errors.New("failed to get pass products")
+	}
+	return products, count, nil
+}
+
+// IssuePass sells a pass for the plate, valid from the start date (today by default) for the
+// product's duration. The sale is recorded as a purchase in the same transaction.
+func (u *passUsecase) IssuePass(adminID uint, req *entities.IssuePassRequest) (*entities.ParkingPass, error) {
+	product, err := u.passRepo.GetProductByID(req.ProductID)
+	if err != nil {
+		return nil, errors.New("pass product not found")
+	}
+	if !product.IsActive {
+		return nil, errors.New("pass product is not on sale")
+	}
+
+	platNomor, err := entities.NormalizePlatNomor(req.PlatNomor)
+	if err != nil {
+		return nil, err
+	}
+	platNomor = compactPlate(platNomor)
+
+	now := nowGMT7()
+	validFrom := dateGMT7(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0)
+	if req.StartDate != nil && *req.StartDate != "" {
+		start, err := time.ParseInLocation("2006-01-02", *req.StartDate, getGMT7Location())
+		if err != nil {
+			return nil, errors.New("invalid start_date format, expected YYYY-MM-DD")
+		}
+		if start.Before(validFrom) {
+			return nil, errors.New("start_date cannot be in the past")
+		}
+		validFrom = start
+	}
+	validUntil := validFrom.AddDate(0, 0, product.DurationDays)
+
+	if current, err := u.passRepo.GetCurrentByPlateAndProduct(platNomor, product.ID, now); err == nil {
+		return nil, fmt.Errorf("plate already has pass %d for this product, renew it instead", current.ID)
+	}
+
+	pass := &entities.ParkingPass{
+		ProductID:   product.ID,
+		PlatNomor:   platNomor,
+		VehicleType: product.VehicleType,
+		HolderName:  req.HolderName,
+		HolderPhone: req.HolderPhone,
+		ValidFrom:   validFrom,
+		ValidUntil:  validUntil,
+		Status:      entities.PassStatusActive,
+		IssuedBy:    adminID,
+	}
+	err = u.uow.Do(func(repos repository.TxRepositories) error {
+		if err := repos.Passes.Create(pass); err != nil {
+			return errors.New("failed to issue pass")
+		}
+		if err := repos.Passes.CreatePurchase(&entities.PassPurchase{
+			PassID:        pass.ID,
+			ProductID:     product.ID,
+			Kind:          entities.PassPurchaseKindPurchase,
+			Amount:        product.Price,
+			PaymentMethod: req.PaymentMethod,
+			PeriodStart:   validFrom,
+			PeriodEnd:     validUntil,
+			RecordedBy:    adminID,
+		}); err != nil {
+			return errors.New("failed to record pass purchase")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u.passRepo.GetByID(pass.ID)
+}
+
+// RenewPass extends the pass by one product period, counted from its current end date or from
+// today if it has already expired
+func (u *passUsecase) RenewPass(passID, adminID uint, req *entities.RenewPassRequest) (*entities.ParkingPass, error) {
+	pass, err := u.passRepo.GetByID(passID)
+	if err != nil {
+		return nil, errors.New("pass not found")
+	}
+	if pass.Status == entities.PassStatusSuspended {
+		return nil, errors.New("pass is suspended")
+	}
+	if !pass.Product.IsActive {
+		return nil, errors.New("pass product is not on sale")
+	}
+
+	now := nowGMT7()
+	periodStart := pass.ValidUntil
+	if periodStart.Before(now) {
+		periodStart = dateGMT7(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0)
+	}
+	periodEnd := periodStart.AddDate(0, 0, pass.Product.DurationDays)
+
+	if pass.ValidUntil.Before(now) {
+		pass.ValidFrom = periodStart
+	}
+	pass.ValidUntil = periodEnd
+	err = u.uow.Do(func(repos repository.TxRepositories) error {
+		if err := repos.Passes.Update(pass); err != nil {
+			return errors.New("failed to renew pass")
+		}
+		if err := repos.Passes.CreatePurchase(&entities.PassPurchase{
+			PassID:        pass.ID,
+			ProductID:     pass.ProductID,
+			Kind:          entities.PassPurchaseKindRenewal,
+			Amount:        pass.Product.Price,
+			PaymentMethod: req.PaymentMethod,
+			PeriodStart:   periodStart,
+			PeriodEnd:     periodEnd,
+			RecordedBy:    adminID,
+		}); err != nil {
+			return errors.New("failed to record pass renewal")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return u.passRepo.GetByID(pass.ID)
+}
+
+// SuspendPass stops the pass from being used at check-in; its validity period is kept
+func (u *passUsecase) SuspendPass(passID uint, req *entities.SuspendPassRequest) (*entities.ParkingPass, error) {
+	pass, err := u.passRepo.GetByID(passID)
+	if err != nil {
+		return nil, errors.New("pass not found")
+	}
+	if pass.Status == entities.PassStatusSuspended {
+		return nil, errors.New("pass is already suspended")
+	}
+
+	suspendedAt := nowGMT7()
+	pass.Status = entities.PassStatusSuspended
+	pass.SuspendedAt = &suspendedAt
+	pass.SuspendReason = &req.Reason
+	if err := u.passRepo.Update(pass); err != nil {
+		return nil, errors.New("failed to suspend pass")
+	}
+	return pass, nil
+}
+
+func (u *passUsecase) GetPasses(status *entities.PassStatus, platNomor string, productID *uint, limit, offset int) ([]entities.ParkingPass, int64, error) {
+	passes, count, err := u.passRepo.List(status, compactPlate(platNomor), productID, limit, offset)
+	if err != nil {
+		return nil, 0, errors.New("failed to get passes")
+	}
+	return passes, count, nil
+}
+
+func (u *passUsecase) GetPassByID(passID uint) (*entities.ParkingPass, error) {
+	pass, err := u.passRepo.GetByID(passID)
+	if err != nil {
+		return nil, errors.New("pass not found")
+	}
+	return pass, nil
+}
+
+// findValidPass returns the ID of a pass covering the plate at check-in, or nil when the plate is
+// empty or has no valid pass for the area. Lookup errors fall back to the normal tariff.
+func findValidPass(passRepo repository.ParkingPassRepository, platNomor *string, vehicleType entities.VehicleType, area entities.ParkingArea, at time.Time) *uint {
+	if platNomor == nil {
+		return nil
+	}
+	plate := compactPlate(*platNomor)
+	if plate == "" {
+		return nil
+	}
+	pass, err := passRepo.GetValidForCheckin(plate, vehicleType, area, at)
+	if err != nil {
+		return nil
+	}
+	return &pass.ID
+}
+
+// compactPlate is how passes and reservations store and match plates: uppercase without
+// spaces, so "b 1234 xyz" and "B1234XYZ" are the same vehicle
+func compactPlate(platNomor string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(platNomor), " ", ""))
+}
@@ -0,0 +1,256 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/receipt"
+	"be-parkir/internal/repository"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ErrReceiptNotFound is returned for receipt codes that were never issued
+var ErrReceiptNotFound = errors.New("receipt not found")
+
+// FileStorage is the object store receipts are kept in; *storage.MinIOClient satisfies it
+type FileStorage interface {
+	Upload(ctx context.Context, objectName string, reader io.Reader, size int64, contentType string) (string, error)
+	GetObject(ctx context.Context, objectName string) (io.Reader, int64, error)
+}
+
+// ReceiptConfig controls the links printed on receipts
+type ReceiptConfig struct {
+	BaseURL string // public address of this server, e.g. https://parkir.example.go.id
+}
+
+// ReceiptFormat is the document type a receipt can be downloaded as
+type ReceiptFormat string
+
+const (
+	ReceiptFormatPDF ReceiptFormat = "pdf"
+	ReceiptFormatPNG ReceiptFormat = "png"
+)
+
+type ReceiptUsecase interface {
+	IssueReceipt(sessionID uint) (*entities.SessionReceipt, error)
+	GetReceiptFile(code string, format ReceiptFormat) (io.Reader, int64, string, error)
+	VerifyReceipt(code string) (*entities.ReceiptVerification, error)
+	ReceiptURL(receipt *entities.SessionReceipt) string
+}
+
+type receiptUsecase struct {
+	receiptRepo repository.SessionReceiptRepository
+	sessionRepo repository.ParkingSessionRepository
+	paymentRepo repository.PaymentRepository
+	tariffRepo  repository.TariffPlanRepository
+	holidayRepo repository.HolidayRepository
+	storage     FileStorage
+	config      ReceiptConfig
+}
+
+func NewReceiptUsecase(receiptRepo repository.SessionReceiptRepository, sessionRepo repository.ParkingSessionRepository, paymentRepo repository.PaymentRepository, tariffRepo repository.TariffPlanRepository, holidayRepo repository.HolidayRepository, storage FileStorage, config ReceiptConfig) ReceiptUsecase {
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	return &receiptUsecase{
+		receiptRepo: receiptRepo,
+		sessionRepo: sessionRepo,
+		paymentRepo: paymentRepo,
+		tariffRepo:  tariffRepo,
+		holidayRepo: holidayRepo,
+		storage:     storage,
+		config:      config,
+	}
+}
+
+// ReceiptURL is the public short link of a receipt
+func (u *receiptUsecase) ReceiptURL(receipt *entities.SessionReceipt) string {
+	return u.config.BaseURL + "/r/" + receipt.Code
+}
+
+func (u *receiptUsecase) verifyURL(code string) string {
+	return u.config.BaseURL + "/r/" + code + "/verify"
+}
+
+// IssueReceipt renders the session's receipt as it stands now and stores it. A session keeps one
+// code for its whole life: the check-in receipt is overwritten at checkout with the final amount.
+func (u *receiptUsecase) IssueReceipt(sessionID uint) (*entities.SessionReceipt, error) {
+	session, err := u.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return nil, errors.New("session not found")
+	}
+
+	existing, err := u.receiptRepo.GetBySessionID(session.ID)
+	if err != nil {
+		existing = nil
+	}
+	code := ""
+	if existing != nil {
+		code = existing.Code
+	} else if code, err = newReceiptCode(); err != nil {
+		return nil, err
+	}
+
+	amount := 0.0
+	if session.TotalCost != nil {
+		amount = *session.TotalCost
+	}
+	data := receipt.Data{
+		Code:         code,
+		AreaName:     session.Area.Name,
+		VehicleType:  string(session.VehicleType),
+		CheckinTime:  session.CheckinTime.In(getGMT7Location()),
+		CheckoutTime: session.CheckoutTime,
+		Amount:       amount,
+		VerifyURL:    u.verifyURL(code),
+	}
+	if session.Jukir != nil {
+		data.JukirCode = session.Jukir.JukirCode
+	}
+	if session.PlatNomor != nil {
+		data.PlatNomor = *session.PlatNomor
+	}
+	if data.CheckoutTime != nil {
+		checkoutTime := data.CheckoutTime.In(getGMT7Location())
+		data.CheckoutTime = &checkoutTime
+	}
+
+	pdf, err := receipt.RenderPDF(data)
+	if err != nil {
+		return nil, err
+	}
+	png, err := receipt.RenderPNG(data)
+	if err != nil {
+		return nil, err
+	}
+
+	pdfObject := "receipts/" + code + ".pdf"
+	pngObject := "receipts/" + code + ".png"
+	ctx := context.Background()
+	if _, err := u.storage.Upload(ctx, pdfObject, bytes.NewReader(pdf), int64(len(pdf)), "application/pdf"); err != nil {
+		return nil, fmt.Errorf("failed to store receipt: %w", err)
+	}
+	if _, err := u.storage.Upload(ctx, pngObject, bytes.NewReader(png), int64(len(png)), "image/png"); err != nil {
+		return nil, fmt.Errorf("failed to store receipt: %w", err)
+	}
+
+	if existing != nil {
+		existing.PDFObject = pdfObject
+		existing.PNGObject = pngObject
+		existing.Amount = amount
+		existing.IssuedAt = nowGMT7()
+		if err := u.receiptRepo.Update(existing); err != nil {
+			return nil, errors.New("failed to save receipt")
+		}
+		return existing, nil
+	}
+
+	issued := &entities.SessionReceipt{
+		SessionID: session.ID,
+		Code:      code,
+		PDFObject: pdfObject,
+		PNGObject: pngObject,
+		Amount:    amount,
+		IssuedAt:  nowGMT7(),
+	}
+	if err := u.receiptRepo.Create(issued); err != nil {
+		return nil, errors.New("failed to save receipt")
+	}
+	return issued, nil
+}
+
+// GetReceiptFile streams the stored receipt document and returns its size and content type
+func (u *receiptUsecase) GetReceiptFile(code string, format ReceiptFormat) (io.Reader, int64, string, error) {
+	issued, err := u.receiptRepo.GetByCode(normalizeReceiptCode(code))
+	if err != nil {
+		return nil, 0, "", ErrReceiptNotFound
+	}
+
+	objectName, contentType := issued.PDFObject, "application/pdf"
+	if format == ReceiptFormatPNG {
+		objectName, contentType = issued.PNGObject, "image/png"
+	}
+	reader, size, err := u.storage.GetObject(context.Background(), objectName)
+	if err != nil {
+		return nil, 0, "", fmt.Errorf("failed to get receipt file: %w", err)
+	}
+	return reader, size, contentType, nil
+}
+
+// VerifyReceipt confirms a receipt code was issued by this system and recomputes what the session
+// should cost under the area's tariff, so an inspector can tell whether the official rate was charged
+func (u *receiptUsecase) VerifyReceipt(code string) (*entities.ReceiptVerification, error) {
+	issued, err := u.receiptRepo.GetByCode(normalizeReceiptCode(code))
+	if err != nil {
+		return nil, ErrReceiptNotFound
+	}
+	session, err := u.sessionRepo.GetByID(issued.SessionID)
+	if err != nil {
+		return nil, ErrReceiptNotFound
+	}
+
+	// Sessions still parked have only paid the first-hour charge
+	duration := 0
+	if session.Duration != nil {
+		duration = *session.Duration
+	}
+	plan, err := resolveSessionTariffPlan(u.tariffRepo, u.holidayRepo, session.Area, session)
+	if err != nil {
+		return nil, err
+	}
+	officialAmount := plan.CalculateCost(duration)
+	if session.LostTicket {
+		penalties, err := u.paymentRepo.GetByKindForSessions(entities.PaymentKindLostTicketPenalty, []uint{session.ID})
+		if err != nil {
+			return nil, errors.New("failed to verify receipt")
+		}
+		for _, penalty := range penalties {
+			officialAmount += penalty.Amount
+		}
+	}
+
+	amountCharged := 0.0
+	if session.TotalCost != nil {
+		amountCharged = *session.TotalCost
+	}
+
+	verification := &entities.ReceiptVerification{
+		Code:            issued.Code,
+		Genuine:         true,
+		SessionID:       session.ID,
+		AreaName:        session.Area.Name,
+		VehicleType:     session.VehicleType,
+		PlatNomor:       session.PlatNomor,
+		CheckinTime:     session.CheckinTime,
+		CheckoutTime:    session.CheckoutTime,
+		SessionStatus:   session.SessionStatus,
+		PassID:          session.PassID,
+		LostTicket:      session.LostTicket,
+		AmountCharged:   amountCharged,
+		OfficialAmount:  officialAmount,
+		OfficialRate:    amountCharged <= officialAmount,
+		ReceiptIssuedAt: issued.IssuedAt,
+	}
+	if session.Jukir != nil {
+		verification.JukirCode = session.Jukir.JukirCode
+	}
+	return verification, nil
+}
+
+// newReceiptCode returns a random 10 character code; it is the only secret in the short link,
+// so it has to be unguessable rather than sequential
+func newReceiptCode() (string, error) {
+	raw := make([]byte, 10)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate receipt code: %w", err)
+	}
+	return base32.StdEncoding.EncodeToString(raw)[:10], nil
+}
+
+// normalizeReceiptCode accepts codes typed by hand in lower case
+func normalizeReceiptCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
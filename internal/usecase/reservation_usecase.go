@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ReservationConfig controls how long reservations hold a slot
+type ReservationConfig struct {
+	GracePeriod time.Duration // how long after start_time the slot is held without a check-in
+	MaxAdvance  time.Duration // how far ahead of now a reservation may start
+}
+
+type ReservationUsecase interface {
+	CreateReservation(userID uint, req *entities.CreateReservationRequest) (*entities.Reservation, error)
+	GetMyReservations(userID uint, status *entities.ReservationStatus, limit, offset int) ([]entities.Reservation, int64, error)
+	GetMyReservation(userID, reservationID uint) (*entities.Reservation, error)
+	CancelReservation(userID, reservationID uint) (*entities.Reservation, error)
+	ExpireReservations() (int, error)
+}
+
+type reservationUsecase struct {
+	reservationRepo repository.ReservationRepository
+	areaRepo        repository.ParkingAreaRepository
+	vehicleTypeRepo repository.VehicleTypeRepository
+	occupancyRepo   repository.OccupancyRepository
+	config          ReservationConfig
+}
+
+func NewReservationUsecase(reservationRepo repository.ReservationRepository, areaRepo repository.ParkingAreaRepository, vehicleTypeRepo repository.VehicleTypeRepository, occupancyRepo repository.OccupancyRepository, config ReservationConfig) ReservationUsecase {
+	return &reservationUsecase{
+		reservationRepo: reservationRepo,
+		areaRepo:        areaRepo,
+		vehicleTypeRepo: vehicleTypeRepo,
+		occupancyRepo:   occupancyRepo,
+		config:          config,
+	}
+}
+
+// CreateReservation holds a slot for the plate from now until it is used at check-in, cancelled,
+// or expires GracePeriod after the window starts. Areas that accept check-ins over capacity
+// still refuse reservations once full.
+func (u *reservationUsecase) CreateReservation(userID uint, req *entities.CreateReservationRequest) (*entities.Reservation, error) {
+	area, err := u.areaRepo.GetByID(req.AreaID)
+	if err != nil {
+		return nil, errors.New("parking area not found")
+	}
+	if area.Status != entities.AreaStatusActive {
+		return nil, errors.New("parking area is not active")
+	}
+	if err := ensureVehicleTypeAccepted(u.vehicleTypeRepo, *area, req.VehicleType); err != nil {
+		return nil, err
+	}
+
+	platNomor, err := entities.NormalizePlatNomor(req.PlatNomor)
+	if err != nil {
+		return nil, err
+	}
+	platNomor = compactPlate(platNomor)
+
+	now := nowGMT7()
+	startTime := req.StartTime.In(getGMT7Location())
+	endTime := req.EndTime.In(getGMT7Location())
+	expiresAt := startTime.Add(u.config.GracePeriod)
+	if !expiresAt.After(now) {
+		return nil, errors.New("reservation window has already started")
+	}
+	if u.config.MaxAdvance > 0 && startTime.After(now.Add(u.config.MaxAdvance)) {
+		return nil, fmt.Errorf("reservations can start at most %s ahead", u.config.MaxAdvance)
+	}
+
+	if _, err := u.reservationRepo.GetReservedByUserAndPlate(userID, platNomor); err == nil {
+		return nil, errors.New("plate already has an open reservation")
+	}
+
+	warning, err := claimSlot(u.occupancyRepo, *area, req.VehicleType)
+	if err != nil {
+		return nil, err
+	}
+	if warning != "" {
+		// Overflow areas admit walk-ins past capacity, but a reservation must be a real slot
+		releaseSlot(u.occupancyRepo, area.ID, req.VehicleType)
+		return nil, fmt.Errorf("%w: no %s slots available in %s", ErrAreaFull, req.VehicleType, area.Name)
+	}
+
+	reservation := &entities.Reservation{
+		UserID:      userID,
+		AreaID:      area.ID,
+		VehicleType: req.VehicleType,
+		PlatNomor:   platNomor,
+		StartTime:   startTime,
+		EndTime:     endTime,
+		ExpiresAt:   expiresAt,
+		Status:      entities.ReservationStatusReserved,
+	}
+	if err := u.reservationRepo.Create(reservation); err != nil {
+		releaseSlot(u.occupancyRepo, area.ID, req.VehicleType)
+		return nil, errors.New("failed to create reservation")
+	}
+
+	reservation.Area = *area
+	return reservation, nil
+}
+
+func (u *reservationUsecase) GetMyReservations(userID uint, status *entities.ReservationStatus, limit, offset int) ([]entities.Reservation, int64, error) {
+	reservations, count, err := u.reservationRepo.ListByUser(userID, status, limit, offset)
+	if err != nil {
+		return nil, 0, errors.New("failed to get reservations")
+	}
+	return reservations, count, nil
+}
+
+func (u *reservationUsecase) GetMyReservation(userID, reservationID uint) (*entities.Reservation, error) {
+	reservation, err := u.reservationRepo.GetByID(reservationID)
+	if err != nil || reservation.UserID != userID {
+		return nil, errors.New("reservation not found")
+	}
+	return reservation, nil
+}
+
+func (u *reservationUsecase) CancelReservation(userID, reservationID uint) (*entities.Reservation, error) {
+	reservation, err := u.GetMyReservation(userID, reservationID)
+	if err != nil {
+		return nil, err
+	}
+
+	closedAt := nowGMT7()
+	if err := u.reservationRepo.Close(reservation.ID, entities.ReservationStatusCancelled, closedAt); err != nil {
+		if errors.Is(err, repository.ErrReservationClosed) {
+			return nil, err
+		}
+		return nil, errors.New("failed to cancel reservation")
+	}
+	releaseSlot(u.occupancyRepo, reservation.AreaID, reservation.VehicleType)
+
+	reservation.Status = entities.ReservationStatusCancelled
+	reservation.ClosedAt = &closedAt
+	return reservation, nil
+}
+
+// ExpireReservations releases the slots of reservations nobody checked in with before the grace
+// period ran out. It returns how many were expired.
+func (u *reservationUsecase) ExpireReservations() (int, error) {
+	now := nowGMT7()
+	reservations, err := u.reservationRepo.GetExpired(now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get expired reservations: %w", err)
+	}
+
+	expired := 0
+	for _, reservation := range reservations {
+		// A check-in may have consumed it since it was read; only release slots we actually closed
+		if err := u.reservationRepo.Close(reservation.ID, entities.ReservationStatusExpired, now); err != nil {
+			continue
+		}
+		releaseSlot(u.occupancyRepo, reservation.AreaID, reservation.VehicleType)
+		expired++
+	}
+	return expired, nil
+}
+
+// findReservation returns the open reservation a check-in should consume: the one given by ID,
+// or else the one for the plate. A reservation ID is only accepted from the customer who made
+// it or with its plate. A reservation ID that does not fit the check-in is an error; finding
+// nothing for the plate is not.
+func findReservation(reservationRepo repository.ReservationRepository, reservationID *uint, userID *uint, platNomor *string, areaID uint, vehicleType entities.VehicleType, at time.Time) (*entities.Reservation, error) {
+	plate := ""
+	if platNomor != nil {
+		plate = compactPlate(*platNomor)
+	}
+
+	if reservationID == nil || *reservationID == 0 {
+		if plate == "" {
+			return nil, nil
+		}
+		reservation, err := reservationRepo.FindForCheckin(areaID, vehicleType, plate, at)
+		if err != nil {
+			return nil, nil
+		}
+		return reservation, nil
+	}
+
+	reservation, err := reservationRepo.GetByID(*reservationID)
+	if err != nil {
+		return nil, errors.New("reservation not found")
+	}
+	if reservation.Status != entities.ReservationStatusReserved || !reservation.ExpiresAt.After(at) {
+		return nil, repository.ErrReservationClosed
+	}
+	if reservation.AreaID != areaID {
+		return nil, errors.New("reservation is for a different parking area")
+	}
+	if reservation.VehicleType != vehicleType {
+		return nil, errors.New("reservation is for a different vehicle type")
+	}
+	if plate != "" && plate != reservation.PlatNomor {
+		return nil, errors.New("reservation is for a different plate")
+	}
+	if plate == "" && (userID == nil || *userID != reservation.UserID) {
+		return nil, errors.New("reservation belongs to another customer; send its plate to use it")
+	}
+	return reservation, nil
+}
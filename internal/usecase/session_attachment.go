@@ -0,0 +1,58 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"errors"
+)
+
+// fileDownloadPath is the admin proxy that streams MinIO objects, see Handlers.DownloadFile
+const fileDownloadPath = "/api/v1/admin/files/"
+
+// sessionPhoto turns an uploaded photo into the attachment row saved with the session, or nil
+// when no photo was sent
+func sessionPhoto(photo *entities.UploadedPhoto, kind entities.AttachmentKind, jukirID uint) *entities.SessionAttachment {
+	if photo == nil {
+		return nil
+	}
+	return &entities.SessionAttachment{
+		Kind:        kind,
+		ObjectName:  photo.ObjectName,
+		ContentType: photo.ContentType,
+		Size:        photo.Size,
+		UploadedBy:  jukirID,
+	}
+}
+
+// withDownloadURLs sets the admin download path on each attachment
+func withDownloadURLs(attachments []entities.SessionAttachment) []entities.SessionAttachment {
+	for i := range attachments {
+		attachments[i].URL = fileDownloadPath + attachments[i].ObjectName
+	}
+	return attachments
+}
+
+// attachmentsOfKind returns the attachments taken at check-in or at checkout
+func attachmentsOfKind(attachments []entities.SessionAttachment, kind entities.AttachmentKind) []entities.SessionAttachment {
+	var matched []entities.SessionAttachment
+	for _, attachment := range attachments {
+		if attachment.Kind == kind {
+			matched = append(matched, attachment)
+		}
+	}
+	return matched
+}
+
+// GetSessionDetail returns a session with its vehicle photos, linked through the admin file proxy
+func (u *adminUsecase) GetSessionDetail(sessionID uint) (*entities.ParkingSession, error) {
+	session, err := u.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return nil, errors.New("session not found")
+	}
+
+	attachments, err := u.attachmentRepo.GetBySessionID(session.ID)
+	if err != nil {
+		return nil, errors.New("failed to get session photos")
+	}
+	session.Attachments = withDownloadURLs(attachments)
+	return session, nil
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+	"errors"
+	"fmt"
+)
+
+// RequestSessionVoid records a jukir's request to cancel one of their sessions. Nothing changes
+// on the session until an admin approves it.
+func (u *jukirUsecase) RequestSessionVoid(jukirID, sessionID uint, req *entities.CreateVoidRequest) (*entities.SessionVoidRequest, error) {
+	session, err := u.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return nil, errors.New("session not found")
+	}
+	if session.JukirID == nil || *session.JukirID != jukirID {
+		return nil, errors.New("session does not belong to this jukir")
+	}
+	if session.SessionStatus == entities.SessionStatusCancelled {
+		return nil, errors.New("session is already cancelled")
+	}
+	if _, err := u.voidRepo.GetPendingBySessionID(sessionID); err == nil {
+		return nil, errors.New("a void request for this session is already pending")
+	}
+
+	request := &entities.SessionVoidRequest{
+		SessionID:   sessionID,
+		RequestedBy: jukirID,
+		ReasonCode:  req.ReasonCode,
+		Note:        req.Note,
+		Status:      entities.VoidStatusPending,
+	}
+	if err := u.voidRepo.Create(request); err != nil {
+		return nil, errors.New("failed to create void request")
+	}
+	return request, nil
+}
+
+func (u *adminUsecase) GetVoidRequests(status *entities.VoidStatus, limit, offset int) ([]entities.SessionVoidRequest, int64, error) {
+	requests, count, err := u.voidRepo.List(status, limit, offset)
+	if err != nil {
+		return nil, 0, errors.New("failed to get void requests")
+	}
+	return requests, count, nil
+}
+
+func (u *adminUsecase) GetSessionVoidRequests(sessionID uint) ([]entities.SessionVoidRequest, error) {
+	if _, err := u.sessionRepo.GetByID(sessionID); err != nil {
+		return nil, errors.New("session not found")
+	}
+	requests, err := u.voidRepo.GetBySessionID(sessionID)
+	if err != nil {
+		return nil, errors.New("failed to get void requests")
+	}
+	return requests, nil
+}
+
+// ApproveSessionVoid cancels the session and reverses its payment in one transaction: money that
+// was collected is marked refunded, a payment that was never collected is marked failed.
+// Cancelled sessions are left out of revenue and reports.
+func (u *adminUsecase) ApproveSessionVoid(sessionID, adminID uint, req *entities.ReviewVoidRequest) (*entities.SessionVoidRequest, error) {
+	session, err := u.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return nil, errors.New("session not found")
+	}
+	if session.SessionStatus == entities.SessionStatusCancelled {
+		return nil, errors.New("session is already cancelled")
+	}
+	wasActive := session.SessionStatus == entities.SessionStatusActive
+
+	reviewedAt := nowGMT7()
+	var request *entities.SessionVoidRequest
+	err = u.uow.Do(func(repos repository.TxRepositories) error {
+		pending, err := repos.VoidRequests.GetPendingBySessionID(sessionID)
+		if err != nil {
+			return errors.New("no pending void request for this session")
+		}
+
+		session.SessionStatus = entities.SessionStatusCancelled
+		session.PaymentStatus = reversedPaymentStatus(session.PaymentStatus)
+		if err := repos.Sessions.Update(session); err != nil {
+			return fmt.Errorf("failed to cancel session: %w", err)
+		}
+
+		// Every line is reversed: the check-in payment, balance lines and lost ticket penalties
+		payments, err := repos.Payments.ListBySessionID(sessionID)
+		if err != nil {
+			return errors.New("failed to get session payments")
+		}
+		for i := range payments {
+			if payments[i].Status != entities.PaymentStatusPaid && payments[i].Status != entities.PaymentStatusPending {
+				continue
+			}
+			payments[i].Status = reversedPaymentStatus(payments[i].Status)
+			if err := repos.Payments.Update(&payments[i]); err != nil {
+				return fmt.Errorf("failed to refund payment: %w", err)
+			}
+		}
+
+		pending.Status = entities.VoidStatusApproved
+		pending.ReviewedBy = &adminID
+		pending.ReviewedAt = &reviewedAt
+		pending.ReviewNote = req.Note
+		if err := repos.VoidRequests.Update(pending); err != nil {
+			return errors.New("failed to update void request")
+		}
+		request = pending
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if wasActive {
+		releaseSlot(u.occupancyRepo, session.AreaID, session.VehicleType)
+	}
+
+	request.Session = *session
+	return request, nil
+}
+
+func (u *adminUsecase) RejectSessionVoid(sessionID, adminID uint, req *entities.ReviewVoidRequest) (*entities.SessionVoidRequest, error) {
+	request, err := u.voidRepo.GetPendingBySessionID(sessionID)
+	if err != nil {
+		return nil, errors.New("no pending void request for this session")
+	}
+
+	reviewedAt := nowGMT7()
+	request.Status = entities.VoidStatusRejected
+	request.ReviewedBy = &adminID
+	request.ReviewedAt = &reviewedAt
+	request.ReviewNote = req.Note
+	if err := u.voidRepo.Update(request); err != nil {
+		return nil, errors.New("failed to update void request")
+	}
+	return request, nil
+}
+
+// reversedPaymentStatus is the status a payment ends in when its session is voided
+func reversedPaymentStatus(status entities.PaymentStatus) entities.PaymentStatus {
+	if status == entities.PaymentStatusPaid {
+		return entities.PaymentStatusRefunded
+	}
+	return entities.PaymentStatusFailed
+}
@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+	"errors"
+)
+
+// SyncManualRecords applies a batch of offline manual records in order. Every item is keyed by
+// its client UUID, so uploading the same batch again reports duplicates instead of new sessions.
+func (u *parkingUsecase) SyncManualRecords(jukirID uint, req *entities.SyncRequest) (*entities.SyncResponse, error) {
+	if _, err := u.jukirRepo.GetByID(jukirID); err != nil {
+		return nil, errors.New("jukir not found")
+	}
+
+	response := &entities.SyncResponse{Results: make([]entities.SyncItemResult, 0, len(req.Items))}
+	for _, item := range req.Items {
+		result := u.applySyncItem(jukirID, item)
+		switch result.Status {
+		case entities.SyncStatusApplied:
+			response.Applied++
+		case entities.SyncStatusDuplicate:
+			response.Duplicate++
+		case entities.SyncStatusConflict:
+			response.Conflict++
+		default:
+			response.Failed++
+		}
+		response.Results = append(response.Results, result)
+	}
+	return response, nil
+}
+
+// errSyncItemTaken is returned when another upload recorded the same client ID first; the
+// transaction that lost is rolled back, session included
+var errSyncItemTaken = errors.New("client_id was applied by another sync")
+
+func (u *parkingUsecase) applySyncItem(jukirID uint, item entities.SyncItem) entities.SyncItemResult {
+	result := entities.SyncItemResult{ClientID: item.ClientID, Action: item.Action}
+
+	if existing, err := u.syncRepo.GetByClientID(item.ClientID); err == nil {
+		return appliedSyncResult(result, existing, jukirID)
+	}
+
+	// The record is written in the same transaction as the session, and its unique index stops
+	// a concurrent upload applying the item twice
+	record := &entities.SyncRecord{ClientID: item.ClientID, JukirID: jukirID, Action: item.Action}
+	var sessionID uint
+	var status entities.SyncStatus
+	var err error
+	if item.Action == entities.SyncActionCheckin {
+		sessionID, status, err = u.applySyncCheckin(jukirID, item, record)
+	} else {
+		sessionID, status, err = u.applySyncCheckout(jukirID, item, record)
+	}
+	if err != nil {
+		// A concurrent upload of the same item may have won the race
+		if existing, getErr := u.syncRepo.GetByClientID(item.ClientID); getErr == nil {
+			return appliedSyncResult(result, existing, jukirID)
+		}
+		result.Status = status
+		result.Message = err.Error()
+		return result
+	}
+
+	result.Status = entities.SyncStatusApplied
+	result.SessionID = &sessionID
+	return result
+}
+
+// appliedSyncResult reports an item whose client ID is already recorded: a duplicate of the
+// same record, or a conflict when the ID was used for something else
+func appliedSyncResult(result entities.SyncItemResult, existing *entities.SyncRecord, jukirID uint) entities.SyncItemResult {
+	if existing.JukirID != jukirID || existing.Action != result.Action {
+		result.Status = entities.SyncStatusConflict
+		result.Message = "client_id already used for a different record"
+		return result
+	}
+	if existing.SessionID == nil {
+		result.Status = entities.SyncStatusConflict
+		result.Message = "item was interrupted by an earlier sync; record it again with a new client_id"
+		return result
+	}
+	result.Status = entities.SyncStatusDuplicate
+	result.SessionID = existing.SessionID
+	return result
+}
+
+// createSyncRecord writes the sync record of an offline item for the session it created or
+// closed, inside the caller's transaction. A nil record (not a sync) writes nothing.
+func createSyncRecord(repos repository.TxRepositories, record *entities.SyncRecord, sessionID uint) error {
+	if record == nil {
+		return nil
+	}
+	record.SessionID = &sessionID
+	if err := repos.SyncRecords.Create(record); err != nil {
+		return errSyncItemTaken
+	}
+	return nil
+}
+
+func (u *parkingUsecase) applySyncCheckin(jukirID uint, item entities.SyncItem, record *entities.SyncRecord) (uint, entities.SyncStatus, error) {
+	response, err := u.manualCheckin(jukirID, &entities.ManualCheckinRequest{
+		PlatNomor:   item.PlatNomor,
+		VehicleType: item.VehicleType,
+		WaktuMasuk:  *item.WaktuMasuk,
+		Latitude:    item.Latitude,
+		Longitude:   item.Longitude,
+	}, record)
+	if err != nil {
+		if errors.Is(err, ErrAreaFull) || errors.Is(err, errSyncItemTaken) {
+			return 0, entities.SyncStatusConflict, err
+		}
+		return 0, entities.SyncStatusFailed, err
+	}
+	return response.SessionID, entities.SyncStatusApplied, nil
+}
+
+func (u *parkingUsecase) applySyncCheckout(jukirID uint, item entities.SyncItem, record *entities.SyncRecord) (uint, entities.SyncStatus, error) {
+	var sessionID uint
+	switch {
+	case item.SessionID != nil && *item.SessionID != 0:
+		sessionID = *item.SessionID
+	case item.CheckinClientID != nil:
+		checkin, err := u.syncRepo.GetByClientID(*item.CheckinClientID)
+		if err != nil || checkin.Action != entities.SyncActionCheckin || checkin.SessionID == nil {
+			return 0, entities.SyncStatusConflict, errors.New("check-in for this checkout has not been synced")
+		}
+		sessionID = *checkin.SessionID
+	default:
+		return 0, entities.SyncStatusFailed, errors.New("session_id or checkin_client_id is required for checkout")
+	}
+
+	session, err := u.sessionRepo.GetByID(sessionID)
+	if err != nil {
+		return 0, entities.SyncStatusConflict, errors.New("session not found")
+	}
+	if session.JukirID == nil || *session.JukirID != jukirID {
+		return 0, entities.SyncStatusConflict, errors.New("session does not belong to this jukir")
+	}
+	if session.SessionStatus != entities.SessionStatusActive {
+		return 0, entities.SyncStatusConflict, errors.New("session is not active")
+	}
+
+	if _, err := u.manualCheckout(jukirID, &entities.ManualCheckoutRequest{
+		SessionID:   sessionID,
+		WaktuKeluar: *item.WaktuKeluar,
+		Latitude:    item.Latitude,
+		Longitude:   item.Longitude,
+	}, record); err != nil {
+		if errors.Is(err, ErrConcurrentUpdate) || errors.Is(err, errSyncItemTaken) {
+			return 0, entities.SyncStatusConflict, err
+		}
+		return 0, entities.SyncStatusFailed, err
+	}
+	return sessionID, entities.SyncStatusApplied, nil
+}
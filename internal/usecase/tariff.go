@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// resolveTariffPlan returns the tariff plan configured for the area and vehicle type,
+// falling back to the area's flat rate when none is configured. The first-hour rate
+// is then overridden by the holiday, weekend or night schedule (in that order of
+// precedence) that applies at checkinTime, if the schedule has a rate for the vehicle type.
+// Database errors are returned rather than priced at the flat rate.
+func resolveTariffPlan(tariffRepo repository.TariffPlanRepository, holidayRepo repository.HolidayRepository, area entities.ParkingArea, vehicleType entities.VehicleType, checkinTime time.Time) (entities.TariffPlan, error) {
+	resolved := entities.FlatTariffPlan(area, vehicleType)
+	plan, err := tariffRepo.GetByAreaAndVehicleType(area.ID, vehicleType)
+	switch {
+	case err == nil:
+		resolved = *plan
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return entities.TariffPlan{}, fmt.Errorf("failed to load tariff plan: %w", err)
+	}
+
+	schedules, err := tariffRepo.GetSchedulesByAreaID(area.ID)
+	if err != nil {
+		return entities.TariffPlan{}, fmt.Errorf("failed to load tariff schedules: %w", err)
+	}
+	if len(schedules) == 0 {
+		return resolved, nil
+	}
+
+	schedule, err := applicableSchedule(holidayRepo, schedules, checkinTime.In(getGMT7Location()))
+	if err != nil {
+		return entities.TariffPlan{}, err
+	}
+	if schedule != nil {
+		if rate := schedule.GetRateByVehicleType(vehicleType); rate > 0 {
+			resolved.FirstHourRate = rate
+		}
+	}
+	return resolved, nil
+}
+
+// resolveSessionTariffPlan is resolveTariffPlan for an existing session. Sessions checked in
+// with a monthly pass get an all-zero plan, so they cost nothing however long they stay.
+func resolveSessionTariffPlan(tariffRepo repository.TariffPlanRepository, holidayRepo repository.HolidayRepository, area entities.ParkingArea, session *entities.ParkingSession) (entities.TariffPlan, error) {
+	if session.PassID != nil {
+		return passTariffPlan(area, session.VehicleType), nil
+	}
+	return resolveTariffPlan(tariffRepo, holidayRepo, area, session.VehicleType, session.CheckinTime)
+}
+
+// passTariffPlan is the plan for sessions covered by a monthly pass: every rate is zero
+func passTariffPlan(area entities.ParkingArea, vehicleType entities.VehicleType) entities.TariffPlan {
+	return entities.TariffPlan{AreaID: area.ID, VehicleType: vehicleType}
+}
+
+// applicableSchedule picks the schedule in effect at the given time, nil when none applies
+func applicableSchedule(holidayRepo repository.HolidayRepository, schedules []entities.TariffSchedule, at time.Time) (*entities.TariffSchedule, error) {
+	byType := make(map[entities.ScheduleType]*entities.TariffSchedule, len(schedules))
+	for i := range schedules {
+		byType[schedules[i].ScheduleType] = &schedules[i]
+	}
+
+	if schedule, ok := byType[entities.ScheduleTypeHoliday]; ok {
+		isHoliday, err := holidayRepo.IsHoliday(at)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check holidays: %w", err)
+		}
+		if isHoliday {
+			return schedule, nil
+		}
+	}
+	if schedule, ok := byType[entities.ScheduleTypeWeekend]; ok {
+		if at.Weekday() == time.Saturday || at.Weekday() == time.Sunday {
+			return schedule, nil
+		}
+	}
+	if schedule, ok := byType[entities.ScheduleTypeNight]; ok && schedule.CoversTimeOfDay(at) {
+		return schedule, nil
+	}
+	return nil, nil
+}
+
+// hourlyRate returns the rate reported as "hourly_rate" in responses: the per-hour
+// increment for progressive plans, or the flat rate otherwise
+func hourlyRate(plan entities.TariffPlan) float64 {
+	if plan.NextHourRate > 0 {
+		return plan.NextHourRate
+	}
+	return plan.FirstHourRate
+}
+
+// buildTariffSchedules validates schedule requests from the area endpoints
+// and converts them into entities (at most one schedule per type)
+func buildTariffSchedules(reqs []entities.TariffScheduleRequest) ([]entities.TariffSchedule, error) {
+	schedules := make([]entities.TariffSchedule, 0, len(reqs))
+	seen := make(map[entities.ScheduleType]bool, len(reqs))
+	for _, req := range reqs {
+		if seen[req.ScheduleType] {
+			return nil, fmt.Errorf("duplicate tariff schedule: %s", req.ScheduleType)
+		}
+		seen[req.ScheduleType] = true
+
+		schedule := entities.TariffSchedule{
+			ScheduleType: req.ScheduleType,
+			RateMobil:    req.RateMobil,
+			RateMotor:    req.RateMotor,
+		}
+		if req.ScheduleType == entities.ScheduleTypeNight {
+			if req.StartTime == nil || req.EndTime == nil || *req.StartTime == *req.EndTime {
+				return nil, errors.New("night schedule requires distinct start_time and end_time")
+			}
+			schedule.StartTime = req.StartTime
+			schedule.EndTime = req.EndTime
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, nil
+}
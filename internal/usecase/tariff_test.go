@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+	"errors"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type fakeHolidayRepo struct {
+	repository.HolidayRepository
+	dates map[string]bool // YYYY-MM-DD
+	err   error
+}
+
+func (r *fakeHolidayRepo) IsHoliday(date time.Time) (bool, error) {
+	if r.err != nil {
+		return false, r.err
+	}
+	return r.dates[date.Format("2006-01-02")], nil
+}
+
+type fakeTariffRepo struct {
+	repository.TariffPlanRepository
+	plan         *entities.TariffPlan
+	planErr      error
+	schedules    []entities.TariffSchedule
+	schedulesErr error
+}
+
+func (r *fakeTariffRepo) GetByAreaAndVehicleType(areaID uint, vehicleType entities.VehicleType) (*entities.TariffPlan, error) {
+	return r.plan, r.planErr
+}
+
+func (r *fakeTariffRepo) GetSchedulesByAreaID(areaID uint) ([]entities.TariffSchedule, error) {
+	return r.schedules, r.schedulesErr
+}
+
+func nightSchedule(start, end string, rateMobil float64) entities.TariffSchedule {
+	return entities.TariffSchedule{ScheduleType: entities.ScheduleTypeNight, RateMobil: rateMobil, StartTime: &start, EndTime: &end}
+}
+
+func TestApplicableSchedule(t *testing.T) {
+	holidays := &fakeHolidayRepo{dates: map[string]bool{"2026-08-17": true, "2026-10-24": true}}
+	all := []entities.TariffSchedule{
+		nightSchedule("22:00", "06:00", 8000),
+		{ScheduleType: entities.ScheduleTypeWeekend, RateMobil: 7000},
+		{ScheduleType: entities.ScheduleTypeHoliday, RateMobil: 9000},
+	}
+	nightOnly := []entities.TariffSchedule{nightSchedule("22:00", "06:00", 8000)}
+	earlyMorning := []entities.TariffSchedule{nightSchedule("01:00", "05:00", 8000)}
+
+	tests := []struct {
+		name      string
+		schedules []entities.TariffSchedule
+		at        time.Time
+		want      entities.ScheduleType // "" = no schedule applies
+	}{
+		{"weekday daytime", all, dateGMT7(2026, 10, 16, 12, 0, 0, 0), ""},
+		{"weekday before night starts", all, dateGMT7(2026, 10, 16, 21, 59, 0, 0), ""},
+		{"night starts", all, dateGMT7(2026, 10, 16, 22, 0, 0, 0), entities.ScheduleTypeNight},
+		{"night before midnight", all, dateGMT7(2026, 10, 15, 23, 30, 0, 0), entities.ScheduleTypeNight},
+		{"night after midnight", all, dateGMT7(2026, 10, 16, 5, 59, 0, 0), entities.ScheduleTypeNight},
+		{"night ends", all, dateGMT7(2026, 10, 16, 6, 0, 0, 0), ""},
+		{"weekend daytime", all, dateGMT7(2026, 10, 17, 12, 0, 0, 0), entities.ScheduleTypeWeekend},
+		{"weekend beats night", all, dateGMT7(2026, 10, 17, 23, 0, 0, 0), entities.ScheduleTypeWeekend},
+		{"holiday on a weekday", all, dateGMT7(2026, 8, 17, 12, 0, 0, 0), entities.ScheduleTypeHoliday},
+		{"holiday beats night", all, dateGMT7(2026, 8, 17, 23, 0, 0, 0), entities.ScheduleTypeHoliday},
+		{"holiday beats weekend", all, dateGMT7(2026, 10, 24, 12, 0, 0, 0), entities.ScheduleTypeHoliday},
+		{"night only on a weekend night", nightOnly, dateGMT7(2026, 10, 17, 23, 0, 0, 0), entities.ScheduleTypeNight},
+		{"night only on a holiday afternoon", nightOnly, dateGMT7(2026, 8, 17, 15, 0, 0, 0), ""},
+		{"same-day window before it starts", earlyMorning, dateGMT7(2026, 10, 16, 0, 59, 0, 0), ""},
+		{"same-day window starts", earlyMorning, dateGMT7(2026, 10, 16, 1, 0, 0, 0), entities.ScheduleTypeNight},
+		{"same-day window last minute", earlyMorning, dateGMT7(2026, 10, 16, 4, 59, 0, 0), entities.ScheduleTypeNight},
+		{"same-day window ends", earlyMorning, dateGMT7(2026, 10, 16, 5, 0, 0, 0), ""},
+		{"no schedules", nil, dateGMT7(2026, 10, 17, 23, 0, 0, 0), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := applicableSchedule(holidays, tt.schedules, tt.at)
+			if err != nil {
+				t.Fatalf("applicableSchedule() error = %v", err)
+			}
+			var got entities.ScheduleType
+			if schedule != nil {
+				got = schedule.ScheduleType
+			}
+			if got != tt.want {
+				t.Errorf("applicableSchedule(%s) = %q, want %q", tt.at.Format("Mon 2006-01-02 15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplicableScheduleHolidayLookupFails(t *testing.T) {
+	holidays := &fakeHolidayRepo{err: errors.New("connection refused")}
+	at := dateGMT7(2026, 10, 17, 12, 0, 0, 0)
+
+	withHoliday := []entities.TariffSchedule{{ScheduleType: entities.ScheduleTypeHoliday, RateMobil: 9000}}
+	if _, err := applicableSchedule(holidays, withHoliday, at); err == nil {
+		t.Error("applicableSchedule() with a holiday schedule: want the lookup error, got nil")
+	}
+
+	// Without a holiday schedule the calendar is not consulted
+	withoutHoliday := []entities.TariffSchedule{{ScheduleType: entities.ScheduleTypeWeekend, RateMobil: 7000}}
+	schedule, err := applicableSchedule(holidays, withoutHoliday, at)
+	if err != nil || schedule == nil || schedule.ScheduleType != entities.ScheduleTypeWeekend {
+		t.Errorf("applicableSchedule() without a holiday schedule = %v, %v; want weekend", schedule, err)
+	}
+}
+
+func TestResolveTariffPlan(t *testing.T) {
+	area := entities.ParkingArea{ID: 3, HourlyRateMobil: 5000, HourlyRateMotor: 2000}
+	configured := &entities.TariffPlan{AreaID: 3, VehicleType: entities.VehicleTypeMobil, FirstHourRate: 6000, NextHourRate: 4000}
+	weekend := []entities.TariffSchedule{{ScheduleType: entities.ScheduleTypeWeekend, RateMobil: 7000}}
+	friday := dateGMT7(2026, 10, 16, 12, 0, 0, 0)
+	saturday := dateGMT7(2026, 10, 17, 12, 0, 0, 0)
+
+	tests := []struct {
+		name        string
+		repo        *fakeTariffRepo
+		vehicleType entities.VehicleType
+		at          time.Time
+		wantFirst   float64
+		wantNext    float64
+		wantErr     bool
+	}{
+		{"no plan falls back to the flat rate", &fakeTariffRepo{planErr: gorm.ErrRecordNotFound}, entities.VehicleTypeMotor, friday, 2000, 0, false},
+		{"configured plan", &fakeTariffRepo{plan: configured}, entities.VehicleTypeMobil, friday, 6000, 4000, false},
+		{"schedule overrides the first hour", &fakeTariffRepo{plan: configured, schedules: weekend}, entities.VehicleTypeMobil, saturday, 7000, 4000, false},
+		{"schedule without a rate for the type", &fakeTariffRepo{planErr: gorm.ErrRecordNotFound, schedules: weekend}, entities.VehicleTypeMotor, saturday, 2000, 0, false},
+		{"plan lookup fails", &fakeTariffRepo{planErr: errors.New("connection refused")}, entities.VehicleTypeMobil, friday, 0, 0, true},
+		{"schedule lookup fails", &fakeTariffRepo{plan: configured, schedulesErr: errors.New("connection refused")}, entities.VehicleTypeMobil, friday, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := resolveTariffPlan(tt.repo, &fakeHolidayRepo{}, area, tt.vehicleType, tt.at)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("resolveTariffPlan() = %+v, want an error", plan)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveTariffPlan() error = %v", err)
+			}
+			if plan.FirstHourRate != tt.wantFirst || plan.NextHourRate != tt.wantNext {
+				t.Errorf("resolveTariffPlan() rates = %v/%v, want %v/%v", plan.FirstHourRate, plan.NextHourRate, tt.wantFirst, tt.wantNext)
+			}
+		})
+	}
+}
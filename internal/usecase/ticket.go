@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrTicketRequired is returned when a bare session ID is used outside the transition window
+	ErrTicketRequired = errors.New("parking ticket is required")
+	// ErrInvalidTicket is returned for tampered, expired or mismatched tickets
+	ErrInvalidTicket = errors.New("invalid or expired parking ticket")
+)
+
+// TicketConfig signs the tickets handed to anonymous users at check-in
+type TicketConfig struct {
+	SecretKey   string
+	Expiry      time.Duration
+	LegacyUntil time.Time // bare session IDs are accepted until this time; zero = never
+}
+
+// parkingTicket is the payload of a ticket: the session it grants access to and when it expires
+type parkingTicket struct {
+	SessionID   uint
+	AreaID      uint
+	CheckinTime int64
+	ExpiresAt   int64
+}
+
+// issueTicket signs a ticket for the session. The token is base64url(payload) + "." + base64url(HMAC-SHA256).
+func (c TicketConfig) issueTicket(session *entities.ParkingSession) (string, time.Time) {
+	return c.signTicket(session, session.CheckinTime.Add(c.Expiry))
+}
+
+// issueLegacyTicket signs a ticket for a session looked up by bare ID. It expires with the
+// transition window, so guessing an ID never yields access that outlives it.
+func (c TicketConfig) issueLegacyTicket(session *entities.ParkingSession) (string, time.Time) {
+	expiresAt := session.CheckinTime.Add(c.Expiry)
+	if expiresAt.After(c.LegacyUntil) {
+		expiresAt = c.LegacyUntil
+	}
+	return c.signTicket(session, expiresAt)
+}
+
+func (c TicketConfig) signTicket(session *entities.ParkingSession, expiresAt time.Time) (string, time.Time) {
+	payload := fmt.Sprintf("%d:%d:%d:%d", session.ID, session.AreaID, session.CheckinTime.Unix(), expiresAt.Unix())
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(payload))
+	return encoded + "." + c.signature(encoded), expiresAt
+}
+
+// matches reports whether the ticket was issued for this session
+func (t *parkingTicket) matches(session *entities.ParkingSession) bool {
+	return t.SessionID == session.ID && t.AreaID == session.AreaID && t.CheckinTime == session.CheckinTime.Unix()
+}
+
+// parseTicket returns the ticket payload if the signature is valid and it has not expired
+func (c TicketConfig) parseTicket(token string) (*parkingTicket, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(c.signature(encoded))) {
+		return nil, ErrInvalidTicket
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidTicket
+	}
+
+	var ticket parkingTicket
+	if _, err := fmt.Sscanf(string(payload), "%d:%d:%d:%d", &ticket.SessionID, &ticket.AreaID, &ticket.CheckinTime, &ticket.ExpiresAt); err != nil {
+		return nil, ErrInvalidTicket
+	}
+	if time.Now().Unix() > ticket.ExpiresAt {
+		return nil, ErrInvalidTicket
+	}
+	return &ticket, nil
+}
+
+// allowsLegacyAccess reports whether bare session IDs are still accepted
+func (c TicketConfig) allowsLegacyAccess() bool {
+	return !c.LegacyUntil.IsZero() && time.Now().Before(c.LegacyUntil)
+}
+
+func (c TicketConfig) signature(encoded string) string {
+	mac := hmac.New(sha256.New, []byte(c.SecretKey))
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTicketRoundTrip(t *testing.T) {
+	config := TicketConfig{SecretKey: "ticket-secret", Expiry: 24 * time.Hour}
+	checkin := time.Now().Add(-time.Hour).Truncate(time.Second)
+	session := &entities.ParkingSession{ID: 42, AreaID: 7, CheckinTime: checkin}
+
+	token, expiresAt := config.issueTicket(session)
+	if want := checkin.Add(config.Expiry); !expiresAt.Equal(want) {
+		t.Errorf("issueTicket() expiresAt = %v, want %v", expiresAt, want)
+	}
+
+	parsed, err := config.parseTicket(token)
+	if err != nil {
+		t.Fatalf("parseTicket() error = %v", err)
+	}
+	if parsed.SessionID != 42 || parsed.AreaID != 7 || parsed.CheckinTime != checkin.Unix() || parsed.ExpiresAt != expiresAt.Unix() {
+		t.Errorf("parseTicket() = %+v", parsed)
+	}
+	if !parsed.matches(session) {
+		t.Error("matches() = false for the session the ticket was issued for")
+	}
+}
+
+func TestParseTicketRejects(t *testing.T) {
+	config := TicketConfig{SecretKey: "ticket-secret", Expiry: 24 * time.Hour}
+	session := &entities.ParkingSession{ID: 42, AreaID: 7, CheckinTime: time.Now().Add(-time.Hour)}
+	token, _ := config.issueTicket(session)
+	encoded, signature, _ := strings.Cut(token, ".")
+
+	expiredConfig := TicketConfig{SecretKey: "ticket-secret", Expiry: time.Minute}
+	expired, _ := expiredConfig.issueTicket(session)
+
+	forged, _ := TicketConfig{SecretKey: "other-secret", Expiry: 24 * time.Hour}.issueTicket(session)
+	otherSession, _ := config.issueTicket(&entities.ParkingSession{ID: 43, AreaID: 7, CheckinTime: session.CheckinTime})
+	otherEncoded, _, _ := strings.Cut(otherSession, ".")
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"empty", ""},
+		{"no signature", encoded},
+		{"tampered signature", encoded + "." + strings.Repeat("A", len(signature))},
+		{"payload swapped under a valid signature", otherEncoded + "." + signature},
+		{"signed with another secret", forged},
+		{"expired", expired},
+		{"not base64", "!!!." + config.signature("!!!")},
+		{"malformed payload", "bm90LWEtdGlja2V0." + config.signature("bm90LWEtdGlja2V0")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := config.parseTicket(tt.token); !errors.Is(err, ErrInvalidTicket) {
+				t.Errorf("parseTicket() error = %v, want ErrInvalidTicket", err)
+			}
+		})
+	}
+}
+
+func TestTicketMatches(t *testing.T) {
+	checkin := time.Now().Add(-time.Hour).Truncate(time.Second)
+	ticket := &parkingTicket{SessionID: 42, AreaID: 7, CheckinTime: checkin.Unix()}
+
+	tests := []struct {
+		name    string
+		session entities.ParkingSession
+		want    bool
+	}{
+		{"same session", entities.ParkingSession{ID: 42, AreaID: 7, CheckinTime: checkin}, true},
+		{"other session", entities.ParkingSession{ID: 43, AreaID: 7, CheckinTime: checkin}, false},
+		{"other area", entities.ParkingSession{ID: 42, AreaID: 8, CheckinTime: checkin}, false},
+		{"check-in time changed", entities.ParkingSession{ID: 42, AreaID: 7, CheckinTime: checkin.Add(time.Minute)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ticket.matches(&tt.session); got != tt.want {
+				t.Errorf("matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllowsLegacyAccess(t *testing.T) {
+	tests := []struct {
+		name        string
+		legacyUntil time.Time
+		want        bool
+	}{
+		{"no transition window", time.Time{}, false},
+		{"window still open", time.Now().Add(time.Hour), true},
+		{"window closed", time.Now().Add(-time.Hour), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := TicketConfig{SecretKey: "ticket-secret", Expiry: 24 * time.Hour, LegacyUntil: tt.legacyUntil}
+			if got := config.allowsLegacyAccess(); got != tt.want {
+				t.Errorf("allowsLegacyAccess() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueLegacyTicket(t *testing.T) {
+	checkin := time.Now().Add(-time.Hour).Truncate(time.Second)
+	session := &entities.ParkingSession{ID: 42, AreaID: 7, CheckinTime: checkin}
+
+	tests := []struct {
+		name        string
+		legacyUntil time.Time
+		want        time.Time
+	}{
+		{"capped at the end of the window", checkin.Add(2 * time.Hour), checkin.Add(2 * time.Hour)},
+		{"window outlasts the ticket", checkin.Add(48 * time.Hour), checkin.Add(24 * time.Hour)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := TicketConfig{SecretKey: "ticket-secret", Expiry: 24 * time.Hour, LegacyUntil: tt.legacyUntil}
+			token, expiresAt := config.issueLegacyTicket(session)
+			if !expiresAt.Equal(tt.want) {
+				t.Errorf("issueLegacyTicket() expiresAt = %v, want %v", expiresAt, tt.want)
+			}
+			parsed, err := config.parseTicket(token)
+			if err != nil {
+				t.Fatalf("parseTicket() error = %v", err)
+			}
+			if parsed.ExpiresAt != tt.want.Unix() {
+				t.Errorf("signed expiry = %d, want %d", parsed.ExpiresAt, tt.want.Unix())
+			}
+		})
+	}
+
+	// Once the window has closed the ticket is already expired
+	closed := TicketConfig{SecretKey: "ticket-secret", Expiry: 24 * time.Hour, LegacyUntil: time.Now().Add(-time.Minute)}
+	token, _ := closed.issueLegacyTicket(session)
+	if _, err := closed.parseTicket(token); !errors.Is(err, ErrInvalidTicket) {
+		t.Errorf("parseTicket() after the window error = %v, want ErrInvalidTicket", err)
+	}
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"be-parkir/internal/domain/entities"
+	"be-parkir/internal/repository"
+	"errors"
+	"fmt"
+)
+
+// listVehicleTypes returns every registered vehicle type (including inactive ones, so
+// historical sessions still show up in reports), falling back to mobil/motor
+func listVehicleTypes(vehicleTypeRepo repository.VehicleTypeRepository) []entities.VehicleTypeConfig {
+	vehicleTypes, err := vehicleTypeRepo.List(false)
+	if err != nil || len(vehicleTypes) == 0 {
+		return entities.DefaultVehicleTypes
+	}
+	return vehicleTypes
+}
+
+// ensureVehicleTypeAccepted checks the type against the registry and the area's configured rates
+func ensureVehicleTypeAccepted(vehicleTypeRepo repository.VehicleTypeRepository, area entities.ParkingArea, vehicleType entities.VehicleType) error {
+	if err := ensureVehicleTypeActive(vehicleTypeRepo, vehicleType); err != nil {
+		return err
+	}
+	if !area.AcceptsVehicleType(vehicleType) {
+		return errors.New("vehicle type is not available in this area")
+	}
+	return nil
+}
+
+// ensureVehicleTypeActive rejects vehicle types that are not registered or are switched off
+func ensureVehicleTypeActive(vehicleTypeRepo repository.VehicleTypeRepository, vehicleType entities.VehicleType) error {
+	config, err := vehicleTypeRepo.GetByCode(vehicleType)
+	if err != nil || !config.IsActive {
+		return errors.New("unsupported vehicle type")
+	}
+	return nil
+}
+
+type vehicleFlow struct {
+	In  int
+	Out int
+}
+
+// countVehicleFlow counts check-ins and check-outs per vehicle type. Every registered
+// type gets an entry, plus any unregistered type that appears in the sessions.
+func countVehicleFlow(sessions []entities.ParkingSession, vehicleTypes []entities.VehicleTypeConfig) map[entities.VehicleType]*vehicleFlow {
+	flows := make(map[entities.VehicleType]*vehicleFlow, len(vehicleTypes))
+	for _, vt := range vehicleTypes {
+		flows[vt.Code] = &vehicleFlow{}
+	}
+
+	for _, session := range sessions {
+		flow, ok := flows[session.VehicleType]
+		if !ok {
+			flow = &vehicleFlow{}
+			flows[session.VehicleType] = flow
+		}
+		flow.In++
+		if session.CheckoutTime != nil {
+			flow.Out++
+		}
+	}
+	return flows
+}
+
+// vehicleFlowByType formats countVehicleFlow results as {code: {inKey: n, outKey: m}}
+func vehicleFlowByType(sessions []entities.ParkingSession, vehicleTypes []entities.VehicleTypeConfig, inKey, outKey string) map[string]interface{} {
+	result := make(map[string]interface{})
+	for code, flow := range countVehicleFlow(sessions, vehicleTypes) {
+		result[string(code)] = map[string]interface{}{
+			inKey:  flow.In,
+			outKey: flow.Out,
+		}
+	}
+	return result
+}
+
+// applyVehicleRates validates area rate requests against the registry. Rates for mobil and
+// motor are written to the area's own columns; the rest are returned as AreaVehicleRate rows.
+func applyVehicleRates(vehicleTypeRepo repository.VehicleTypeRepository, area *entities.ParkingArea, reqs []entities.AreaVehicleRateRequest) ([]entities.AreaVehicleRate, error) {
+	rates := make([]entities.AreaVehicleRate, 0, len(reqs))
+	seen := make(map[entities.VehicleType]bool, len(reqs))
+	for _, req := range reqs {
+		if seen[req.VehicleType] {
+			return nil, fmt.Errorf("duplicate vehicle rate: %s", req.VehicleType)
+		}
+		seen[req.VehicleType] = true
+
+		if _, err := vehicleTypeRepo.GetByCode(req.VehicleType); err != nil {
+			return nil, fmt.Errorf("unknown vehicle type: %s", req.VehicleType)
+		}
+
+		switch req.VehicleType {
+		case entities.VehicleTypeMobil:
+			area.HourlyRateMobil = req.HourlyRate
+			area.MaxMobil = req.Capacity
+		case entities.VehicleTypeMotor:
+			area.HourlyRateMotor = req.HourlyRate
+			area.MaxMotor = req.Capacity
+		default:
+			rates = append(rates, entities.AreaVehicleRate{
+				VehicleType: req.VehicleType,
+				HourlyRate:  req.HourlyRate,
+				Capacity:    req.Capacity,
+			})
+		}
+	}
+	return rates, nil
+}
@@ -0,0 +1,22 @@
+-- Migration: Create tariff_plans table
+-- Progressive per-area pricing: first hour, next-hour increments, daily cap and grace period
+
+CREATE TABLE IF NOT EXISTS tariff_plans (
+    id BIGSERIAL PRIMARY KEY,
+    area_id BIGINT NOT NULL REFERENCES parking_areas(id),
+    vehicle_type VARCHAR(10) NOT NULL,
+    first_hour_rate DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    next_hour_rate DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    daily_cap DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    grace_period_minutes INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+-- One plan per area and vehicle type
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tariff_plans_area_vehicle ON tariff_plans(area_id, vehicle_type);
+
+COMMENT ON COLUMN tariff_plans.first_hour_rate IS 'Price of the first (started) hour';
+COMMENT ON COLUMN tariff_plans.next_hour_rate IS 'Price of every further started hour';
+COMMENT ON COLUMN tariff_plans.daily_cap IS 'Maximum charge per 24 hours, 0 means no cap';
+COMMENT ON COLUMN tariff_plans.grace_period_minutes IS 'Tolerance in minutes before the next hour is charged';
@@ -0,0 +1,31 @@
+-- Migration: Create tariff_schedules and holidays tables
+-- Night, weekend and national-holiday rates that override an area's first-hour rate
+
+CREATE TABLE IF NOT EXISTS tariff_schedules (
+    id BIGSERIAL PRIMARY KEY,
+    area_id BIGINT NOT NULL REFERENCES parking_areas(id),
+    schedule_type VARCHAR(10) NOT NULL,
+    rate_mobil DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    rate_motor DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    start_time VARCHAR(5),
+    end_time VARCHAR(5),
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+-- One schedule of each type per area
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tariff_schedules_area_type ON tariff_schedules(area_id, schedule_type);
+
+COMMENT ON COLUMN tariff_schedules.schedule_type IS 'night, weekend or holiday';
+COMMENT ON COLUMN tariff_schedules.start_time IS 'HH:MM (GMT+7) when the night window starts';
+COMMENT ON COLUMN tariff_schedules.end_time IS 'HH:MM (GMT+7) when the night window ends, may wrap past midnight';
+
+CREATE TABLE IF NOT EXISTS holidays (
+    id BIGSERIAL PRIMARY KEY,
+    date DATE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_holidays_date ON holidays(date);
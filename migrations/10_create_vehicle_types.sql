@@ -0,0 +1,40 @@
+-- Migration: Vehicle type registry
+-- Replaces the hard-coded mobil/motor constraint with a vehicle_types table and per-area rates
+
+CREATE TABLE IF NOT EXISTS vehicle_types (
+    id BIGSERIAL PRIMARY KEY,
+    code VARCHAR(20) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_vehicle_types_code ON vehicle_types(code);
+
+INSERT INTO vehicle_types (code, name, is_active, sort_order, created_at, updated_at) VALUES
+    ('mobil', 'Mobil Penumpang', TRUE, 1, NOW(), NOW()),
+    ('motor', 'Motor', TRUE, 2, NOW(), NOW())
+ON CONFLICT (code) DO NOTHING;
+
+-- Rates and capacities for registry types other than mobil/motor
+-- (mobil/motor keep using hourly_rate_mobil/motor and max_mobil/motor on parking_areas)
+CREATE TABLE IF NOT EXISTS area_vehicle_rates (
+    id BIGSERIAL PRIMARY KEY,
+    area_id BIGINT NOT NULL REFERENCES parking_areas(id),
+    vehicle_type VARCHAR(20) NOT NULL,
+    hourly_rate DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    capacity INT DEFAULT 0,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_area_vehicle_rates_area_type ON area_vehicle_rates(area_id, vehicle_type);
+
+-- Vehicle types are now validated against the registry
+ALTER TABLE parking_sessions DROP CONSTRAINT IF EXISTS chk_vehicle_type;
+ALTER TABLE parking_sessions ALTER COLUMN vehicle_type TYPE VARCHAR(20);
+ALTER TABLE tariff_plans ALTER COLUMN vehicle_type TYPE VARCHAR(20);
+
+COMMENT ON COLUMN parking_sessions.vehicle_type IS 'Vehicle type code from vehicle_types';
@@ -0,0 +1,12 @@
+-- Migration: Capacity policy for parking areas
+-- reject = check-in is refused once max_mobil/max_motor (or the vehicle rate capacity) is reached
+-- overflow = check-in is accepted with a warning
+
+ALTER TABLE parking_areas
+ADD COLUMN IF NOT EXISTS capacity_policy VARCHAR(10) NOT NULL DEFAULT 'reject';
+
+ALTER TABLE parking_areas
+DROP CONSTRAINT IF EXISTS chk_capacity_policy;
+
+ALTER TABLE parking_areas
+ADD CONSTRAINT chk_capacity_policy CHECK (capacity_policy IN ('reject', 'overflow'));
@@ -0,0 +1,16 @@
+-- Migration: Create sync_records table
+-- Client UUIDs of offline manual records applied through POST /jukir/sync, so re-uploads are idempotent
+
+CREATE TABLE IF NOT EXISTS sync_records (
+    id BIGSERIAL PRIMARY KEY,
+    client_id VARCHAR(36) NOT NULL,
+    jukir_id BIGINT NOT NULL REFERENCES jukirs(id),
+    action VARCHAR(10) NOT NULL,
+    session_id BIGINT NULL REFERENCES parking_sessions(id),
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT chk_sync_action CHECK (action IN ('checkin', 'checkout'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sync_records_client_id ON sync_records(client_id);
+CREATE INDEX IF NOT EXISTS idx_sync_records_jukir_id ON sync_records(jukir_id);
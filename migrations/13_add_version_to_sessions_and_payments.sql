@@ -0,0 +1,5 @@
+-- Migration: Add version columns to parking_sessions and payments
+-- Updates compare the version they read and bump it, so concurrent checkouts of one session fail with 409 instead of both applying
+
+ALTER TABLE parking_sessions ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,22 @@
+-- Migration: Create session_void_requests table
+-- Jukir requests to cancel sessions recorded by mistake, and the admin who approved or rejected each one
+
+CREATE TABLE IF NOT EXISTS session_void_requests (
+    id BIGSERIAL PRIMARY KEY,
+    session_id BIGINT NOT NULL REFERENCES parking_sessions(id),
+    requested_by BIGINT NOT NULL REFERENCES jukirs(id),
+    reason_code VARCHAR(30) NOT NULL,
+    note TEXT,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending',
+    reviewed_by BIGINT REFERENCES users(id),
+    reviewed_at TIMESTAMPTZ,
+    review_note TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT chk_void_reason_code CHECK (reason_code IN ('wrong_plate', 'wrong_vehicle_type', 'accidental_checkin')),
+    CONSTRAINT chk_void_status CHECK (status IN ('pending', 'approved', 'rejected'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_void_requests_session_id ON session_void_requests(session_id);
+CREATE INDEX IF NOT EXISTS idx_session_void_requests_requested_by ON session_void_requests(requested_by);
+CREATE INDEX IF NOT EXISTS idx_session_void_requests_status ON session_void_requests(status);
@@ -0,0 +1,33 @@
+-- Migration: Add overstay limits to parking_areas and create overstay_events
+-- The overstay scheduler flags active sessions past max_duration or closing_time and either alerts the jukir or closes them (auto_closed)
+
+ALTER TABLE parking_areas
+ADD COLUMN IF NOT EXISTS max_duration INTEGER,
+ADD COLUMN IF NOT EXISTS closing_time VARCHAR(5),
+ADD COLUMN IF NOT EXISTS overstay_policy VARCHAR(20) NOT NULL DEFAULT 'alert';
+
+ALTER TABLE parking_areas
+DROP CONSTRAINT IF EXISTS chk_overstay_policy;
+
+ALTER TABLE parking_areas
+ADD CONSTRAINT chk_overstay_policy CHECK (overstay_policy IN ('alert', 'auto_complete'));
+
+ALTER TABLE parking_sessions
+ADD COLUMN IF NOT EXISTS auto_closed BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS overstay_events (
+    id BIGSERIAL PRIMARY KEY,
+    session_id BIGINT NOT NULL REFERENCES parking_sessions(id),
+    area_id BIGINT NOT NULL REFERENCES parking_areas(id),
+    jukir_id BIGINT REFERENCES jukirs(id),
+    reason VARCHAR(20) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    deadline TIMESTAMPTZ NOT NULL,
+    detected_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ,
+    CONSTRAINT chk_overstay_reason CHECK (reason IN ('max_duration', 'closing_time')),
+    CONSTRAINT chk_overstay_action CHECK (action IN ('alert', 'auto_completed'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_overstay_events_session_id ON overstay_events(session_id);
+CREATE INDEX IF NOT EXISTS idx_overstay_events_area_id ON overstay_events(area_id);
@@ -0,0 +1,19 @@
+-- Migration: Add lost ticket checkout
+-- Sessions closed without a ticket are flagged lost_ticket; the area's penalty is stored as a separate payment line (kind lost_ticket_penalty)
+
+ALTER TABLE parking_areas
+ADD COLUMN IF NOT EXISTS lost_ticket_penalty DECIMAL(10, 2) NOT NULL DEFAULT 0.00;
+
+ALTER TABLE parking_sessions
+ADD COLUMN IF NOT EXISTS lost_ticket BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE payments
+ADD COLUMN IF NOT EXISTS kind VARCHAR(30) NOT NULL DEFAULT 'parking';
+
+ALTER TABLE payments
+DROP CONSTRAINT IF EXISTS chk_payment_kind;
+
+ALTER TABLE payments
+ADD CONSTRAINT chk_payment_kind CHECK (kind IN ('parking', 'lost_ticket_penalty'));
+
+CREATE INDEX IF NOT EXISTS idx_payments_session_id_kind ON payments(session_id, kind);
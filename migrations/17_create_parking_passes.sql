@@ -0,0 +1,67 @@
+-- Migration: Create monthly pass (langganan) tables
+-- Pass products are sold per area or per region; sessions checked in with a plate holding a valid pass cost nothing and carry pass_id
+
+CREATE TABLE IF NOT EXISTS pass_products (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    area_id BIGINT REFERENCES parking_areas(id),
+    regional VARCHAR(50),
+    vehicle_type VARCHAR(20) NOT NULL,
+    duration_days INTEGER NOT NULL DEFAULT 30,
+    price DECIMAL(10, 2) NOT NULL DEFAULT 0.00,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT chk_pass_product_scope CHECK ((area_id IS NULL) <> (regional IS NULL)),
+    CONSTRAINT chk_pass_product_duration CHECK (duration_days > 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pass_products_area_id ON pass_products(area_id);
+CREATE INDEX IF NOT EXISTS idx_pass_products_regional ON pass_products(regional);
+
+CREATE TABLE IF NOT EXISTS parking_passes (
+    id BIGSERIAL PRIMARY KEY,
+    product_id BIGINT NOT NULL REFERENCES pass_products(id),
+    plat_nomor VARCHAR(20) NOT NULL,
+    vehicle_type VARCHAR(20) NOT NULL,
+    holder_name VARCHAR(255),
+    holder_phone VARCHAR(20),
+    valid_from TIMESTAMPTZ NOT NULL,
+    valid_until TIMESTAMPTZ NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'active',
+    issued_by BIGINT NOT NULL REFERENCES users(id),
+    suspended_at TIMESTAMPTZ,
+    suspend_reason TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT chk_parking_pass_status CHECK (status IN ('active', 'suspended')),
+    CONSTRAINT chk_parking_pass_period CHECK (valid_until > valid_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_parking_passes_product_id ON parking_passes(product_id);
+CREATE INDEX IF NOT EXISTS idx_parking_passes_plat_nomor ON parking_passes(plat_nomor);
+CREATE INDEX IF NOT EXISTS idx_parking_passes_valid_until ON parking_passes(valid_until);
+CREATE INDEX IF NOT EXISTS idx_parking_passes_status ON parking_passes(status);
+
+CREATE TABLE IF NOT EXISTS pass_purchases (
+    id BIGSERIAL PRIMARY KEY,
+    pass_id BIGINT NOT NULL REFERENCES parking_passes(id),
+    product_id BIGINT NOT NULL REFERENCES pass_products(id),
+    kind VARCHAR(10) NOT NULL,
+    amount DECIMAL(10, 2) NOT NULL,
+    payment_method VARCHAR(20) NOT NULL,
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    recorded_by BIGINT NOT NULL REFERENCES users(id),
+    created_at TIMESTAMPTZ,
+    CONSTRAINT chk_pass_purchase_kind CHECK (kind IN ('purchase', 'renewal')),
+    CONSTRAINT chk_pass_purchase_method CHECK (payment_method IN ('cash', 'qris', 'bank_transfer'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_pass_purchases_pass_id ON pass_purchases(pass_id);
+CREATE INDEX IF NOT EXISTS idx_pass_purchases_created_at ON pass_purchases(created_at);
+
+ALTER TABLE parking_sessions
+ADD COLUMN IF NOT EXISTS pass_id BIGINT REFERENCES parking_passes(id);
+
+CREATE INDEX IF NOT EXISTS idx_parking_sessions_pass_id ON parking_sessions(pass_id);
@@ -0,0 +1,26 @@
+-- Migration: Create reservations table
+-- A reserved row holds one vehicle-type slot in the area until a check-in consumes it, the customer cancels it, or it expires after the grace period
+
+CREATE TABLE IF NOT EXISTS reservations (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    area_id BIGINT NOT NULL REFERENCES parking_areas(id),
+    vehicle_type VARCHAR(20) NOT NULL,
+    plat_nomor VARCHAR(20) NOT NULL,
+    start_time TIMESTAMPTZ NOT NULL,
+    end_time TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'reserved',
+    session_id BIGINT REFERENCES parking_sessions(id),
+    closed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT chk_reservation_status CHECK (status IN ('reserved', 'consumed', 'expired', 'cancelled')),
+    CONSTRAINT chk_reservation_window CHECK (end_time > start_time)
+);
+
+CREATE INDEX IF NOT EXISTS idx_reservations_user_id ON reservations(user_id);
+CREATE INDEX IF NOT EXISTS idx_reservations_area_id ON reservations(area_id);
+CREATE INDEX IF NOT EXISTS idx_reservations_plat_nomor ON reservations(plat_nomor);
+CREATE INDEX IF NOT EXISTS idx_reservations_expires_at ON reservations(expires_at);
+CREATE INDEX IF NOT EXISTS idx_reservations_status ON reservations(status);
@@ -0,0 +1,16 @@
+-- Migration: Create session_attachments table
+-- Vehicle photos taken by the jukir at manual check-in and checkout; the files are stored in MinIO under sessions/
+
+CREATE TABLE IF NOT EXISTS session_attachments (
+    id BIGSERIAL PRIMARY KEY,
+    session_id BIGINT NOT NULL REFERENCES parking_sessions(id),
+    kind VARCHAR(10) NOT NULL,
+    object_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    uploaded_by BIGINT NOT NULL REFERENCES jukirs(id),
+    created_at TIMESTAMPTZ,
+    CONSTRAINT chk_session_attachment_kind CHECK (kind IN ('checkin', 'checkout'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_attachments_session_id ON session_attachments(session_id);
@@ -0,0 +1,17 @@
+-- Migration: Create session_receipts table
+-- E-karcis issued per parking session; the PDF and PNG are stored in MinIO under receipts/ and looked up by the public code
+
+CREATE TABLE IF NOT EXISTS session_receipts (
+    id BIGSERIAL PRIMARY KEY,
+    session_id BIGINT NOT NULL REFERENCES parking_sessions(id),
+    code VARCHAR(16) NOT NULL,
+    pdf_object VARCHAR(255) NOT NULL,
+    png_object VARCHAR(255) NOT NULL,
+    amount DECIMAL(10,2) NOT NULL,
+    issued_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_session_receipts_session_id ON session_receipts(session_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_session_receipts_code ON session_receipts(code);
@@ -0,0 +1,8 @@
+-- Migration: Add QR token rotation columns to jukirs
+-- The token replaced by the last rotation is still accepted until previous_qr_token_expires_at
+
+ALTER TABLE jukirs ADD COLUMN IF NOT EXISTS previous_qr_token VARCHAR(255);
+ALTER TABLE jukirs ADD COLUMN IF NOT EXISTS previous_qr_token_expires_at TIMESTAMPTZ;
+ALTER TABLE jukirs ADD COLUMN IF NOT EXISTS qr_rotated_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_jukirs_previous_qr_token ON jukirs(previous_qr_token);
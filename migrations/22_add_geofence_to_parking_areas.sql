@@ -0,0 +1,8 @@
+-- Migration: Add geofence columns to parking_areas
+-- geofence is a GeoJSON Polygon ([longitude, latitude] positions); without one, geofence_radius (meters) or the default radius applies
+
+ALTER TABLE parking_areas
+ADD COLUMN IF NOT EXISTS geofence JSONB;
+
+ALTER TABLE parking_areas
+ADD COLUMN IF NOT EXISTS geofence_radius DOUBLE PRECISION;
@@ -0,0 +1,7 @@
+-- Migration: Add geohash to parking_areas
+-- Prefix-indexed geohash of latitude/longitude for nearby searches; existing rows are filled in by the server at startup
+
+ALTER TABLE parking_areas
+ADD COLUMN IF NOT EXISTS geohash VARCHAR(12);
+
+CREATE INDEX IF NOT EXISTS idx_parking_areas_geohash ON parking_areas (geohash varchar_pattern_ops);
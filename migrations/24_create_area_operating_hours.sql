@@ -0,0 +1,32 @@
+-- Migration: Create area operating hours and schedule exceptions
+-- Weekly opening windows (HH:MM, WIB) per area plus dated exceptions; areas with hours_override keep status_operasional set by hand
+
+CREATE TABLE IF NOT EXISTS area_operating_hours (
+    id BIGSERIAL PRIMARY KEY,
+    area_id BIGINT NOT NULL REFERENCES parking_areas(id),
+    day_of_week INTEGER NOT NULL,
+    open_time VARCHAR(5) NOT NULL,
+    close_time VARCHAR(5) NOT NULL,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT chk_area_operating_hours_day CHECK (day_of_week BETWEEN 0 AND 6)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_area_operating_hours_area_day ON area_operating_hours(area_id, day_of_week);
+
+CREATE TABLE IF NOT EXISTS area_schedule_exceptions (
+    id BIGSERIAL PRIMARY KEY,
+    area_id BIGINT NOT NULL REFERENCES parking_areas(id),
+    date DATE NOT NULL,
+    is_closed BOOLEAN NOT NULL DEFAULT FALSE,
+    open_time VARCHAR(5),
+    close_time VARCHAR(5),
+    reason VARCHAR(255),
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_area_schedule_exceptions_area_date ON area_schedule_exceptions(area_id, date);
+
+ALTER TABLE parking_areas
+ADD COLUMN IF NOT EXISTS hours_override BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,7 @@
+-- Migration: Add user_id to parking_sessions
+-- Customer account a session is linked to (checked in or out with a customer token, or claimed with its ticket); NULL for anonymous sessions
+
+ALTER TABLE parking_sessions
+ADD COLUMN IF NOT EXISTS user_id BIGINT REFERENCES users(id);
+
+CREATE INDEX IF NOT EXISTS idx_parking_sessions_user_id ON parking_sessions (user_id);
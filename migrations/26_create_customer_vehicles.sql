@@ -0,0 +1,15 @@
+-- Migration: Create customer_vehicles
+-- Plates customers saved to their garage with a vehicle type and nickname
+
+CREATE TABLE IF NOT EXISTS customer_vehicles (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id),
+    plat_nomor VARCHAR(20) NOT NULL,
+    vehicle_type VARCHAR(20) NOT NULL,
+    nickname VARCHAR(50),
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_customer_vehicles_user_plate ON customer_vehicles(user_id, plat_nomor);
+CREATE INDEX IF NOT EXISTS idx_customer_vehicles_plat_nomor ON customer_vehicles(plat_nomor);
@@ -0,0 +1,11 @@
+-- Migration: Add online payment charge to payments
+-- Provider, charge reference, amount and QRIS payload of the pending gateway charge; NULL for cash payments
+
+ALTER TABLE payments
+ADD COLUMN IF NOT EXISTS gateway_provider VARCHAR(30),
+ADD COLUMN IF NOT EXISTS gateway_charge_id VARCHAR(100),
+ADD COLUMN IF NOT EXISTS charge_amount DECIMAL(10,2),
+ADD COLUMN IF NOT EXISTS qr_string TEXT,
+ADD COLUMN IF NOT EXISTS charge_expires_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_gateway_charge_id ON payments(gateway_charge_id);
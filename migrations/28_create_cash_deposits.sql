@@ -0,0 +1,19 @@
+-- Migration: Create cash_deposits
+-- Cash each jukir handed over to the office per day (setoran), with the expected cash from their paid cash payments, the variance and the admin who received it
+
+CREATE TABLE IF NOT EXISTS cash_deposits (
+    id BIGSERIAL PRIMARY KEY,
+    jukir_id BIGINT NOT NULL REFERENCES jukirs(id),
+    date DATE NOT NULL,
+    expected_amount DECIMAL(10, 2) NOT NULL,
+    deposited_amount DECIMAL(10, 2) NOT NULL,
+    variance DECIMAL(10, 2) NOT NULL,
+    received_by BIGINT NOT NULL REFERENCES users(id),
+    received_at TIMESTAMPTZ NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_cash_deposits_jukir_date ON cash_deposits(jukir_id, date);
+CREATE INDEX IF NOT EXISTS idx_cash_deposits_date ON cash_deposits(date);
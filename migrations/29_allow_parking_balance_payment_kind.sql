@@ -0,0 +1,8 @@
+-- Migration: Allow parking_balance payment lines
+-- The rest of the parking fee collected at checkout (cash or QRIS) and the unpaid fee of auto-closed sessions are stored as their own payment line (kind parking_balance)
+
+ALTER TABLE payments
+DROP CONSTRAINT IF EXISTS chk_payment_kind;
+
+ALTER TABLE payments
+ADD CONSTRAINT chk_payment_kind CHECK (kind IN ('parking', 'parking_balance', 'lost_ticket_penalty'));